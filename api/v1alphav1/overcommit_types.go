@@ -7,12 +7,55 @@ package v1alphav1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// CertificateMode selects how the TLS certificates of the webhooks are managed.
+// +kubebuilder:validation:Enum=CertManager;Builtin
+type CertificateMode string
+
+const (
+	// CertificateModeCertManager delegates the certificates to cert-manager Issuers and Certificates.
+	CertificateModeCertManager CertificateMode = "CertManager"
+	// CertificateModeBuiltin makes the operator generate a self-signed CA and serving certificates
+	// in Secrets, rotate them before expiry and inject the CA bundle into the webhook configurations.
+	CertificateModeBuiltin CertificateMode = "Builtin"
+)
+
+// ValidationMode selects how the pod validating webhook reports policy violations.
+// +kubebuilder:validation:Enum=Enforce;Warn
+type ValidationMode string
+
+const (
+	// ValidationModeEnforce rejects the pods violating the policy of their class.
+	ValidationModeEnforce ValidationMode = "Enforce"
+	// ValidationModeWarn admits the pods violating the policy of their class, returning the violations as warnings.
+	ValidationModeWarn ValidationMode = "Warn"
+)
+
+const (
+	// DefaultCpuWarningThreshold is the cpuOvercommit under which an OvercommitClass is reported as risky.
+	DefaultCpuWarningThreshold = 0.1
+	// DefaultMemoryWarningThreshold is the memoryOvercommit under which an OvercommitClass is reported as risky.
+	DefaultMemoryWarningThreshold = 0.5
+)
+
+// ClassWarningThresholds holds the ratios under which the OvercommitClass validating webhook warns about a class.
+type ClassWarningThresholds struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	CpuOvercommit float64 `json:"cpuOvercommit,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	MemoryOvercommit float64 `json:"memoryOvercommit,omitempty"`
+}
+
 // OvercommitSpec defines the desired state of Overcommit
 type OvercommitSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -29,18 +72,164 @@ type OvercommitSpec struct {
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	// +kubebuilder:validation:Optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// CertificateMode selects who manages the webhook certificates. Use Builtin on clusters without cert-manager.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=CertManager
+	CertificateMode CertificateMode `json:"certificateMode,omitempty"`
+	// Paused is an emergency kill switch: while set, the mutating webhook configurations of every class are
+	// removed and the webhooks stop changing pods.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Paused bool `json:"paused,omitempty"`
+	// ValidationMode selects whether the pods violating the policy of their class are rejected or only warned about.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Warn
+	ValidationMode ValidationMode `json:"validationMode,omitempty"`
+	// ClassWarningThresholds overrides the ratios under which creating or updating an OvercommitClass returns a warning.
+	// +kubebuilder:validation:Optional
+	ClassWarningThresholds *ClassWarningThresholds `json:"classWarningThresholds,omitempty"`
+	// Webhook tunes how the API server calls the pod and OvercommitClass validating webhooks.
+	// +kubebuilder:validation:Optional
+	Webhook *ValidatingWebhookSettings `json:"webhook,omitempty"`
+	// ResourceQuotas makes the ResourceQuotas of the namespaces aware of the overcommit of their class.
+	// +kubebuilder:validation:Optional
+	ResourceQuotas *ResourceQuotaPolicy `json:"resourceQuotas,omitempty"`
+	// Recommendations enables the recommendation of the ratios of the classes of the OvercommitRecommendations.
+	// +kubebuilder:validation:Optional
+	Recommendations *RecommendationPolicy `json:"recommendations,omitempty"`
+}
+
+// RecommendationPolicy configures the recommendation of the ratios of the classes from their observed usage.
+type RecommendationPolicy struct {
+	// Enabled runs the recommendation controller, which samples the usage of the pods of the classes of the
+	// OvercommitRecommendations.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// ResourceQuotaPolicy configures how the ResourceQuotas follow the overcommit of the class of their namespace.
+// The requests.* quotas are consumed at the ratios of the class while the limits.* quotas are not.
+type ResourceQuotaPolicy struct {
+	// Enabled annotates the ResourceQuotas with the class of their namespace and its ratios, and warns when
+	// the ratios of a class make the limits quotas of its namespaces unreachable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+	// ScaleRequests scales the requests.cpu and requests.memory quotas by the ratios of the class, keeping their
+	// original values in annotations, so they admit the same pods as before the overcommit.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	ScaleRequests bool `json:"scaleRequests,omitempty"`
+}
+
+// UsesBuiltinCertificates returns true when the operator manages the webhook certificates itself.
+func (o *Overcommit) UsesBuiltinCertificates() bool {
+	return o.Spec.CertificateMode == CertificateModeBuiltin
+}
+
+// WarnsOnly returns true when the pod policy violations are reported as warnings instead of rejections,
+// which is the case unless the Enforce mode is set.
+func (o *Overcommit) WarnsOnly() bool {
+	return o.Spec.ValidationMode != ValidationModeEnforce
+}
+
+// ResourceQuotasEnabled returns true when the ResourceQuotas follow the overcommit of their class.
+func (o *Overcommit) ResourceQuotasEnabled() bool {
+	return o != nil && o.Spec.ResourceQuotas != nil && o.Spec.ResourceQuotas.Enabled
+}
+
+// ManagesResourceQuotas returns true when the Overcommit sets a resourceQuotas policy, the quota controller
+// running to apply it or, once disabled, to restore the quotas.
+func (o *Overcommit) ManagesResourceQuotas() bool {
+	return o != nil && o.Spec.ResourceQuotas != nil
+}
+
+// ScalesRequestsQuotas returns true when the requests quotas are scaled by the ratios of their class.
+func (o *Overcommit) ScalesRequestsQuotas() bool {
+	return o.ResourceQuotasEnabled() && o.Spec.ResourceQuotas.ScaleRequests
+}
+
+// RecommendationsEnabled returns true when the ratios of the classes are recommended from their usage.
+func (o *Overcommit) RecommendationsEnabled() bool {
+	return o != nil && o.Spec.Recommendations != nil && o.Spec.Recommendations.Enabled
+}
+
+// WarningThresholds returns the cpu and memory ratios under which an OvercommitClass is reported as risky.
+func (o *Overcommit) WarningThresholds() (cpu, memory float64) {
+	cpu, memory = DefaultCpuWarningThreshold, DefaultMemoryWarningThreshold
+	if o == nil || o.Spec.ClassWarningThresholds == nil {
+		return cpu, memory
+	}
+	if o.Spec.ClassWarningThresholds.CpuOvercommit > 0 {
+		cpu = o.Spec.ClassWarningThresholds.CpuOvercommit
+	}
+	if o.Spec.ClassWarningThresholds.MemoryOvercommit > 0 {
+		memory = o.Spec.ClassWarningThresholds.MemoryOvercommit
+	}
+	return cpu, memory
+}
+
+// ResourceOvercommit compares the requests and limits of the pods scheduled on the nodes with their
+// allocatable resources, for a resource.
+type ResourceOvercommit struct {
+	Allocatable resource.Quantity `json:"allocatable"`
+	Requests    resource.Quantity `json:"requests"`
+	Limits      resource.Quantity `json:"limits"`
+	// RequestsRatio and LimitsRatio are the requests and limits against the allocatable resources, a
+	// LimitsRatio above 1 meaning the nodes are overcommitted.
+	RequestsRatio float64 `json:"requestsRatio"`
+	LimitsRatio   float64 `json:"limitsRatio"`
+	// MaxLimitsRatio is the LimitsRatio of the most overcommitted node, MaxLimitsNode.
+	MaxLimitsRatio float64 `json:"maxLimitsRatio"`
+	// +kubebuilder:validation:Optional
+	MaxLimitsNode string `json:"maxLimitsNode,omitempty"`
+}
+
+// ClassNodeOvercommit holds the share of the allocatable resources of the nodes requested and limited by the
+// pods of a class.
+type ClassNodeOvercommit struct {
+	Class string `json:"class"`
+	Pods  int32  `json:"pods"`
+	// +kubebuilder:validation:Optional
+	CpuRequestsRatio float64 `json:"cpuRequestsRatio,omitempty"`
+	// +kubebuilder:validation:Optional
+	CpuLimitsRatio float64 `json:"cpuLimitsRatio,omitempty"`
+	// +kubebuilder:validation:Optional
+	MemoryRequestsRatio float64 `json:"memoryRequestsRatio,omitempty"`
+	// +kubebuilder:validation:Optional
+	MemoryLimitsRatio float64 `json:"memoryLimitsRatio,omitempty"`
+}
+
+// NodeOvercommitStatus summarizes how overcommitted the nodes of the cluster are.
+type NodeOvercommitStatus struct {
+	Nodes  int32              `json:"nodes"`
+	Cpu    ResourceOvercommit `json:"cpu"`
+	Memory ResourceOvercommit `json:"memory"`
+	// Classes break the requests and limits down by the class that mutated the pods.
+	// +kubebuilder:validation:Optional
+	Classes []ClassNodeOvercommit `json:"classes,omitempty"`
+	// +kubebuilder:validation:Optional
+	LastScanTime *metav1.Time `json:"lastScanTime,omitempty"`
 }
 
 // OvercommitStatus defines the observed state of Overcommit
 type OvercommitStatus struct {
 	Resources  []ResourceStatus   `json:"resources,omitempty"`
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Webhook holds the settings of the pod validating webhook configuration found in the cluster.
+	Webhook *ValidatingWebhookSettings `json:"webhook,omitempty"`
+	// ClassWebhook holds the settings of the OvercommitClass validating webhook configuration found in the cluster.
+	ClassWebhook *ValidatingWebhookSettings `json:"classWebhook,omitempty"`
+	// Nodes compares the requests and limits of the pods with the allocatable resources of the nodes.
+	Nodes *NodeOvercommitStatus `json:"nodes,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Target Label",type=string,JSONPath=".spec.overcommitLabel",description="Label to apply to the pods to make overcommit"
+// +kubebuilder:printcolumn:name="Paused",type=boolean,JSONPath=".spec.paused",description="Overcommit mutation is paused"
 // +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="overcommit is a singleton, .metadata.name must be 'cluster'"
 
 // Overcommit is the Schema for the overcommits API
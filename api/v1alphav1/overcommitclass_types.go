@@ -6,10 +6,18 @@
 package v1alphav1
 
 import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// ForceDeleteAnnotation set to "true" on an OvercommitClass allows deleting it while still in use.
+	ForceDeleteAnnotation = "overcommit.inditex.dev/force-delete"
+	// AppliedAnnotation records on the mutated pods the OvercommitClass applied to them.
+	AppliedAnnotation = "overcommit.inditex.dev/applied"
+)
+
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
@@ -26,8 +34,13 @@ type OvercommitClassSpec struct {
 	// +kubebuilder:validation:Maximum=1
 	// +kubebuilder:validation:Required
 	MemoryOvercommit float64 `json:"memoryOvercommit,omitempty"`
-	// +kubebuilder:validation:Required
+	// ExcludedNamespaces is a regex matched against the namespace of the pods left untouched by the class.
+	// Prefer NamespaceExclusions, which are easier to get right.
+	// +kubebuilder:validation:Optional
 	ExcludedNamespaces string `json:"excludedNamespaces,omitempty"`
+	// NamespaceExclusions is a structured alternative to ExcludedNamespaces, both apply when set.
+	// +kubebuilder:validation:Optional
+	NamespaceExclusions *NamespaceExclusions `json:"namespaceExclusions,omitempty"`
 	// +kubebuilder:default=false
 	IsDefault   bool              `json:"isDefault,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
@@ -36,6 +49,214 @@ type OvercommitClassSpec struct {
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	// +kubebuilder:validation:Optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Suspended stops the mutation of the pods of this class, removing its mutating webhook configuration.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Suspended bool `json:"suspended,omitempty"`
+	// Deprecated flags the class as being phased out. New pods using it violate the pod policy.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Webhook tunes how the API server calls the mutating webhook of the class.
+	// +kubebuilder:validation:Optional
+	Webhook *WebhookSettings `json:"webhook,omitempty"`
+	// Rollout restarts the workloads whose pods run with outdated overcommit values after the class changes.
+	// +kubebuilder:validation:Optional
+	Rollout *RolloutPolicy `json:"rollout,omitempty"`
+	// InPlaceResize resizes the running pods of the class without restarting them after its ratios change.
+	// +kubebuilder:validation:Optional
+	InPlaceResize *InPlaceResizePolicy `json:"inPlaceResize,omitempty"`
+}
+
+// NamespaceExclusions lists the namespaces whose pods are left untouched by a class.
+type NamespaceExclusions struct {
+	// Names are the exact names of the excluded namespaces.
+	// +kubebuilder:validation:Optional
+	Names []string `json:"names,omitempty"`
+	// Globs are matched against the whole namespace name, '*' matching any sequence of characters and '?' a single one.
+	// +kubebuilder:validation:Optional
+	Globs []string `json:"globs,omitempty"`
+	// NamespaceSelector restricts the class to the namespaces whose labels match it, the others are excluded.
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// WebhookSettings tunes how the API server calls an admission webhook generated by the operator.
+type WebhookSettings struct {
+	// FailurePolicy defines what happens when the webhook cannot be called: Fail rejects the request,
+	// Ignore admits it unmodified.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Fail;Ignore
+	// +kubebuilder:default=Fail
+	FailurePolicy admissionregistrationv1.FailurePolicyType `json:"failurePolicy,omitempty"`
+	// TimeoutSeconds is how long the API server waits for the webhook before applying the failure policy.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=30
+	// +kubebuilder:default=10
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// ReinvocationPolicy defines whether the mutating webhook is called again when later webhooks change the pod.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Never;IfNeeded
+	// +kubebuilder:default=IfNeeded
+	ReinvocationPolicy admissionregistrationv1.ReinvocationPolicyType `json:"reinvocationPolicy,omitempty"`
+}
+
+// WithDefaults returns the settings with the unset fields defaulted, w may be nil.
+func (w *WebhookSettings) WithDefaults() WebhookSettings {
+	settings := WebhookSettings{
+		FailurePolicy:      admissionregistrationv1.Fail,
+		TimeoutSeconds:     10,
+		ReinvocationPolicy: admissionregistrationv1.IfNeededReinvocationPolicy,
+	}
+	if w == nil {
+		return settings
+	}
+	if w.FailurePolicy != "" {
+		settings.FailurePolicy = w.FailurePolicy
+	}
+	if w.TimeoutSeconds != 0 {
+		settings.TimeoutSeconds = w.TimeoutSeconds
+	}
+	if w.ReinvocationPolicy != "" {
+		settings.ReinvocationPolicy = w.ReinvocationPolicy
+	}
+	return settings
+}
+
+// ValidatingWebhookSettings tunes how the API server calls a validating webhook generated by the operator.
+type ValidatingWebhookSettings struct {
+	// FailurePolicy defines what happens when the webhook cannot be called: Fail rejects the request,
+	// Ignore admits it. Unset, it is Fail for the OvercommitClass webhook and Ignore for the pod webhook.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Fail;Ignore
+	FailurePolicy admissionregistrationv1.FailurePolicyType `json:"failurePolicy,omitempty"`
+	// TimeoutSeconds is how long the API server waits for the webhook before applying the failure policy.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=30
+	// +kubebuilder:default=10
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// WithDefaults returns the settings with the unset fields defaulted, the failure policy to failurePolicy, w may be
+// nil.
+func (w *ValidatingWebhookSettings) WithDefaults(failurePolicy admissionregistrationv1.FailurePolicyType) ValidatingWebhookSettings {
+	settings := ValidatingWebhookSettings{
+		FailurePolicy:  failurePolicy,
+		TimeoutSeconds: 10,
+	}
+	if w == nil {
+		return settings
+	}
+	if w.FailurePolicy != "" {
+		settings.FailurePolicy = w.FailurePolicy
+	}
+	if w.TimeoutSeconds != 0 {
+		settings.TimeoutSeconds = w.TimeoutSeconds
+	}
+	return settings
+}
+
+// RolloutWorkloadAnnotation set to "true" or "false" on a Deployment, StatefulSet or DaemonSet opts it in or out
+// of the rolling restarts, whatever the rollout policy of its class.
+const RolloutWorkloadAnnotation = "overcommit.inditex.dev/rollout"
+
+// RolloutPolicy configures the rolling restarts of the workloads whose pods run with outdated overcommit values.
+type RolloutPolicy struct {
+	// Enabled restarts every Deployment, StatefulSet and DaemonSet of the class not opted out.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxConcurrent is the number of workloads of the class restarted at the same time.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
+	// MaintenanceWindow restricts the restarts to a daily window, they can happen at any time when unset.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// MaintenanceWindow is a daily time window, in UTC. A window ending before it starts spans midnight.
+type MaintenanceWindow struct {
+	// Start is the time of day the window opens, as HH:MM.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// End is the time of day the window closes, as HH:MM.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+}
+
+// WorkloadReference identifies a workload restarted by the rollout.
+type WorkloadReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// RolloutStatus reports the progress of the rolling restarts of the workloads of a class.
+type RolloutStatus struct {
+	// ObservedGeneration is the generation of the class the workloads are restarted for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// OutdatedWorkloads is the number of workloads with pods running with outdated overcommit values.
+	OutdatedWorkloads int32 `json:"outdatedWorkloads"`
+	// RestartedWorkloads is the number of workloads restarted for the observed generation.
+	RestartedWorkloads int32 `json:"restartedWorkloads"`
+	// Restarting are the workloads whose restart is in progress.
+	Restarting []WorkloadReference `json:"restarting,omitempty"`
+	// LastRestartTime is when the last restart was triggered.
+	LastRestartTime *metav1.Time `json:"lastRestartTime,omitempty"`
+}
+
+// InPlaceResizePolicy configures the in-place resize of the running pods of a class after a change of its ratios.
+type InPlaceResizePolicy struct {
+	// Enabled resizes the running pods of the class through the pods/resize subresource. The workloads of the
+	// class are then not restarted by the rollout.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+	// PodsPerMinute is the number of pods of the class resized per minute.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	PodsPerMinute int32 `json:"podsPerMinute,omitempty"`
+}
+
+// InPlaceResizeEnabled reports whether the running pods of the class are resized in place.
+func (c *OvercommitClass) InPlaceResizeEnabled() bool {
+	return c.Spec.InPlaceResize != nil && c.Spec.InPlaceResize.Enabled
+}
+
+// InPlaceResizeStatus reports the in-place resize of the running pods of a class.
+type InPlaceResizeStatus struct {
+	// ObservedGeneration is the generation of the class the pods are resized for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// OutdatedPods is the number of running pods with outdated overcommit values.
+	OutdatedPods int32 `json:"outdatedPods"`
+	// ResizedPods is the number of pods resized for the observed generation.
+	ResizedPods int32 `json:"resizedPods"`
+	// RestartRequiredPods is the number of outdated pods left untouched because the resize policy of one
+	// of their containers requires a restart.
+	RestartRequiredPods int32 `json:"restartRequiredPods"`
+	// LastResizeTime is when the last pod was resized.
+	LastResizeTime *metav1.Time `json:"lastResizeTime,omitempty"`
+}
+
+// DriftStatus summarizes how many of the pods of a class reflect its current values.
+type DriftStatus struct {
+	// CurrentPods is the number of pods mutated by the class with its current ratios.
+	CurrentPods int32 `json:"currentPods"`
+	// OutdatedPods is the number of pods mutated by the class with other ratios than its current ones.
+	OutdatedPods int32 `json:"outdatedPods"`
+	// OrphanedPods is the number of pods in scope of the class mutated by a class that no longer exists.
+	OrphanedPods int32 `json:"orphanedPods"`
+	// UnmutatedPods is the number of pods in scope of the class that were never mutated.
+	UnmutatedPods int32 `json:"unmutatedPods"`
+	// LastScanTime is when the pods were last compared with the class.
+	LastScanTime *metav1.Time `json:"lastScanTime,omitempty"`
 }
 
 type ResourceStatus struct {
@@ -49,6 +270,14 @@ type OvercommitClassStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	Resources  []ResourceStatus   `json:"resources,omitempty"`
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Webhook holds the settings of the mutating webhook configuration found in the cluster.
+	Webhook *WebhookSettings `json:"webhook,omitempty"`
+	// Rollout reports the rolling restarts of the workloads of the class, when enabled.
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// Drift reports how many of the pods of the class reflect its current values.
+	Drift *DriftStatus `json:"drift,omitempty"`
+	// InPlaceResize reports the in-place resize of the running pods of the class, when enabled.
+	InPlaceResize *InPlaceResizeStatus `json:"inPlaceResize,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -57,6 +286,7 @@ type OvercommitClassStatus struct {
 // +kubebuilder:printcolumn:name="CPU",type=number,JSONPath=".spec.cpuOvercommit",description="CPU overcommit ratio"
 // +kubebuilder:printcolumn:name="Memory",type=number,JSONPath=".spec.memoryOvercommit",description="Memory overcommit ratio"
 // +kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=".spec.isDefault",description="Is default overcommit class"
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=".spec.suspended",description="Overcommit mutation is suspended for the class"
 
 // OvercommitClass is the Schema for the overcommitclasses API
 type OvercommitClass struct {
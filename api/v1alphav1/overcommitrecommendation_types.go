@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UsageSource selects where the usage of the containers is read from.
+// +kubebuilder:validation:Enum=MetricsAPI;Prometheus
+type UsageSource string
+
+const (
+	// UsageSourceMetricsAPI samples the current usage from the metrics.k8s.io API served by metrics-server.
+	// The samples are kept in the memory of the operator, they are lost when it restarts.
+	UsageSourceMetricsAPI UsageSource = "MetricsAPI"
+	// UsageSourcePrometheus reads the usage over the whole window from a Prometheus-compatible HTTP API.
+	UsageSourcePrometheus UsageSource = "Prometheus"
+)
+
+// RecommendationScope selects what the ratios are recommended for.
+// +kubebuilder:validation:Enum=Class;Owner
+type RecommendationScope string
+
+const (
+	// RecommendationScopeClass recommends the ratios of the class.
+	RecommendationScopeClass RecommendationScope = "Class"
+	// RecommendationScopeOwner also recommends ratios for each owner of the pods of the class.
+	RecommendationScopeOwner RecommendationScope = "Owner"
+)
+
+// Condition types of an OvercommitRecommendation.
+const (
+	// RecommendationConditionReady reports whether ratios could be recommended from the observed usage.
+	RecommendationConditionReady = "Ready"
+	// RecommendationConditionApplied reports whether the recommended ratios were applied to the class.
+	RecommendationConditionApplied = "Applied"
+)
+
+// PrometheusSource is a Prometheus-compatible HTTP API exposing the cAdvisor metrics of the containers.
+type PrometheusSource struct {
+	// URL of the API, like http://prometheus.monitoring:9090.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+}
+
+// AutoApplyPolicy applies the recommended ratios to the class, within bounds.
+// +kubebuilder:validation:XValidation:rule="self.minCpuOvercommit <= self.maxCpuOvercommit",message="minCpuOvercommit must not be greater than maxCpuOvercommit"
+// +kubebuilder:validation:XValidation:rule="self.minMemoryOvercommit <= self.maxMemoryOvercommit",message="minMemoryOvercommit must not be greater than maxMemoryOvercommit"
+type AutoApplyPolicy struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0.0001
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:default=0.1
+	MinCpuOvercommit float64 `json:"minCpuOvercommit,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0.0001
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:default=1
+	MaxCpuOvercommit float64 `json:"maxCpuOvercommit,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0.0001
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:default=0.5
+	MinMemoryOvercommit float64 `json:"minMemoryOvercommit,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0.0001
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:default=1
+	MaxMemoryOvercommit float64 `json:"maxMemoryOvercommit,omitempty"`
+}
+
+// OvercommitRecommendationSpec defines the desired state of OvercommitRecommendation
+// +kubebuilder:validation:XValidation:rule="self.source != 'Prometheus' || has(self.prometheus)",message="prometheus is required when the source is Prometheus"
+type OvercommitRecommendationSpec struct {
+	// ClassName is the OvercommitClass whose pods are observed.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ClassName string `json:"className"`
+	// Source selects where the usage of the containers is read from.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=MetricsAPI
+	Source UsageSource `json:"source,omitempty"`
+	// Prometheus is the API read when the source is Prometheus.
+	// +kubebuilder:validation:Optional
+	Prometheus *PrometheusSource `json:"prometheus,omitempty"`
+	// Percentile of the usage samples kept under the requests computed with the recommended ratios.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=95
+	Percentile int32 `json:"percentile,omitempty"`
+	// Window is how far back the usage is observed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="24h"
+	Window metav1.Duration `json:"window,omitempty"`
+	// Scope selects whether ratios are also recommended for each owner of the pods of the class.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Class
+	Scope RecommendationScope `json:"scope,omitempty"`
+	// AutoApply applies the ratios recommended for the class to it.
+	// +kubebuilder:validation:Optional
+	AutoApply *AutoApplyPolicy `json:"autoApply,omitempty"`
+}
+
+// AutoApplyEnabled returns true when the recommended ratios are applied to the class.
+func (r *OvercommitRecommendation) AutoApplyEnabled() bool {
+	return r.Spec.AutoApply != nil && r.Spec.AutoApply.Enabled
+}
+
+// OwnerRecommendation holds the ratios recommended for the pods of an owner.
+type OwnerRecommendation struct {
+	// Kind, Namespace and Name identify the owner, a ReplicaSet being reported as its Deployment.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// +kubebuilder:validation:Optional
+	CpuOvercommit float64 `json:"cpuOvercommit,omitempty"`
+	// +kubebuilder:validation:Optional
+	MemoryOvercommit float64 `json:"memoryOvercommit,omitempty"`
+	// Samples is the number of usage samples the ratios were computed from.
+	Samples int32 `json:"samples"`
+}
+
+// OvercommitRecommendationStatus defines the observed state of OvercommitRecommendation
+type OvercommitRecommendationStatus struct {
+	// CpuOvercommit and MemoryOvercommit are the ratios recommended for the class, unset without samples.
+	// +kubebuilder:validation:Optional
+	CpuOvercommit float64 `json:"cpuOvercommit,omitempty"`
+	// +kubebuilder:validation:Optional
+	MemoryOvercommit float64 `json:"memoryOvercommit,omitempty"`
+	// Samples is the number of usage samples the ratios were computed from.
+	// +kubebuilder:validation:Optional
+	Samples int32 `json:"samples,omitempty"`
+	// Owners are the ratios recommended for each owner of the pods, with the Owner scope.
+	// +kubebuilder:validation:Optional
+	Owners []OwnerRecommendation `json:"owners,omitempty"`
+	// +kubebuilder:validation:Optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// LastAppliedTime is when the recommended ratios were last applied to the class.
+	// +kubebuilder:validation:Optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Class",type=string,JSONPath=".spec.className",description="OvercommitClass observed"
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=".spec.source",description="Source of the usage"
+// +kubebuilder:printcolumn:name="CPU",type=number,JSONPath=".status.cpuOvercommit",description="Recommended CPU ratio"
+// +kubebuilder:printcolumn:name="Memory",type=number,JSONPath=".status.memoryOvercommit",description="Recommended memory ratio"
+// +kubebuilder:printcolumn:name="Auto Apply",type=boolean,JSONPath=".spec.autoApply.enabled",description="Recommended ratios applied to the class"
+
+// OvercommitRecommendation is the Schema for the overcommitrecommendations API
+type OvercommitRecommendation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OvercommitRecommendationSpec   `json:"spec,omitempty"`
+	Status OvercommitRecommendationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OvercommitRecommendationList contains a list of OvercommitRecommendation
+type OvercommitRecommendationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OvercommitRecommendation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OvercommitRecommendation{}, &OvercommitRecommendationList{})
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultReportName is the name of the OvercommitReport created by the operator.
+const DefaultReportName = "cluster"
+
+// ReportRanking selects the resource saved by which the top workloads of a report are ranked.
+// +kubebuilder:validation:Enum=CPU;Memory
+type ReportRanking string
+
+const (
+	ReportRankingCPU    ReportRanking = "CPU"
+	ReportRankingMemory ReportRanking = "Memory"
+)
+
+// OvercommitReportSpec defines the desired state of OvercommitReport
+type OvercommitReportSpec struct {
+	// TopWorkloads is the number of workloads saving the most resources that are reported.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	TopWorkloads int32 `json:"topWorkloads,omitempty"`
+	// RankBy is the resource saved by which the top workloads are ranked.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Memory
+	RankBy ReportRanking `json:"rankBy,omitempty"`
+}
+
+// ResourceSavings compares the limits of a set of containers with their requests after the mutation.
+type ResourceSavings struct {
+	Limits   resource.Quantity `json:"limits"`
+	Requests resource.Quantity `json:"requests"`
+	// Saved is the resource the containers with limits would request on top of their requests if their
+	// requests were equal to their limits.
+	Saved resource.Quantity `json:"saved"`
+}
+
+// Savings sums the resources saved by the overcommit of a set of pods.
+type Savings struct {
+	Pods   int32           `json:"pods"`
+	Cpu    ResourceSavings `json:"cpu"`
+	Memory ResourceSavings `json:"memory"`
+}
+
+// ClassSavings are the resources saved by the pods mutated by a class.
+type ClassSavings struct {
+	Class   string `json:"class"`
+	Savings `json:",inline"`
+}
+
+// NamespaceSavings are the resources saved by the mutated pods of a namespace.
+type NamespaceSavings struct {
+	Namespace string `json:"namespace"`
+	Savings   `json:",inline"`
+}
+
+// WorkloadSavings are the resources saved by the mutated pods of a workload.
+type WorkloadSavings struct {
+	// Kind, Namespace and Name identify the controller of the pods, a ReplicaSet being reported as its Deployment.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Class is the class that mutated the pods, the one of most of them when they were mutated by several.
+	Class   string `json:"class"`
+	Savings `json:",inline"`
+}
+
+// OvercommitReportStatus defines the observed state of OvercommitReport
+type OvercommitReportStatus struct {
+	// Total are the resources saved by every mutated pod of the cluster.
+	// +kubebuilder:validation:Optional
+	Total *Savings `json:"total,omitempty"`
+	// Classes are sorted by name.
+	// +kubebuilder:validation:Optional
+	Classes []ClassSavings `json:"classes,omitempty"`
+	// Namespaces are sorted by name.
+	// +kubebuilder:validation:Optional
+	Namespaces []NamespaceSavings `json:"namespaces,omitempty"`
+	// TopWorkloads are the workloads saving the most of the rankBy resource, in decreasing order.
+	// +kubebuilder:validation:Optional
+	TopWorkloads []WorkloadSavings `json:"topWorkloads,omitempty"`
+	// +kubebuilder:validation:Optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Pods",type=integer,JSONPath=".status.total.pods",description="Mutated pods"
+// +kubebuilder:printcolumn:name="CPU Saved",type=string,JSONPath=".status.total.cpu.saved",description="CPU saved by the overcommit"
+// +kubebuilder:printcolumn:name="Memory Saved",type=string,JSONPath=".status.total.memory.saved",description="Memory saved by the overcommit"
+// +kubebuilder:printcolumn:name="Updated",type=date,JSONPath=".status.lastUpdateTime",description="Last time the report was regenerated"
+
+// OvercommitReport is the Schema for the overcommitreports API
+type OvercommitReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OvercommitReportSpec   `json:"spec,omitempty"`
+	Status OvercommitReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OvercommitReportList contains a list of OvercommitReport
+type OvercommitReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OvercommitReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OvercommitReport{}, &OvercommitReportList{})
+}
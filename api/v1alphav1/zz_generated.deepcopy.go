@@ -26,6 +26,207 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoApplyPolicy) DeepCopyInto(out *AutoApplyPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoApplyPolicy.
+func (in *AutoApplyPolicy) DeepCopy() *AutoApplyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoApplyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClassNodeOvercommit) DeepCopyInto(out *ClassNodeOvercommit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClassNodeOvercommit.
+func (in *ClassNodeOvercommit) DeepCopy() *ClassNodeOvercommit {
+	if in == nil {
+		return nil
+	}
+	out := new(ClassNodeOvercommit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClassSavings) DeepCopyInto(out *ClassSavings) {
+	*out = *in
+	in.Savings.DeepCopyInto(&out.Savings)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClassSavings.
+func (in *ClassSavings) DeepCopy() *ClassSavings {
+	if in == nil {
+		return nil
+	}
+	out := new(ClassSavings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClassWarningThresholds) DeepCopyInto(out *ClassWarningThresholds) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClassWarningThresholds.
+func (in *ClassWarningThresholds) DeepCopy() *ClassWarningThresholds {
+	if in == nil {
+		return nil
+	}
+	out := new(ClassWarningThresholds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftStatus) DeepCopyInto(out *DriftStatus) {
+	*out = *in
+	if in.LastScanTime != nil {
+		in, out := &in.LastScanTime, &out.LastScanTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftStatus.
+func (in *DriftStatus) DeepCopy() *DriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InPlaceResizePolicy) DeepCopyInto(out *InPlaceResizePolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceResizePolicy.
+func (in *InPlaceResizePolicy) DeepCopy() *InPlaceResizePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(InPlaceResizePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InPlaceResizeStatus) DeepCopyInto(out *InPlaceResizeStatus) {
+	*out = *in
+	if in.LastResizeTime != nil {
+		in, out := &in.LastResizeTime, &out.LastResizeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceResizeStatus.
+func (in *InPlaceResizeStatus) DeepCopy() *InPlaceResizeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InPlaceResizeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceExclusions) DeepCopyInto(out *NamespaceExclusions) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Globs != nil {
+		in, out := &in.Globs, &out.Globs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceExclusions.
+func (in *NamespaceExclusions) DeepCopy() *NamespaceExclusions {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceExclusions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSavings) DeepCopyInto(out *NamespaceSavings) {
+	*out = *in
+	in.Savings.DeepCopyInto(&out.Savings)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSavings.
+func (in *NamespaceSavings) DeepCopy() *NamespaceSavings {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSavings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOvercommitStatus) DeepCopyInto(out *NodeOvercommitStatus) {
+	*out = *in
+	in.Cpu.DeepCopyInto(&out.Cpu)
+	in.Memory.DeepCopyInto(&out.Memory)
+	if in.Classes != nil {
+		in, out := &in.Classes, &out.Classes
+		*out = make([]ClassNodeOvercommit, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScanTime != nil {
+		in, out := &in.LastScanTime, &out.LastScanTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOvercommitStatus.
+func (in *NodeOvercommitStatus) DeepCopy() *NodeOvercommitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOvercommitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Overcommit) DeepCopyInto(out *Overcommit) {
 	*out = *in
@@ -115,6 +316,11 @@ func (in *OvercommitClassList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OvercommitClassSpec) DeepCopyInto(out *OvercommitClassSpec) {
 	*out = *in
+	if in.NamespaceExclusions != nil {
+		in, out := &in.NamespaceExclusions, &out.NamespaceExclusions
+		*out = new(NamespaceExclusions)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -143,6 +349,21 @@ func (in *OvercommitClassSpec) DeepCopyInto(out *OvercommitClassSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookSettings)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InPlaceResize != nil {
+		in, out := &in.InPlaceResize, &out.InPlaceResize
+		*out = new(InPlaceResizePolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitClassSpec.
@@ -170,6 +391,26 @@ func (in *OvercommitClassStatus) DeepCopyInto(out *OvercommitClassStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookSettings)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(DriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InPlaceResize != nil {
+		in, out := &in.InPlaceResize, &out.InPlaceResize
+		*out = new(InPlaceResizeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitClassStatus.
@@ -214,6 +455,245 @@ func (in *OvercommitList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitRecommendation) DeepCopyInto(out *OvercommitRecommendation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitRecommendation.
+func (in *OvercommitRecommendation) DeepCopy() *OvercommitRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OvercommitRecommendation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitRecommendationList) DeepCopyInto(out *OvercommitRecommendationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OvercommitRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitRecommendationList.
+func (in *OvercommitRecommendationList) DeepCopy() *OvercommitRecommendationList {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitRecommendationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OvercommitRecommendationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitRecommendationSpec) DeepCopyInto(out *OvercommitRecommendationSpec) {
+	*out = *in
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusSource)
+		**out = **in
+	}
+	out.Window = in.Window
+	if in.AutoApply != nil {
+		in, out := &in.AutoApply, &out.AutoApply
+		*out = new(AutoApplyPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitRecommendationSpec.
+func (in *OvercommitRecommendationSpec) DeepCopy() *OvercommitRecommendationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitRecommendationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitRecommendationStatus) DeepCopyInto(out *OvercommitRecommendationStatus) {
+	*out = *in
+	if in.Owners != nil {
+		in, out := &in.Owners, &out.Owners
+		*out = make([]OwnerRecommendation, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitRecommendationStatus.
+func (in *OvercommitRecommendationStatus) DeepCopy() *OvercommitRecommendationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitRecommendationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitReport) DeepCopyInto(out *OvercommitReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitReport.
+func (in *OvercommitReport) DeepCopy() *OvercommitReport {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OvercommitReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitReportList) DeepCopyInto(out *OvercommitReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OvercommitReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitReportList.
+func (in *OvercommitReportList) DeepCopy() *OvercommitReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OvercommitReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitReportSpec) DeepCopyInto(out *OvercommitReportSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitReportSpec.
+func (in *OvercommitReportSpec) DeepCopy() *OvercommitReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvercommitReportStatus) DeepCopyInto(out *OvercommitReportStatus) {
+	*out = *in
+	if in.Total != nil {
+		in, out := &in.Total, &out.Total
+		*out = new(Savings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Classes != nil {
+		in, out := &in.Classes, &out.Classes
+		*out = make([]ClassSavings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]NamespaceSavings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopWorkloads != nil {
+		in, out := &in.TopWorkloads, &out.TopWorkloads
+		*out = make([]WorkloadSavings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitReportStatus.
+func (in *OvercommitReportStatus) DeepCopy() *OvercommitReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OvercommitReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OvercommitSpec) DeepCopyInto(out *OvercommitSpec) {
 	*out = *in
@@ -245,6 +725,26 @@ func (in *OvercommitSpec) DeepCopyInto(out *OvercommitSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ClassWarningThresholds != nil {
+		in, out := &in.ClassWarningThresholds, &out.ClassWarningThresholds
+		*out = new(ClassWarningThresholds)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(ValidatingWebhookSettings)
+		**out = **in
+	}
+	if in.ResourceQuotas != nil {
+		in, out := &in.ResourceQuotas, &out.ResourceQuotas
+		*out = new(ResourceQuotaPolicy)
+		**out = **in
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = new(RecommendationPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitSpec.
@@ -272,6 +772,21 @@ func (in *OvercommitStatus) DeepCopyInto(out *OvercommitStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(ValidatingWebhookSettings)
+		**out = **in
+	}
+	if in.ClassWebhook != nil {
+		in, out := &in.ClassWebhook, &out.ClassWebhook
+		*out = new(ValidatingWebhookSettings)
+		**out = **in
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(NodeOvercommitStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvercommitStatus.
@@ -284,6 +799,102 @@ func (in *OvercommitStatus) DeepCopy() *OvercommitStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OwnerRecommendation) DeepCopyInto(out *OwnerRecommendation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OwnerRecommendation.
+func (in *OwnerRecommendation) DeepCopy() *OwnerRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(OwnerRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusSource) DeepCopyInto(out *PrometheusSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusSource.
+func (in *PrometheusSource) DeepCopy() *PrometheusSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecommendationPolicy) DeepCopyInto(out *RecommendationPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommendationPolicy.
+func (in *RecommendationPolicy) DeepCopy() *RecommendationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RecommendationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceOvercommit) DeepCopyInto(out *ResourceOvercommit) {
+	*out = *in
+	out.Allocatable = in.Allocatable.DeepCopy()
+	out.Requests = in.Requests.DeepCopy()
+	out.Limits = in.Limits.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceOvercommit.
+func (in *ResourceOvercommit) DeepCopy() *ResourceOvercommit {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceOvercommit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaPolicy) DeepCopyInto(out *ResourceQuotaPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaPolicy.
+func (in *ResourceQuotaPolicy) DeepCopy() *ResourceQuotaPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSavings) DeepCopyInto(out *ResourceSavings) {
+	*out = *in
+	out.Limits = in.Limits.DeepCopy()
+	out.Requests = in.Requests.DeepCopy()
+	out.Saved = in.Saved.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSavings.
+func (in *ResourceSavings) DeepCopy() *ResourceSavings {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSavings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
 	*out = *in
@@ -298,3 +909,125 @@ func (in *ResourceStatus) DeepCopy() *ResourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutPolicy) DeepCopyInto(out *RolloutPolicy) {
+	*out = *in
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPolicy.
+func (in *RolloutPolicy) DeepCopy() *RolloutPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.Restarting != nil {
+		in, out := &in.Restarting, &out.Restarting
+		*out = make([]WorkloadReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastRestartTime != nil {
+		in, out := &in.LastRestartTime, &out.LastRestartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Savings) DeepCopyInto(out *Savings) {
+	*out = *in
+	in.Cpu.DeepCopyInto(&out.Cpu)
+	in.Memory.DeepCopyInto(&out.Memory)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Savings.
+func (in *Savings) DeepCopy() *Savings {
+	if in == nil {
+		return nil
+	}
+	out := new(Savings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidatingWebhookSettings) DeepCopyInto(out *ValidatingWebhookSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidatingWebhookSettings.
+func (in *ValidatingWebhookSettings) DeepCopy() *ValidatingWebhookSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidatingWebhookSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookSettings) DeepCopyInto(out *WebhookSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSettings.
+func (in *WebhookSettings) DeepCopy() *WebhookSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReference) DeepCopyInto(out *WorkloadReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReference.
+func (in *WorkloadReference) DeepCopy() *WorkloadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSavings) DeepCopyInto(out *WorkloadSavings) {
+	*out = *in
+	in.Savings.DeepCopyInto(&out.Savings)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSavings.
+func (in *WorkloadSavings) DeepCopy() *WorkloadSavings {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSavings)
+	in.DeepCopyInto(out)
+	return out
+}
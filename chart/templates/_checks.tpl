@@ -1,6 +1,6 @@
 {{- define "checks" -}}
 {{- $kubeVersion := lookup "v1" "Namespace" "" "kube-system" }}
-{{- if $kubeVersion }}
+{{- if and $kubeVersion (ne (.Values.overcommit.certificateMode | default "CertManager") "Builtin") }}
 {{- $certCRD := lookup "apiextensions.k8s.io/v1" "CustomResourceDefinition" "" "certificates.cert-manager.io" }}
 {{- if not $certCRD }}
 {{- fail "Required CRD 'certificates.cert-manager.io' not found in the cluster. Please install cert-manager first." }}
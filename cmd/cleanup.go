@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"flag"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+)
+
+// runCleanup implements the cleanup subcommand. It stops the operator, removes every object generated by it
+// and releases the Overcommit, so the operator can be uninstalled without leaving webhooks behind.
+func runCleanup(args []string) error {
+	fs := flag.NewFlagSet("cleanup", flag.ExitOnError)
+	operatorConfig := config.FromEnv()
+	operatorConfig.BindFlags(fs)
+	var operatorDeployment string
+	fs.StringVar(&operatorDeployment, "operator-deployment", "k8s-overcommit-operator",
+		"Name of the operator deployment, scaled down before the cleanup.")
+	opts := zap.Options{
+		Development: true,
+	}
+	opts.BindFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	if operatorConfig.PodNamespace == "" {
+		return errors.New("pod namespace is not set (--pod-namespace or POD_NAMESPACE)")
+	}
+
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+	// Nothing may recreate the generated objects or the finalizer once they are removed
+	setupLog.Info("stopping the operator", "deployment", operatorDeployment, "namespace", operatorConfig.PodNamespace)
+	if err := utils.StopDeployments(ctx, k8sClient, operatorConfig.PodNamespace, operatorDeployment); err != nil {
+		return err
+	}
+	setupLog.Info("removing the resources generated by the operator", "namespace", operatorConfig.PodNamespace)
+	if err := utils.DeleteResources(ctx, k8sClient, operatorConfig.PodNamespace); err != nil {
+		return err
+	}
+	return utils.RemoveCleanupFinalizer(ctx, k8sClient)
+}
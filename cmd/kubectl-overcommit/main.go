@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Command kubectl-overcommit is the kubectl overcommit plugin. Installed in the PATH, it is run as
+// "kubectl overcommit" and answers how the overcommit applies to the pods of the current cluster.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/plugin"
+)
+
+const usage = `kubectl overcommit explains how the overcommit applies to the pods of the cluster.
+
+Usage:
+  kubectl overcommit explain <pod> [-n namespace]       Explain how a pod was mutated and how it would be now
+  kubectl overcommit classes                            List the classes with their settings and usage
+  kubectl overcommit namespaces                         List the class every namespace resolves to, and why
+  kubectl overcommit simulate -f <file> [-n namespace]  Simulate the admission of the pods of manifests
+
+Flags:
+  --kubeconfig string      Path to the kubeconfig file
+  --context string         Name of the kubeconfig context to use
+  -n, --namespace string   Namespace of the pod, or of the manifests without one
+  -f, --filename string    Manifests of the pods to simulate, - reads them from the standard input
+`
+
+// options are the flags shared by the subcommands.
+type options struct {
+	kubeconfig string
+	context    string
+	namespace  string
+	filename   string
+}
+
+func main() {
+	// The logs of the mutation are not part of the answers
+	ctrl.SetLogger(zap.New(zap.WriteTo(io.Discard)))
+
+	if err := run(context.Background(), os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Print(usage)
+		return nil
+	}
+	command := args[0]
+	if command != "explain" && command != "classes" && command != "namespaces" && command != "simulate" {
+		return fmt.Errorf("unknown command %q, see kubectl overcommit --help", command)
+	}
+
+	var opts options
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(fs.Output(), usage) }
+	fs.StringVar(&opts.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	fs.StringVar(&opts.context, "context", "", "Name of the kubeconfig context to use")
+	fs.StringVar(&opts.namespace, "namespace", "", "Namespace of the pod or of the manifests without one")
+	fs.StringVar(&opts.namespace, "n", "", "Shorthand for --namespace")
+	if command == "simulate" {
+		fs.StringVar(&opts.filename, "filename", "", "Manifests of the pods to simulate")
+		fs.StringVar(&opts.filename, "f", "", "Shorthand for --filename")
+	}
+	positional, err := parse(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if command == "explain" && len(positional) != 1 {
+		return errors.New("explain takes the name of a pod")
+	}
+	if command == "simulate" && opts.filename == "" {
+		return errors.New("simulate takes the manifests with -f")
+	}
+
+	p, namespace, err := newPlugin(opts)
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "explain":
+		return p.Explain(ctx, namespace, positional[0])
+	case "classes":
+		return p.Classes(ctx)
+	case "namespaces":
+		return p.Namespaces(ctx)
+	case "simulate":
+		manifests := io.Reader(os.Stdin)
+		if opts.filename != "-" {
+			file, err := os.Open(opts.filename)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			manifests = file
+		}
+		return p.Simulate(ctx, manifests, namespace)
+	}
+	return nil
+}
+
+// parse parses the flags placed before and after the positional arguments, like kubectl does.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// newPlugin builds the plugin from the kubeconfig, like kubectl loads it, and returns the namespace of the
+// flags or of the kubeconfig context.
+func newPlugin(opts options) (*plugin.Plugin, string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = opts.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules,
+		&clientcmd.ConfigOverrides{CurrentContext: opts.context})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	namespace := opts.namespace
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, "", err
+	}
+	if err := overcommit.AddToScheme(scheme); err != nil {
+		return nil, "", err
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", err
+	}
+	return &plugin.Plugin{Client: k8sClient, Out: os.Stdout}, namespace, nil
+}
@@ -27,12 +27,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	occontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/overcommitclass"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 
+	capacitycontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/capacity"
+	driftcontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/drift"
+	nodecontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/node"
 	overcommitcontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/overcommit"
+	quotacontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/quota"
+	recommendationcontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/recommendation"
+	reportcontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/report"
+	resizecontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/resize"
+	rolloutcontroller "github.com/InditexTech/k8s-overcommit-operator/internal/controller/rollout"
 	webhookcorev1mutating "github.com/InditexTech/k8s-overcommit-operator/internal/webhook/v1alphav1/mutating"
+	webhookovercommitclass "github.com/InditexTech/k8s-overcommit-operator/internal/webhook/v1alphav1/overcommitclass"
 	webhookcorev1validating "github.com/InditexTech/k8s-overcommit-operator/internal/webhook/v1alphav1/validating"
 	// +kubebuilder:scaffold:imports
 )
@@ -50,12 +60,25 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cleanup" {
+		if err := runCleanup(os.Args[2:]); err != nil {
+			setupLog.Error(err, "unable to clean up the operator resources")
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var configFile string
 	var tlsOpts []func(*tls.Config)
+	operatorConfig := config.FromEnv()
+	operatorConfig.BindFlags(flag.CommandLine)
+	flag.StringVar(&configFile, "config", "", "Path to a YAML file with the operator configuration. "+
+		"Flags take precedence over the file, and the file over the environment.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -74,6 +97,11 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if err := operatorConfig.LoadFile(configFile, flag.CommandLine); err != nil {
+		setupLog.Error(err, "unable to load the operator configuration")
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -112,7 +140,7 @@ func main() {
 		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/metrics/filters#WithAuthenticationAndAuthorization
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
-	deploymentName, err := utils.GetPodDeploymentName()
+	deploymentName, err := utils.GetPodDeploymentName(operatorConfig.PodNamespace, operatorConfig.PodName)
 	if err != nil {
 		setupLog.Error(err, "unable to get pod deployment name")
 		os.Exit(1)
@@ -124,13 +152,12 @@ func main() {
 		HealthProbeBindAddress:  probeAddr,
 		LeaderElection:          enableLeaderElection,
 		LeaderElectionID:        deploymentName + ".inditex.dev",
-		LeaderElectionNamespace: os.Getenv("POD_NAMESPACE"),
+		LeaderElectionNamespace: operatorConfig.PodNamespace,
 	}
-	// nolint:goconst
-	if os.Getenv("ENABLE_POD_MUTATING_WEBHOOK") == "true" || os.Getenv("ENABLE_POD_VALIDATING_WEBHOOK") == "true" || os.Getenv("ENABLE_OC_VALIDATING_WEBHOOK") == "true" {
+	if operatorConfig.WebhooksEnabled() {
 		webhookServer := webhook.NewServer(webhook.Options{
 			TLSOpts: tlsOpts,
-			CertDir: os.Getenv("WEBHOOK_CERT_DIR"),
+			CertDir: operatorConfig.WebhookCertDir,
 		})
 
 		mgrOptions.WebhookServer = webhookServer
@@ -142,88 +169,195 @@ func main() {
 		os.Exit(1)
 	}
 
-	if os.Getenv("ENABLE_OVERCOMMIT_CONTROLLER") == "true" {
-		// Get the image of the pod
+	if operatorConfig.EnableOvercommitController {
+		// The image and service account of the generated deployments default to the ones of this pod
 		ctx := context.Background()
-		registry, image, tag, err := utils.GetPodImageDetails(ctx, mgr.GetAPIReader())
-		if err != nil {
-			setupLog.Error(err, "unable to get pod image details")
+		if operatorConfig.ImageRegistry == "" || operatorConfig.ImageRepository == "" || operatorConfig.AppVersion == "" {
+			registry, image, tag, err := utils.GetPodImageDetails(ctx, mgr.GetAPIReader(), operatorConfig.PodNamespace, operatorConfig.PodName)
+			if err != nil {
+				setupLog.Error(err, "unable to get pod image details")
+				os.Exit(1)
+			}
+			operatorConfig.ImageRegistry = registry
+			operatorConfig.ImageRepository = image
+			operatorConfig.AppVersion = tag
+		}
+
+		if operatorConfig.ServiceAccountName == "" {
+			serviceAccountName, err := utils.GetPodServiceAccount(ctx, mgr.GetAPIReader(), operatorConfig.PodNamespace, operatorConfig.PodName)
+			if err != nil {
+				setupLog.Error(err, "unable to get pod service account")
+				os.Exit(1)
+			}
+			operatorConfig.ServiceAccountName = serviceAccountName
+		}
+	}
+
+	if err := operatorConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid operator configuration")
+		os.Exit(1)
+	}
+
+	if operatorConfig.EnableOvercommitController {
+		setupLog.Info("Enabling bootstrap controller")
+		if err = (&overcommitcontroller.OvercommitReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Config: operatorConfig,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Overcommit")
 			os.Exit(1)
 		}
+	}
 
-		serviceAccountName, err := utils.GetPodServiceAccount(ctx, mgr.GetAPIReader())
-		if err != nil {
-			setupLog.Error(err, "unable to get pod service account")
+	if operatorConfig.EnableOvercommitClassController {
+		setupLog.Info("Enabling overcommit class controller")
+		// Register overcommitClass controller
+		if err = (&occontroller.OvercommitClassReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Config: operatorConfig,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitClass")
 			os.Exit(1)
 		}
-		err = os.Setenv("SERVICE_ACCOUNT_NAME", serviceAccountName)
-		if err != nil {
-			setupLog.Error(err, "unable to set service account name")
+	}
+
+	if operatorConfig.EnableRolloutController {
+		setupLog.Info("Enabling rollout controller")
+		// Register the controller restarting the workloads with outdated overcommit values
+		if err = (&rolloutcontroller.RolloutReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			APIReader: mgr.GetAPIReader(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitClassRollout")
 			os.Exit(1)
 		}
-		err = os.Setenv("IMAGE_REGISTRY", registry)
-		if err != nil {
-			setupLog.Error(err, "unable to set image registry")
+	}
+
+	if operatorConfig.EnableDriftController {
+		setupLog.Info("Enabling drift controller")
+		// Register the controller reporting the pods with outdated overcommit values
+		if err = (&driftcontroller.DriftReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitDrift")
 			os.Exit(1)
 		}
-		err = os.Setenv("IMAGE_REPOSITORY", image)
-		if err != nil {
-			setupLog.Error(err, "unable to set image repository")
+	}
+
+	if operatorConfig.EnableResizeController {
+		setupLog.Info("Enabling resize controller")
+		// Register the controller resizing in place the pods with outdated overcommit values
+		if err = (&resizecontroller.ResizeReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("overcommit-resize"),
+			Instance:  operatorConfig.PodName,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitClassResize")
 			os.Exit(1)
 		}
-		err = os.Setenv("APP_VERSION", tag)
-		if err != nil {
-			setupLog.Error(err, "unable to set app version")
+		// Register the controller recording the decision of the pods resized through the pods/resize subresource
+		if err = (&resizecontroller.DecisionReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Instance: operatorConfig.PodName,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitDecision")
 			os.Exit(1)
 		}
+	}
 
-		setupLog.Info("Enabling bootstrap controller")
-		if err = (&overcommitcontroller.OvercommitReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
+	if operatorConfig.EnableRecommendationController {
+		setupLog.Info("Enabling recommendation controller")
+		// Register the controller recommending the ratios of the classes from the observed usage
+		if err = (&recommendationcontroller.RecommendationReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("overcommit-recommendation"),
 		}).SetupWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "Overcommit")
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitRecommendation")
 			os.Exit(1)
 		}
 	}
 
-	// nolint:goconst
-	if os.Getenv("ENABLE_OVERCOMMIT_CLASS_CONTROLLER") == "true" {
-		setupLog.Info("Enabling overcommit class controller")
-		// Register overcommitClass controller
-		if err = (&occontroller.OvercommitClassReconciler{
+	if operatorConfig.EnableNodeController {
+		setupLog.Info("Enabling node controller")
+		// Register the controller reporting the effective overcommit of the nodes
+		if err = (&nodecontroller.NodeReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			APIReader: mgr.GetAPIReader(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitNodes")
+			os.Exit(1)
+		}
+	}
+
+	if operatorConfig.EnableQuotaController {
+		setupLog.Info("Enabling quota controller")
+		// Register the controller making the ResourceQuotas aware of the overcommit of their namespace
+		if err = (&quotacontroller.QuotaReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("overcommit-quota"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitResourceQuota")
+			os.Exit(1)
+		}
+	}
+
+	if operatorConfig.EnableReportController {
+		setupLog.Info("Enabling report controller")
+		// Register the controller reporting the resources saved by the overcommit
+		if err = (&reportcontroller.ReportReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			APIReader: mgr.GetAPIReader(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitReport")
+			os.Exit(1)
+		}
+	}
+
+	if operatorConfig.EnableCapacityController {
+		setupLog.Info("Enabling capacity controller")
+		// Register the controller publishing the requests and limits of the running mutated pods
+		if err = (&capacitycontroller.CapacityReconciler{
 			Client: mgr.GetClient(),
 			Scheme: mgr.GetScheme(),
 		}).SetupWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "OvercommitClass")
+			setupLog.Error(err, "unable to create controller", "controller", "OvercommitCapacity")
 			os.Exit(1)
 		}
 	}
 
-	// nolint:goconst
-	if os.Getenv("ENABLE_POD_MUTATING_WEBHOOK") == "true" {
+	if operatorConfig.EnablePodMutatingWebhook {
 		setupLog.Info("Enabling pod mutating webhook")
 		// Register pod mutating webhook
-		if err = webhookcorev1mutating.SetupPodWebhookWithManager(mgr); err != nil {
+		if err = webhookcorev1mutating.SetupPodWebhookWithManager(mgr, operatorConfig); err != nil {
 			setupLog.Error(err, "unable to create mutating webhook", "webhook", "Pod")
 			os.Exit(1)
 		}
 	}
-	// nolint:goconst
-	if os.Getenv("ENABLE_POD_VALIDATING_WEBHOOK") == "true" {
+	if operatorConfig.EnablePodValidatingWebhook {
 		setupLog.Info("Enabling pod validating webhook")
 		// Register pod validating webhook
-		if err = webhookcorev1validating.SetupPodWebhookWithManager(mgr); err != nil {
+		if err = webhookcorev1validating.SetupPodWebhookWithManager(mgr, operatorConfig); err != nil {
 			setupLog.Error(err, "unable to create validating webhook", "webhook", "Pod")
 			os.Exit(1)
 		}
 	}
 
-	// nolint:goconst
-	if os.Getenv("ENABLE_OC_VALIDATING_WEBHOOK") == "true" {
+	if operatorConfig.EnableOCValidatingWebhook {
 		setupLog.Info("Enabling overcommitClass validating webhook")
 		// Register overcommitClass validation webhook
-		if err = (&overcommit.OvercommitClass{}).SetupWebhookWithManager(mgr); err != nil {
+		if err = webhookovercommitclass.SetupOvercommitClassWebhookWithManager(mgr, operatorConfig.PodNamespace); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "OvercommitClass")
 			os.Exit(1)
 		}
@@ -234,11 +368,18 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	// The pod is ready once the informers are synced, so lookups are never served from a partial cache
+	if err := mgr.AddReadyzCheck("readyz", utils.CacheSyncedChecker(mgr.GetCache())); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
-	metrics.K8sOvercommitOperatorVersion.WithLabelValues(os.Getenv("APP_VERSION")).Set(1)
+	if operatorConfig.WebhooksEnabled() {
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
+	metrics.K8sOvercommitOperatorVersion.WithLabelValues(operatorConfig.AppVersion).Set(1)
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -0,0 +1,346 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certs implements the built-in certificate management used when cert-manager is not available.
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+)
+
+const (
+	// CASecretName is the Secret holding the operator's self-signed CA.
+	CASecretName = "k8s-overcommit-ca"
+
+	// CADuration is the validity of the self-signed CA.
+	CADuration = 10 * 365 * 24 * time.Hour
+	// CertificateDuration is the validity of the webhook serving certificates (1 year, as with cert-manager).
+	CertificateDuration = 365 * 24 * time.Hour
+	// RenewBefore is how long before expiry certificates are rotated (30 days, as with cert-manager).
+	RenewBefore = 30 * 24 * time.Hour
+
+	// CACertKey is the Secret key holding the PEM encoded CA certificate.
+	CACertKey = "ca.crt"
+)
+
+var certslog = logf.Log.WithName("certs")
+
+// CA is a certificate authority able to sign webhook serving certificates.
+type CA struct {
+	Cert    *x509.Certificate
+	Key     crypto.Signer
+	CertPEM []byte
+}
+
+// EnsureCA returns the CA stored in the CA Secret, generating or rotating it when it is
+// missing, unreadable or about to expire. The owner is optional.
+func EnsureCA(ctx context.Context, c client.Client, scheme *runtime.Scheme, namespace string, owner client.Object) (*CA, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Name: CASecretName, Namespace: namespace}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("error getting CA secret: %w", err)
+	}
+
+	if err == nil {
+		ca, parseErr := parseCA(secret)
+		if parseErr == nil && !needsRenewal(ca.Cert, time.Now()) {
+			return ca, nil
+		}
+		certslog.Info("Rotating the webhook CA", "secret", CASecretName, "reason", renewalReason(parseErr))
+	}
+
+	ca, err := generateCA()
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := encodeKey(ca.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Name = CASecretName
+	secret.Namespace = namespace
+	_, err = controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+		secret.Type = corev1.SecretTypeTLS
+		metav1.SetMetaDataLabel(&secret.ObjectMeta, utils.ManagedByLabel, utils.FieldManager)
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       ca.CertPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			CACertKey:               ca.CertPEM,
+		}
+		if owner != nil && len(secret.OwnerReferences) == 0 {
+			return controllerutil.SetOwnerReference(owner, secret, scheme)
+		}
+		return nil
+	})
+	if apierrors.IsAlreadyExists(err) {
+		// Another controller created the CA concurrently, use that one
+		return EnsureCA(ctx, c, scheme, namespace, owner)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error storing CA secret: %w", err)
+	}
+	return ca, nil
+}
+
+// EnsureServingCertificate makes sure the given Secret holds a serving certificate for the
+// DNS names signed by the CA, reissuing it when it is missing, signed by another CA, issued
+// for other names or about to expire. It returns the time at which it has to be renewed.
+func EnsureServingCertificate(ctx context.Context, c client.Client, scheme *runtime.Scheme, ca *CA, namespace, secretName string, dnsNames []string, owner client.Object) (time.Time, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Name: secretName, Namespace: namespace}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return time.Time{}, fmt.Errorf("error getting certificate secret %s: %w", secretName, err)
+	}
+
+	if err == nil {
+		cert, parseErr := parseServingCertificate(secret, ca, dnsNames)
+		if parseErr == nil && !needsRenewal(cert, time.Now()) {
+			return RenewalTime(cert), nil
+		}
+		certslog.Info("Issuing webhook serving certificate", "secret", secretName, "reason", renewalReason(parseErr))
+	}
+
+	certPEM, keyPEM, cert, err := issueServingCertificate(ca, dnsNames)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	secret.Name = secretName
+	secret.Namespace = namespace
+	_, err = controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+		secret.Type = corev1.SecretTypeTLS
+		metav1.SetMetaDataLabel(&secret.ObjectMeta, utils.ManagedByLabel, utils.FieldManager)
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			CACertKey:               ca.CertPEM,
+		}
+		if owner != nil {
+			return controllerutil.SetControllerReference(owner, secret, scheme)
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error storing certificate secret %s: %w", secretName, err)
+	}
+	return RenewalTime(cert), nil
+}
+
+// RenewalTime returns the moment a certificate has to be rotated.
+func RenewalTime(cert *x509.Certificate) time.Time {
+	return cert.NotAfter.Add(-RenewBefore)
+}
+
+// ServiceDNSNames returns the DNS names a webhook Service is reachable at.
+func ServiceDNSNames(name, namespace string) []string {
+	return []string{
+		name + "." + namespace + ".svc",
+		name + "." + namespace + ".svc.cluster.local",
+	}
+}
+
+// RequeueAfter returns how long to wait until the earliest of the given renewal times.
+func RequeueAfter(renewals ...time.Time) time.Duration {
+	var next time.Time
+	for _, renewal := range renewals {
+		if renewal.IsZero() {
+			continue
+		}
+		if next.IsZero() || renewal.Before(next) {
+			next = renewal
+		}
+	}
+	if next.IsZero() {
+		return 0
+	}
+	wait := time.Until(next)
+	if wait < time.Minute {
+		return time.Minute
+	}
+	return wait
+}
+
+func needsRenewal(cert *x509.Certificate, now time.Time) bool {
+	return !now.Before(RenewalTime(cert))
+}
+
+func renewalReason(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "certificate is about to expire"
+}
+
+func generateCA() (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating CA key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "k8s-overcommit-ca"},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(CADuration),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}, nil
+}
+
+func issueServingCertificate(ca *CA, dnsNames []string) ([]byte, []byte, *x509.Certificate, error) {
+	if len(dnsNames) == 0 {
+		return nil, nil, nil, errors.New("at least one DNS name is required")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error generating certificate key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(CertificateDuration)
+	if notAfter.After(ca.Cert.NotAfter) {
+		notAfter = ca.Cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error creating serving certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, cert, nil
+}
+
+func parseCA(secret *corev1.Secret) (*CA, error) {
+	cert, err := decodeCertificate(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, errors.New("stored certificate is not a CA")
+	}
+	keyBlock, _ := pem.Decode(secret.Data[corev1.TLSPrivateKeyKey])
+	if keyBlock == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+	parsedKey, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing CA key: %w", err)
+	}
+	key, ok := parsedKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("CA key cannot sign certificates")
+	}
+	return &CA{Cert: cert, Key: key, CertPEM: secret.Data[corev1.TLSCertKey]}, nil
+}
+
+func parseServingCertificate(secret *corev1.Secret, ca *CA, dnsNames []string) (*x509.Certificate, error) {
+	cert, err := decodeCertificate(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, err
+	}
+	if len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return nil, errors.New("no private key found")
+	}
+	if !bytes.Equal(secret.Data[CACertKey], ca.CertPEM) {
+		return nil, errors.New("certificate was issued by another CA")
+	}
+	if err := cert.CheckSignatureFrom(ca.Cert); err != nil {
+		return nil, fmt.Errorf("certificate is not signed by the current CA: %w", err)
+	}
+	wanted := slices.Clone(dnsNames)
+	current := slices.Clone(cert.DNSNames)
+	slices.Sort(wanted)
+	slices.Sort(current)
+	if !slices.Equal(wanted, current) {
+		return nil, errors.New("certificate DNS names changed")
+	}
+	return cert, nil
+}
+
+func decodeCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func encodeKey(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("error generating serial number: %w", err)
+	}
+	return serial, nil
+}
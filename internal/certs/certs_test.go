@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package certs
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestServingCertificateIsSignedByCA(t *testing.T) {
+	ca, err := generateCA()
+	if err != nil {
+		t.Fatalf("Expected CA to be generated, got error '%v'", err)
+	}
+
+	dnsNames := ServiceDNSNames("test-service", "test-namespace")
+	certPEM, keyPEM, cert, err := issueServingCertificate(ca, dnsNames)
+	if err != nil {
+		t.Fatalf("Expected certificate to be issued, got error '%v'", err)
+	}
+
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			CACertKey:               ca.CertPEM,
+		},
+	}
+	if _, err := parseServingCertificate(secret, ca, dnsNames); err != nil {
+		t.Errorf("Expected certificate to be valid, got error '%v'", err)
+	}
+
+	if needsRenewal(cert, time.Now()) {
+		t.Errorf("Expected a new certificate not to need renewal")
+	}
+	if !needsRenewal(cert, cert.NotAfter.Add(-RenewBefore)) {
+		t.Errorf("Expected certificate to need renewal %v before expiry", RenewBefore)
+	}
+}
+
+func TestServingCertificateIsReissued(t *testing.T) {
+	ca, err := generateCA()
+	if err != nil {
+		t.Fatalf("Expected CA to be generated, got error '%v'", err)
+	}
+	otherCA, err := generateCA()
+	if err != nil {
+		t.Fatalf("Expected CA to be generated, got error '%v'", err)
+	}
+
+	dnsNames := ServiceDNSNames("test-service", "test-namespace")
+	certPEM, keyPEM, _, err := issueServingCertificate(otherCA, dnsNames)
+	if err != nil {
+		t.Fatalf("Expected certificate to be issued, got error '%v'", err)
+	}
+
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			CACertKey:               otherCA.CertPEM,
+		},
+	}
+	if _, err := parseServingCertificate(secret, ca, dnsNames); err == nil {
+		t.Errorf("Expected certificate issued by another CA to be rejected")
+	}
+
+	secret.Data[CACertKey] = ca.CertPEM
+	if _, err := parseServingCertificate(secret, ca, dnsNames); err == nil {
+		t.Errorf("Expected certificate not signed by the CA to be rejected")
+	}
+
+	certPEM, keyPEM, _, err = issueServingCertificate(ca, dnsNames)
+	if err != nil {
+		t.Fatalf("Expected certificate to be issued, got error '%v'", err)
+	}
+	secret.Data[corev1.TLSCertKey] = certPEM
+	secret.Data[corev1.TLSPrivateKeyKey] = keyPEM
+	if _, err := parseServingCertificate(secret, ca, ServiceDNSNames("other-service", "test-namespace")); err == nil {
+		t.Errorf("Expected certificate with other DNS names to be rejected")
+	}
+}
+
+func TestParseCA(t *testing.T) {
+	ca, err := generateCA()
+	if err != nil {
+		t.Fatalf("Expected CA to be generated, got error '%v'", err)
+	}
+	keyPEM, err := encodeKey(ca.Key)
+	if err != nil {
+		t.Fatalf("Expected key to be encoded, got error '%v'", err)
+	}
+
+	parsed, err := parseCA(&corev1.Secret{
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       ca.CertPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected CA to be parsed, got error '%v'", err)
+	}
+	if !parsed.Cert.Equal(ca.Cert) {
+		t.Errorf("Expected parsed CA certificate to match the generated one")
+	}
+
+	if _, err := parseCA(&corev1.Secret{}); err == nil {
+		t.Errorf("Expected empty secret to be rejected")
+	}
+}
+
+func TestRequeueAfter(t *testing.T) {
+	if RequeueAfter() != 0 {
+		t.Errorf("Expected no requeue without renewals")
+	}
+	if RequeueAfter(time.Time{}) != 0 {
+		t.Errorf("Expected zero renewals to be ignored")
+	}
+
+	soon := time.Now().Add(time.Hour)
+	later := time.Now().Add(48 * time.Hour)
+	if wait := RequeueAfter(later, soon); wait > time.Hour || wait < 59*time.Minute {
+		t.Errorf("Expected requeue at the earliest renewal, got '%v'", wait)
+	}
+	if wait := RequeueAfter(time.Now().Add(-time.Hour)); wait != time.Minute {
+		t.Errorf("Expected overdue renewals to requeue after a minute, got '%v'", wait)
+	}
+}
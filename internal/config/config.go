@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package config holds the typed configuration of the operator.
+//
+// The configuration is loaded once at startup. Values are taken, from lowest to highest
+// precedence, from the environment (kept for compatibility with the generated deployments),
+// from an optional YAML config file and from command line flags.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the operator configuration, passed explicitly to the controllers, generators and webhooks.
+type Config struct {
+	// ImageRegistry, ImageRepository and AppVersion build the image used for the generated deployments.
+	ImageRegistry   string `json:"imageRegistry,omitempty"`
+	ImageRepository string `json:"imageRepository,omitempty"`
+	AppVersion      string `json:"appVersion,omitempty"`
+
+	// PodName and PodNamespace identify the pod running the operator.
+	PodName      string `json:"podName,omitempty"`
+	PodNamespace string `json:"podNamespace,omitempty"`
+
+	// ServiceAccountName is the service account used by the generated deployments.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// OvercommitClassName is the class served by a pod mutating webhook.
+	OvercommitClassName string `json:"overcommitClassName,omitempty"`
+
+	// WebhookCertDir is the directory holding the webhook serving certificate.
+	WebhookCertDir string `json:"webhookCertDir,omitempty"`
+
+	EnableOvercommitController      bool `json:"enableOvercommitController,omitempty"`
+	EnableOvercommitClassController bool `json:"enableOvercommitClassController,omitempty"`
+	EnablePodMutatingWebhook        bool `json:"enablePodMutatingWebhook,omitempty"`
+	EnablePodValidatingWebhook      bool `json:"enablePodValidatingWebhook,omitempty"`
+	EnableOCValidatingWebhook       bool `json:"enableOcValidatingWebhook,omitempty"`
+	EnableRolloutController         bool `json:"enableRolloutController,omitempty"`
+	EnableDriftController           bool `json:"enableDriftController,omitempty"`
+	EnableResizeController          bool `json:"enableResizeController,omitempty"`
+	EnableRecommendationController  bool `json:"enableRecommendationController,omitempty"`
+	EnableNodeController            bool `json:"enableNodeController,omitempty"`
+	EnableQuotaController           bool `json:"enableQuotaController,omitempty"`
+	EnableReportController          bool `json:"enableReportController,omitempty"`
+	EnableCapacityController        bool `json:"enableCapacityController,omitempty"`
+}
+
+// FromEnv returns the configuration defined by the environment variables.
+func FromEnv() Config {
+	return Config{
+		ImageRegistry:                   os.Getenv("IMAGE_REGISTRY"),
+		ImageRepository:                 os.Getenv("IMAGE_REPOSITORY"),
+		AppVersion:                      os.Getenv("APP_VERSION"),
+		PodName:                         os.Getenv("POD_NAME"),
+		PodNamespace:                    os.Getenv("POD_NAMESPACE"),
+		ServiceAccountName:              os.Getenv("SERVICE_ACCOUNT_NAME"),
+		OvercommitClassName:             os.Getenv("OVERCOMMIT_CLASS_NAME"),
+		WebhookCertDir:                  os.Getenv("WEBHOOK_CERT_DIR"),
+		EnableOvercommitController:      envBool("ENABLE_OVERCOMMIT_CONTROLLER"),
+		EnableOvercommitClassController: envBool("ENABLE_OVERCOMMIT_CLASS_CONTROLLER"),
+		EnablePodMutatingWebhook:        envBool("ENABLE_POD_MUTATING_WEBHOOK"),
+		EnablePodValidatingWebhook:      envBool("ENABLE_POD_VALIDATING_WEBHOOK"),
+		EnableOCValidatingWebhook:       envBool("ENABLE_OC_VALIDATING_WEBHOOK"),
+		EnableRolloutController:         envBool("ENABLE_ROLLOUT_CONTROLLER"),
+		EnableDriftController:           envBool("ENABLE_DRIFT_CONTROLLER"),
+		EnableResizeController:          envBool("ENABLE_RESIZE_CONTROLLER"),
+		EnableRecommendationController:  envBool("ENABLE_RECOMMENDATION_CONTROLLER"),
+		EnableNodeController:            envBool("ENABLE_NODE_CONTROLLER"),
+		EnableQuotaController:           envBool("ENABLE_QUOTA_CONTROLLER"),
+		EnableReportController:          envBool("ENABLE_REPORT_CONTROLLER"),
+		EnableCapacityController:        envBool("ENABLE_CAPACITY_CONTROLLER"),
+	}
+}
+
+func envBool(name string) bool {
+	value, err := strconv.ParseBool(os.Getenv(name))
+	return err == nil && value
+}
+
+// BindFlags registers a flag for every field, using the current values as defaults.
+func (c *Config) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.ImageRegistry, "image-registry", c.ImageRegistry, "Registry of the operator image used for the generated deployments.")
+	fs.StringVar(&c.ImageRepository, "image-repository", c.ImageRepository, "Repository of the operator image used for the generated deployments.")
+	fs.StringVar(&c.AppVersion, "app-version", c.AppVersion, "Tag of the operator image used for the generated deployments.")
+	fs.StringVar(&c.PodName, "pod-name", c.PodName, "Name of the pod running the operator.")
+	fs.StringVar(&c.PodNamespace, "pod-namespace", c.PodNamespace, "Namespace of the pod running the operator.")
+	fs.StringVar(&c.ServiceAccountName, "service-account-name", c.ServiceAccountName, "Service account used by the generated deployments.")
+	fs.StringVar(&c.OvercommitClassName, "overcommit-class-name", c.OvercommitClassName, "OvercommitClass served by the pod mutating webhook.")
+	fs.StringVar(&c.WebhookCertDir, "webhook-cert-dir", c.WebhookCertDir, "Directory holding the webhook serving certificate.")
+	fs.BoolVar(&c.EnableOvercommitController, "enable-overcommit-controller", c.EnableOvercommitController, "Enable the Overcommit controller.")
+	fs.BoolVar(&c.EnableOvercommitClassController, "enable-overcommit-class-controller", c.EnableOvercommitClassController, "Enable the OvercommitClass controller.")
+	fs.BoolVar(&c.EnablePodMutatingWebhook, "enable-pod-mutating-webhook", c.EnablePodMutatingWebhook, "Enable the pod mutating webhook.")
+	fs.BoolVar(&c.EnablePodValidatingWebhook, "enable-pod-validating-webhook", c.EnablePodValidatingWebhook, "Enable the pod validating webhook.")
+	fs.BoolVar(&c.EnableOCValidatingWebhook, "enable-oc-validating-webhook", c.EnableOCValidatingWebhook, "Enable the OvercommitClass validating webhook.")
+	fs.BoolVar(&c.EnableRolloutController, "enable-rollout-controller", c.EnableRolloutController, "Enable the rolling restarts of the workloads with outdated overcommit values.")
+	fs.BoolVar(&c.EnableDriftController, "enable-drift-controller", c.EnableDriftController, "Enable the drift report of the pods running with outdated overcommit values.")
+	fs.BoolVar(&c.EnableResizeController, "enable-resize-controller", c.EnableResizeController, "Enable the in-place resize of the pods running with outdated overcommit values.")
+	fs.BoolVar(&c.EnableRecommendationController, "enable-recommendation-controller", c.EnableRecommendationController, "Enable the recommendation of the overcommit ratios from the observed usage.")
+	fs.BoolVar(&c.EnableNodeController, "enable-node-controller", c.EnableNodeController, "Enable the report of the effective overcommit of the nodes.")
+	fs.BoolVar(&c.EnableQuotaController, "enable-quota-controller", c.EnableQuotaController, "Enable the ResourceQuota awareness of the overcommit of the namespaces.")
+	fs.BoolVar(&c.EnableReportController, "enable-report-controller", c.EnableReportController, "Enable the OvercommitReports summarizing the resources saved by the overcommit.")
+	fs.BoolVar(&c.EnableCapacityController, "enable-capacity-controller", c.EnableCapacityController, "Enable the metrics of the requests and limits of the running mutated pods.")
+}
+
+// LoadFile merges the YAML config file at path into the configuration.
+// Flags explicitly set in fs keep precedence over the values of the file.
+func (c *Config) LoadFile(path string, fs *flag.FlagSet) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read config file %s: %w", path, err)
+	}
+
+	setFlags := map[string]string{}
+	if fs != nil {
+		fs.Visit(func(f *flag.Flag) {
+			setFlags[f.Name] = f.Value.String()
+		})
+	}
+
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+
+	for name, value := range setFlags {
+		if err := fs.Set(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Image returns the operator image used for the generated deployments.
+func (c Config) Image() string {
+	return c.ImageRegistry + "/" + c.ImageRepository + ":" + c.AppVersion
+}
+
+// WebhooksEnabled reports whether any webhook is served by this process.
+func (c Config) WebhooksEnabled() bool {
+	return c.EnablePodMutatingWebhook || c.EnablePodValidatingWebhook || c.EnableOCValidatingWebhook
+}
+
+// GeneratesResources reports whether this process generates deployments running the operator image.
+func (c Config) GeneratesResources() bool {
+	return c.EnableOvercommitController || c.EnableOvercommitClassController
+}
+
+// Validate checks that the values required by the enabled components are set.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.PodNamespace == "" {
+		errs = append(errs, missing("pod namespace", "pod-namespace", "POD_NAMESPACE"))
+	}
+
+	if c.GeneratesResources() {
+		if c.ImageRegistry == "" {
+			errs = append(errs, missing("image registry", "image-registry", "IMAGE_REGISTRY"))
+		}
+		if c.ImageRepository == "" {
+			errs = append(errs, missing("image repository", "image-repository", "IMAGE_REPOSITORY"))
+		}
+		if c.AppVersion == "" {
+			errs = append(errs, missing("app version", "app-version", "APP_VERSION"))
+		}
+		if c.ServiceAccountName == "" {
+			errs = append(errs, missing("service account name", "service-account-name", "SERVICE_ACCOUNT_NAME"))
+		}
+		if strings.ContainsAny(c.ImageRepository, ":@") {
+			errs = append(errs, fmt.Errorf("image repository %q must not contain a tag or digest", c.ImageRepository))
+		}
+	}
+
+	if c.EnablePodMutatingWebhook && c.OvercommitClassName == "" {
+		errs = append(errs, missing("overcommit class name", "overcommit-class-name", "OVERCOMMIT_CLASS_NAME"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func missing(name, flagName, envName string) error {
+	return fmt.Errorf("%s is not set (--%s or %s)", name, flagName, envName)
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("IMAGE_REGISTRY", "test-registry")
+	t.Setenv("IMAGE_REPOSITORY", "test-repo")
+	t.Setenv("APP_VERSION", "v1.0.0")
+	t.Setenv("ENABLE_POD_MUTATING_WEBHOOK", "true")
+	t.Setenv("ENABLE_OVERCOMMIT_CONTROLLER", "false")
+
+	cfg := FromEnv()
+
+	if cfg.Image() != "test-registry/test-repo:v1.0.0" {
+		t.Errorf("Expected image 'test-registry/test-repo:v1.0.0', got '%s'", cfg.Image())
+	}
+	if !cfg.EnablePodMutatingWebhook || !cfg.WebhooksEnabled() {
+		t.Errorf("Expected the pod mutating webhook to be enabled")
+	}
+	if cfg.EnableOvercommitController {
+		t.Errorf("Expected the overcommit controller to be disabled")
+	}
+}
+
+func TestLoadFileKeepsFlagPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte("imageRegistry: file-registry\nimageRepository: file-repo\nappVersion: v2.0.0\n"), 0o600)
+	if err != nil {
+		t.Fatalf("Expected config file to be written, got error '%v'", err)
+	}
+
+	cfg := Config{ImageRegistry: "env-registry", PodNamespace: "env-namespace"}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.BindFlags(fs)
+	if err := fs.Parse([]string{"--app-version=v3.0.0"}); err != nil {
+		t.Fatalf("Expected flags to be parsed, got error '%v'", err)
+	}
+
+	if err := cfg.LoadFile(path, fs); err != nil {
+		t.Fatalf("Expected config file to be loaded, got error '%v'", err)
+	}
+
+	if cfg.Image() != "file-registry/file-repo:v3.0.0" {
+		t.Errorf("Expected image 'file-registry/file-repo:v3.0.0', got '%s'", cfg.Image())
+	}
+	if cfg.PodNamespace != "env-namespace" {
+		t.Errorf("Expected pod namespace from the environment to be kept, got '%s'", cfg.PodNamespace)
+	}
+}
+
+func TestLoadFileRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("imageRegistryy: typo\n"), 0o600); err != nil {
+		t.Fatalf("Expected config file to be written, got error '%v'", err)
+	}
+
+	cfg := Config{}
+	if err := cfg.LoadFile(path, nil); err == nil {
+		t.Errorf("Expected unknown fields to be rejected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Config{PodNamespace: "test-namespace", EnableOvercommitController: true}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("Expected missing image details to be rejected")
+	}
+
+	cfg.ImageRegistry = "test-registry"
+	cfg.ImageRepository = "test-repo"
+	cfg.AppVersion = "v1.0.0"
+	cfg.ServiceAccountName = "test-service-account"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected configuration to be valid, got error '%v'", err)
+	}
+
+	cfg.EnablePodMutatingWebhook = true
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("Expected missing overcommit class name to be rejected")
+	}
+
+	if err := (Config{}).Validate(); err == nil {
+		t.Errorf("Expected missing pod namespace to be rejected")
+	}
+}
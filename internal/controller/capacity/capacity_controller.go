@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// capacityResources are the resources whose requests and limits are published.
+var capacityResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// CapacityReconciler keeps the total requests and limits of the running pods mutated by every class, by
+// namespace, in the pods requests and limits metrics. Every pod is reconciled as it changes, its previous
+// contribution being replaced by the current one.
+type CapacityReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Pods reads the pods from the cache of the controller, trimmed by TrimPod. Client is used when nil.
+	Pods client.Reader
+
+	mu sync.Mutex
+	// pods are the contributions of the pods counted in the totals
+	pods   map[types.NamespacedName]podCapacity
+	totals map[capacityKey]*capacityTotal
+}
+
+// capacityKey identifies the pods of a class in a namespace.
+type capacityKey struct {
+	class     string
+	namespace string
+}
+
+// podCapacity is the contribution of a pod to the totals of its class and namespace, in milli units.
+type podCapacity struct {
+	key      capacityKey
+	requests map[corev1.ResourceName]int64
+	limits   map[corev1.ResourceName]int64
+}
+
+// capacityTotal sums the contributions of the pods of a class in a namespace, in milli units.
+type capacityTotal struct {
+	pods     int
+	requests map[corev1.ResourceName]int64
+	limits   map[corev1.ResourceName]int64
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager. The pods are watched through a cache of their own,
+// trimmed by TrimPod, so the other controllers and the webhooks keep reading whole pods from the cache of the
+// manager.
+func (r *CapacityReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	podCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:   mgr.GetScheme(),
+		Mapper:   mgr.GetRESTMapper(),
+		ByObject: map[client.Object]cache.ByObject{&corev1.Pod{}: {Transform: TrimPod}},
+	})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(podCache); err != nil {
+		return err
+	}
+	r.Pods = podCache
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// Only the pods mutated by a class count in the totals
+		WatchesRawSource(source.Kind(podCache, &corev1.Pod{}, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{},
+			predicate.NewTypedPredicateFuncs(func(pod *corev1.Pod) bool {
+				return pod.GetAnnotations()[overcommit.AppliedAnnotation] != ""
+			}))).
+		Named("OvercommitCapacity").
+		Complete(r)
+}
+
+func (r *CapacityReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reader := r.Pods
+	if reader == nil {
+		reader = r.Client
+	}
+	pod := &corev1.Pod{}
+	if err := reader.Get(ctx, req.NamespacedName, pod); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		pod = nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pods == nil {
+		r.pods = map[types.NamespacedName]podCapacity{}
+		r.totals = map[capacityKey]*capacityTotal{}
+	}
+	if previous, ok := r.pods[req.NamespacedName]; ok {
+		r.remove(previous)
+		delete(r.pods, req.NamespacedName)
+	}
+	if counted(pod) {
+		current := newPodCapacity(pod)
+		r.add(current)
+		r.pods[req.NamespacedName] = current
+	}
+	return ctrl.Result{}, nil
+}
+
+// counted reports whether a pod counts in the totals: it exists, was mutated by a class and is not terminated.
+func counted(pod *corev1.Pod) bool {
+	return pod != nil && pod.Annotations[overcommit.AppliedAnnotation] != "" && pod.DeletionTimestamp.IsZero() &&
+		pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+}
+
+// newPodCapacity sums the requests and limits of the containers and sidecars of a pod, the containers not
+// setting a resource not counting in it.
+func newPodCapacity(pod *corev1.Pod) podCapacity {
+	capacity := podCapacity{
+		key:      capacityKey{class: pod.Annotations[overcommit.AppliedAnnotation], namespace: pod.Namespace},
+		requests: map[corev1.ResourceName]int64{},
+		limits:   map[corev1.ResourceName]int64{},
+	}
+	add := func(resources corev1.ResourceRequirements) {
+		for _, name := range capacityResources {
+			if quantity, ok := resources.Requests[name]; ok {
+				capacity.requests[name] += quantity.MilliValue()
+			}
+			if quantity, ok := resources.Limits[name]; ok {
+				capacity.limits[name] += quantity.MilliValue()
+			}
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		add(container.Resources)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			add(container.Resources)
+		}
+	}
+	return capacity
+}
+
+func (r *CapacityReconciler) add(capacity podCapacity) {
+	total := r.totals[capacity.key]
+	if total == nil {
+		total = &capacityTotal{requests: map[corev1.ResourceName]int64{}, limits: map[corev1.ResourceName]int64{}}
+		r.totals[capacity.key] = total
+	}
+	total.pods++
+	for _, name := range capacityResources {
+		total.requests[name] += capacity.requests[name]
+		total.limits[name] += capacity.limits[name]
+	}
+	r.publish(capacity.key)
+}
+
+func (r *CapacityReconciler) remove(capacity podCapacity) {
+	total := r.totals[capacity.key]
+	total.pods--
+	for _, name := range capacityResources {
+		total.requests[name] -= capacity.requests[name]
+		total.limits[name] -= capacity.limits[name]
+	}
+	r.publish(capacity.key)
+}
+
+// publish sets the metrics of a class in a namespace, deleting them along with its last pod.
+func (r *CapacityReconciler) publish(key capacityKey) {
+	total := r.totals[key]
+	for _, name := range capacityResources {
+		if total.pods == 0 {
+			metrics.K8sOvercommitOperatorPodsRequests.DeleteLabelValues(key.class, key.namespace, string(name))
+			metrics.K8sOvercommitOperatorPodsLimits.DeleteLabelValues(key.class, key.namespace, string(name))
+			continue
+		}
+		metrics.K8sOvercommitOperatorPodsRequests.WithLabelValues(key.class, key.namespace, string(name)).
+			Set(float64(total.requests[name]) / 1000)
+		metrics.K8sOvercommitOperatorPodsLimits.WithLabelValues(key.class, key.namespace, string(name)).
+			Set(float64(total.limits[name]) / 1000)
+	}
+	if total.pods == 0 {
+		delete(r.totals, key)
+	}
+}
+
+// TrimPod is the transform of the cache of the pods of the capacity controller. It keeps the metadata of the pods
+// without managed fields and, for the ones mutated by a class, the resources of their containers and their phase,
+// so that the cached pods take little memory. Other objects are returned unchanged. It must not be installed on
+// the cache of the manager, where the webhooks and the other controllers read the whole pods.
+func TrimPod(obj interface{}) (interface{}, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	trimmed := &corev1.Pod{TypeMeta: pod.TypeMeta, ObjectMeta: pod.ObjectMeta}
+	trimmed.ManagedFields = nil
+	if pod.Annotations[overcommit.AppliedAnnotation] == "" {
+		return trimmed, nil
+	}
+	for _, container := range pod.Spec.Containers {
+		trimmed.Spec.Containers = append(trimmed.Spec.Containers, corev1.Container{Name: container.Name, Resources: container.Resources})
+	}
+	for _, container := range pod.Spec.InitContainers {
+		trimmed.Spec.InitContainers = append(trimmed.Spec.InitContainers, corev1.Container{
+			Name:          container.Name,
+			Resources:     container.Resources,
+			RestartPolicy: container.RestartPolicy,
+		})
+	}
+	trimmed.Status.Phase = pod.Status.Phase
+	return trimmed, nil
+}
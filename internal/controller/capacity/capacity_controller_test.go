@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+)
+
+var _ = Describe("Capacity", func() {
+	var (
+		k8sClient client.Client
+		r         *CapacityReconciler
+	)
+
+	resources, container := controllertest.Resources, controllertest.Container
+	pod := func(name, class string, containers ...corev1.Container) *corev1.Pod {
+		return controllertest.Pod("apps", name, class, containers...)
+	}
+	reconcile := func(name string) {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "apps", Name: name}})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	requests := func(class, resource string) float64 {
+		return testutil.ToFloat64(metrics.K8sOvercommitOperatorPodsRequests.WithLabelValues(class, "apps", resource))
+	}
+	limits := func(class, resource string) float64 {
+		return testutil.ToFloat64(metrics.K8sOvercommitOperatorPodsLimits.WithLabelValues(class, "apps", resource))
+	}
+
+	BeforeEach(func() {
+		sidecar := container(resources("100m", "64Mi"), resources("200m", "128Mi"))
+		sidecar.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		web := pod("web", "capacity", container(resources("500m", "1Gi"), resources("1", "2Gi")))
+		web.Spec.InitContainers = []corev1.Container{sidecar, container(resources("4", "4Gi"), resources("4", "4Gi"))}
+		k8sClient = controllertest.NewClientBuilder(
+			web,
+			// Containers without limits only count in the requests
+			pod("api", "capacity", container(resources("250m", "256Mi"), nil)),
+		).Build()
+		r = &CapacityReconciler{Client: k8sClient, Scheme: controllertest.Scheme()}
+	})
+
+	It("sums the requests and limits of the mutated pods by class and namespace", func() {
+		reconcile("web")
+		reconcile("api")
+
+		Expect(requests("capacity", "cpu")).To(Equal(0.85))
+		Expect(limits("capacity", "cpu")).To(Equal(1.2))
+		Expect(requests("capacity", "memory")).To(Equal(float64(1344 << 20)))
+		Expect(limits("capacity", "memory")).To(Equal(float64(2176 << 20)))
+
+		// A pod reconciled again replaces its previous contribution
+		reconcile("web")
+		Expect(requests("capacity", "cpu")).To(Equal(0.85))
+	})
+
+	It("follows the changes and the termination of the pods", func() {
+		reconcile("web")
+		reconcile("api")
+
+		api := &corev1.Pod{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "api"}, api)).To(Succeed())
+		api.Spec.Containers[0].Resources.Requests = resources("750m", "256Mi")
+		Expect(k8sClient.Update(context.Background(), api)).To(Succeed())
+		reconcile("api")
+		Expect(requests("capacity", "cpu")).To(Equal(1.35))
+
+		api.Status.Phase = corev1.PodSucceeded
+		Expect(k8sClient.Status().Update(context.Background(), api)).To(Succeed())
+		reconcile("api")
+		Expect(requests("capacity", "cpu")).To(Equal(0.6))
+
+		// The series are removed along with the last pod of the class
+		Expect(k8sClient.Delete(context.Background(), pod("web", "capacity"))).To(Succeed())
+		reconcile("web")
+		Expect(testutil.CollectAndCount(metrics.K8sOvercommitOperatorPodsLimits)).To(BeZero())
+	})
+
+	It("reads the pods from its own cache when set", func() {
+		web := &corev1.Pod{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "web"}, web)).To(Succeed())
+		trimmed, err := TrimPod(web)
+		Expect(err).NotTo(HaveOccurred())
+		r.Pods = controllertest.NewClientBuilder(trimmed.(*corev1.Pod)).Build()
+
+		reconcile("web")
+		reconcile("api")
+		// The api pod is only known to the client of the manager
+		Expect(requests("capacity", "cpu")).To(Equal(0.6))
+	})
+
+	It("trims the cached pods", func() {
+		web := &corev1.Pod{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "web"}, web)).To(Succeed())
+		web.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}
+
+		trimmed, err := TrimPod(web)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(trimmed.(*corev1.Pod).ManagedFields).To(BeEmpty())
+		Expect(trimmed.(*corev1.Pod).Spec.Containers[0].Image).To(BeEmpty())
+		Expect(trimmed.(*corev1.Pod).Spec.Containers[0].Resources).To(Equal(web.Spec.Containers[0].Resources))
+		Expect(trimmed.(*corev1.Pod).Spec.InitContainers[0].RestartPolicy).NotTo(BeNil())
+		Expect(trimmed.(*corev1.Pod).Status.Phase).To(Equal(corev1.PodRunning))
+
+		web.Annotations = nil
+		trimmed, err = TrimPod(web)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(trimmed.(*corev1.Pod).Spec.Containers).To(BeEmpty())
+
+		metadata := &metav1.PartialObjectMetadata{}
+		Expect(TrimPod(metadata)).To(BeIdenticalTo(metadata))
+	})
+})
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package controllertest holds the fixtures shared by the tests of the controllers, which run against a fake
+// client instead of a test environment.
+package controllertest
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+// ClassLabel is the class label of the Overcommit returned by Cluster.
+const ClassLabel = "inditex.com/overcommit-class"
+
+// Scheme returns a scheme with the core, apps and overcommit types.
+func Scheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(overcommit.AddToScheme(scheme))
+	return scheme
+}
+
+// NewClientBuilder returns a builder of a fake client with the types of Scheme, holding the objects.
+func NewClientBuilder(objects ...client.Object) *fake.ClientBuilder {
+	return fake.NewClientBuilder().WithScheme(Scheme()).WithObjects(objects...)
+}
+
+// Cluster returns the Overcommit of the cluster, with ClassLabel as class label.
+func Cluster() *overcommit.Overcommit {
+	return &overcommit.Overcommit{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       overcommit.OvercommitSpec{OvercommitLabel: ClassLabel},
+	}
+}
+
+// Class returns an OvercommitClass with the ratios.
+func Class(name string, cpu, memory float64) *overcommit.OvercommitClass {
+	return &overcommit.OvercommitClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       overcommit.OvercommitClassSpec{CpuOvercommit: cpu, MemoryOvercommit: memory},
+	}
+}
+
+// Resources returns the cpu and memory quantities as a resource list.
+func Resources(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+}
+
+// Container returns the app container with the requests and limits.
+func Container(requests, limits corev1.ResourceList) corev1.Container {
+	return corev1.Container{Name: "app", Image: "app:1.0", Resources: corev1.ResourceRequirements{Requests: requests, Limits: limits}}
+}
+
+// Pod returns a running pod with the containers, recording the class that mutated it when class is not empty.
+func Pod(namespace, name, class string, containers ...corev1.Container) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.PodSpec{Containers: containers},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if class != "" {
+		pod.Annotations = map[string]string{overcommit.AppliedAnnotation: class}
+	}
+	return pod
+}
+
+// Mutated returns the annotations written by the webhook on a pod mutated by the class with the ratios.
+func Mutated(class, cpu, memory string) map[string]string {
+	return map[string]string{
+		overcommit.AppliedAnnotation:    class,
+		"overcommit.inditex.dev/cpu":    cpu,
+		"overcommit.inditex.dev/memory": memory,
+	}
+}
@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// driftScanPeriod is how often the pods are compared with their classes.
+	driftScanPeriod = 5 * time.Minute
+	// podPageSize is the number of pods read from the API server at once.
+	podPageSize = 500
+)
+
+// Drift states of a pod, used as the state label of the drift metric.
+const (
+	// driftCurrent is a pod mutated with the current ratios of its class.
+	driftCurrent = "current"
+	// driftOutdated is a pod mutated by its class with other ratios than its current ones.
+	driftOutdated = "outdated"
+	// driftOrphaned is a pod mutated by, or requesting, a class that no longer exists.
+	driftOrphaned = "orphaned"
+	// driftUnmutated is a pod in scope of a class that was never mutated.
+	driftUnmutated = "unmutated"
+)
+
+// DriftReconciler regularly compares the overcommit recorded on every pod with the current OvercommitClasses,
+// publishing the number of pods in each drift state as metrics and a summary in the status of every class.
+type DriftReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// APIReader reads the pods, whose phase is not cached.
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DriftReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&overcommit.Overcommit{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Every scan covers all the classes, a change of any of them triggers a new one
+		Watches(&overcommit.OvercommitClass{}, handler.EnqueueRequestsFromMapFunc(requestsForClass),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("OvercommitDrift").
+		Complete(r)
+}
+
+func (r *DriftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	overcommitResource := &overcommit.Overcommit{}
+	if err := r.Get(ctx, req.NamespacedName, overcommitResource); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.K8sOvercommitOperatorPodsDrift.Reset()
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	overcommitClasses := &overcommit.OvercommitClassList{}
+	if err := r.List(ctx, overcommitClasses); err != nil {
+		logger.Error(err, "Failed to list OvercommitClasses")
+		return ctrl.Result{}, err
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		logger.Error(err, "Failed to list Namespaces")
+		return ctrl.Result{}, err
+	}
+
+	scan := newDriftScan(overcommitResource, overcommitClasses.Items, namespaces.Items)
+	pods, err := r.scanPods(ctx, scan)
+	if err != nil {
+		logger.Error(err, "Failed to list Pods")
+		return ctrl.Result{}, err
+	}
+
+	metrics.K8sOvercommitOperatorPodsDrift.Reset()
+	for key, count := range scan.counts {
+		metrics.K8sOvercommitOperatorPodsDrift.WithLabelValues(key.class, key.namespace, key.state).Set(float64(count))
+	}
+
+	now := metav1.Now()
+	for i := range overcommitClasses.Items {
+		overcommitClass := &overcommitClasses.Items[i]
+		status := scan.status(overcommitClass.Name)
+		status.LastScanTime = &now
+		patch := client.MergeFrom(overcommitClass.DeepCopy())
+		overcommitClass.Status.Drift = &status
+		if err := r.Status().Patch(ctx, overcommitClass, patch); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "Failed to update the drift status", "class", overcommitClass.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger.Info("Drift scan completed", "pods", pods, "classes", len(overcommitClasses.Items))
+	return ctrl.Result{RequeueAfter: driftScanPeriod}, nil
+}
+
+// driftKey identifies a series of the drift metric.
+type driftKey struct {
+	class     string
+	namespace string
+	state     string
+}
+
+// routeKey identifies the pods of a namespace requesting a class with their class label, or not labeled.
+type routeKey struct {
+	namespace string
+	labeled   bool
+	class     string
+}
+
+// driftScan classifies the pods against the classes, the Overcommit and the namespaces of the cluster.
+type driftScan struct {
+	overcommitResource *overcommit.Overcommit
+	label              string
+	classList          []overcommit.OvercommitClass
+	classes            map[string]*overcommit.OvercommitClass
+	namespaces         map[string]*corev1.Namespace
+	// routes caches the class in whose scope are the pods of a namespace requesting a class
+	routes map[routeKey]*overcommit.OvercommitClass
+
+	// counts are the pods in each drift state, by the class they record or request
+	counts map[driftKey]int32
+	// summaries are the pods in each drift state, by the class in whose scope they are
+	summaries map[string]*overcommit.DriftStatus
+}
+
+func newDriftScan(overcommitResource *overcommit.Overcommit, classes []overcommit.OvercommitClass, namespaces []corev1.Namespace) *driftScan {
+	scan := &driftScan{
+		overcommitResource: overcommitResource,
+		label:              overcommitResource.Spec.OvercommitLabel,
+		classList:          classes,
+		classes:            make(map[string]*overcommit.OvercommitClass, len(classes)),
+		namespaces:         make(map[string]*corev1.Namespace, len(namespaces)),
+		routes:             map[routeKey]*overcommit.OvercommitClass{},
+		counts:             map[driftKey]int32{},
+		summaries:          map[string]*overcommit.DriftStatus{},
+	}
+	for i := range classes {
+		scan.classes[classes[i].Name] = &classes[i]
+	}
+	for i := range namespaces {
+		scan.namespaces[namespaces[i].Name] = &namespaces[i]
+	}
+	return scan
+}
+
+// scanPods reads the pods by pages, adding to the scan the running ones: finished pods keep the requests they
+// were created with but no longer use them. It returns the number of pods scanned.
+func (r *DriftReconciler) scanPods(ctx context.Context, scan *driftScan) (int, error) {
+	scanned := 0
+	list := &corev1.PodList{}
+	for {
+		if err := r.APIReader.List(ctx, list, client.Limit(podPageSize), client.Continue(list.Continue)); err != nil {
+			return 0, err
+		}
+		for i := range list.Items {
+			pod := &list.Items[i]
+			if pod.DeletionTimestamp.IsZero() && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				scan.add(pod)
+				scanned++
+			}
+		}
+		if list.Continue == "" {
+			return scanned, nil
+		}
+	}
+}
+
+// add classifies a pod. A pod mutated by an existing class is reported under it, as current or outdated. A pod
+// mutated by a deleted class is orphaned, reported in the metrics under the deleted class and in the status of
+// the class it is now in scope of. A pod never mutated is unmutated when in scope of a class, and orphaned in the
+// metrics under an empty class when its label requests a class that does not exist, as the label may hold any value.
+func (s *driftScan) add(pod *corev1.Pod) {
+	annotations := pod.GetAnnotations()
+	if applied, ok := annotations[overcommit.AppliedAnnotation]; ok {
+		if overcommitClass, exists := s.classes[applied]; exists {
+			state := driftCurrent
+			if utils.OvercommitOutdated(annotations, &overcommitClass.Spec) {
+				state = driftOutdated
+			}
+			s.count(applied, applied, pod.Namespace, state)
+			return
+		}
+		scope := ""
+		if overcommitClass := s.scope(pod); overcommitClass != nil {
+			scope = overcommitClass.Name
+		}
+		s.count(applied, scope, pod.Namespace, driftOrphaned)
+		return
+	}
+
+	if requested, ok := pod.GetLabels()[s.label]; ok && s.label != "" && s.classes[requested] == nil {
+		s.count("", "", pod.Namespace, driftOrphaned)
+		return
+	}
+	// Reverted pods opted out of the overcommit
+	if annotations[overcommitpkg.AnnotationOvercommitRevert] == "true" {
+		return
+	}
+	if overcommitClass := s.scope(pod); overcommitClass != nil {
+		s.count(overcommitClass.Name, overcommitClass.Name, pod.Namespace, driftUnmutated)
+	}
+}
+
+// count records a pod in the metrics under class and in the status of scope, when set.
+func (s *driftScan) count(class, scope, namespace, state string) {
+	s.counts[driftKey{class: class, namespace: namespace, state: state}]++
+	if scope == "" {
+		return
+	}
+	summary := s.summaries[scope]
+	if summary == nil {
+		summary = &overcommit.DriftStatus{}
+		s.summaries[scope] = summary
+	}
+	switch state {
+	case driftCurrent:
+		summary.CurrentPods++
+	case driftOutdated:
+		summary.OutdatedPods++
+	case driftOrphaned:
+		summary.OrphanedPods++
+	case driftUnmutated:
+		summary.UnmutatedPods++
+	}
+}
+
+// scope returns the class the mutating webhooks would apply to the pod if it was created now, nil when the
+// pod would be left untouched. The pods are routed like the webhooks route them, once for all the pods of a
+// namespace requesting the same class.
+func (s *driftScan) scope(pod *corev1.Pod) *overcommit.OvercommitClass {
+	namespace := s.namespaces[pod.Namespace]
+	if namespace == nil {
+		return nil
+	}
+	requested, labeled := pod.GetLabels()[s.label]
+	key := routeKey{namespace: namespace.Name, labeled: labeled, class: requested}
+	overcommitClass, cached := s.routes[key]
+	if !cached {
+		// Invalid exclusions are rejected by the validating webhook, the pods are out of scope like in an
+		// excluded namespace
+		route, _ := overcommitpkg.RouteClass(s.overcommitResource, s.classList, namespace, pod.GetLabels())
+		overcommitClass = route.Class
+		s.routes[key] = overcommitClass
+	}
+	return overcommitClass
+}
+
+// status returns the summary of the pods in scope of a class.
+func (s *driftScan) status(class string) overcommit.DriftStatus {
+	if summary := s.summaries[class]; summary != nil {
+		return *summary
+	}
+	return overcommit.DriftStatus{}
+}
+
+// requestsForClass enqueues the Overcommit singleton when a class changes.
+func requestsForClass(_ context.Context, _ client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: "cluster"}}}
+}
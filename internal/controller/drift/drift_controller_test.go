@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+)
+
+var _ = Describe("Drift", func() {
+	var (
+		k8sClient client.Client
+		r         *DriftReconciler
+	)
+
+	pod := func(namespace, name string, labels, annotations map[string]string) *corev1.Pod {
+		pod := controllertest.Pod(namespace, name, "")
+		pod.Labels, pod.Annotations = labels, annotations
+		return pod
+	}
+	finished := func(pod *corev1.Pod, phase corev1.PodPhase) *corev1.Pod {
+		pod.Status.Phase = phase
+		return pod
+	}
+	classLabel, mutated := controllertest.ClassLabel, controllertest.Mutated
+	class := func(name string, isDefault bool) *overcommit.OvercommitClass {
+		class := controllertest.Class(name, 0.5, 0.8)
+		class.Spec.IsDefault, class.Spec.ExcludedNamespaces = isDefault, "^kube-.*"
+		return class
+	}
+	drift := func(class, namespace, state string) float64 {
+		return testutil.ToFloat64(metrics.K8sOvercommitOperatorPodsDrift.WithLabelValues(class, namespace, state))
+	}
+
+	BeforeEach(func() {
+		k8sClient = controllertest.NewClientBuilder().
+			WithStatusSubresource(&overcommit.OvercommitClass{}).
+			WithObjects(
+				controllertest.Cluster(),
+				class("standard", true),
+				class("high", false),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "batch", Labels: map[string]string{classLabel: "high"}}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+				pod("apps", "current", nil, mutated("standard", "0.5000", "0.8000")),
+				pod("apps", "outdated", nil, mutated("standard", "0.4000", "0.8000")),
+				pod("apps", "orphaned", nil, mutated("removed", "0.5000", "0.8000")),
+				pod("apps", "unmutated", nil, nil),
+				pod("apps", "requesting-removed", map[string]string{classLabel: "removed"}, nil),
+				pod("apps", "reverted", nil, map[string]string{overcommitpkg.AnnotationOvercommitRevert: "true"}),
+				pod("batch", "unmutated", nil, nil),
+				pod("kube-system", "excluded", nil, nil),
+				finished(pod("apps", "completed", nil, mutated("standard", "0.4000", "0.8000")), corev1.PodSucceeded),
+				finished(pod("apps", "failed", nil, nil), corev1.PodFailed),
+			).
+			Build()
+		r = &DriftReconciler{Client: k8sClient, Scheme: controllertest.Scheme(), APIReader: k8sClient}
+	})
+
+	It("should report the drift of the pods in the metrics", func() {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "cluster"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(drift("standard", "apps", driftCurrent)).To(Equal(1.0))
+		Expect(drift("standard", "apps", driftOutdated)).To(Equal(1.0))
+		Expect(drift("removed", "apps", driftOrphaned)).To(Equal(1.0))
+		// The classes requested by the labels are not recorded
+		Expect(drift("", "apps", driftOrphaned)).To(Equal(1.0))
+		Expect(drift("standard", "apps", driftUnmutated)).To(Equal(1.0))
+		// Pods without a class label take the class of their namespace
+		Expect(drift("high", "batch", driftUnmutated)).To(Equal(1.0))
+		// Pods in excluded namespaces are out of scope, and finished pods are not counted
+		Expect(testutil.CollectAndCount(metrics.K8sOvercommitOperatorPodsDrift)).To(Equal(6))
+	})
+
+	It("should summarize the drift in the status of the classes", func() {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "cluster"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		standard := &overcommit.OvercommitClass{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "standard"}, standard)).To(Succeed())
+		Expect(standard.Status.Drift).NotTo(BeNil())
+		Expect(standard.Status.Drift.CurrentPods).To(Equal(int32(1)))
+		Expect(standard.Status.Drift.OutdatedPods).To(Equal(int32(1)))
+		// The pod of the removed class is now in scope of the default class
+		Expect(standard.Status.Drift.OrphanedPods).To(Equal(int32(1)))
+		Expect(standard.Status.Drift.UnmutatedPods).To(Equal(int32(1)))
+		Expect(standard.Status.Drift.LastScanTime).NotTo(BeNil())
+
+		high := &overcommit.OvercommitClass{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "high"}, high)).To(Succeed())
+		Expect(high.Status.Drift.UnmutatedPods).To(Equal(int32(1)))
+		Expect(high.Status.Drift.CurrentPods).To(BeZero())
+	})
+
+	It("should not count unmutated pods while the Overcommit is paused", func() {
+		overcommitResource := &overcommit.Overcommit{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "cluster"}, overcommitResource)).To(Succeed())
+		overcommitResource.Spec.Paused = true
+		Expect(k8sClient.Update(context.Background(), overcommitResource)).To(Succeed())
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "cluster"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift("standard", "apps", driftUnmutated)).To(BeZero())
+		Expect(drift("standard", "apps", driftOutdated)).To(Equal(1.0))
+	})
+})
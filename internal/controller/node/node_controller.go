@@ -0,0 +1,350 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// nodeScanPeriod is how often the requests and limits of the pods are compared with the nodes.
+	nodeScanPeriod = 5 * time.Minute
+	// podPageSize is the number of pods read from the API server at once.
+	podPageSize = 500
+)
+
+// scannedResources are the resources compared with the allocatable resources of the nodes.
+var scannedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// NodeReconciler regularly compares the requests and limits of the pods scheduled on every node with its
+// allocatable resources, publishing the ratios by node and class as metrics and a cluster-wide summary in the
+// status of the Overcommit.
+type NodeReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// APIReader reads the pods, whose specs are not cached.
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&overcommit.Overcommit{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("OvercommitNodes").
+		Complete(r)
+}
+
+func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	overcommitResource := &overcommit.Overcommit{}
+	if err := r.Get(ctx, req.NamespacedName, overcommitResource); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.K8sOvercommitOperatorNodeRequestsRatio.Reset()
+			metrics.K8sOvercommitOperatorNodeLimitsRatio.Reset()
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		logger.Error(err, "Failed to list Nodes")
+		return ctrl.Result{}, err
+	}
+	pods, err := r.listPods(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to list Pods")
+		return ctrl.Result{}, err
+	}
+
+	scan := newNodeScan(nodes.Items)
+	for i := range pods {
+		scan.add(&pods[i])
+	}
+
+	metrics.K8sOvercommitOperatorNodeRequestsRatio.Reset()
+	metrics.K8sOvercommitOperatorNodeLimitsRatio.Reset()
+	for key, used := range scan.usage {
+		allocatable := scan.allocatable[key.node][key.resource]
+		if allocatable.IsZero() {
+			continue
+		}
+		metrics.K8sOvercommitOperatorNodeRequestsRatio.WithLabelValues(key.node, key.class, string(key.resource)).
+			Set(fraction(&used.requests, &allocatable))
+		metrics.K8sOvercommitOperatorNodeLimitsRatio.WithLabelValues(key.node, key.class, string(key.resource)).
+			Set(fraction(&used.limits, &allocatable))
+	}
+
+	status := scan.status()
+	now := metav1.Now()
+	status.LastScanTime = &now
+	patch := client.MergeFrom(overcommitResource.DeepCopy())
+	overcommitResource.Status.Nodes = status
+	if err := r.Status().Patch(ctx, overcommitResource, patch); client.IgnoreNotFound(err) != nil {
+		logger.Error(err, "Failed to update the node status")
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Node scan completed", "nodes", len(nodes.Items), "pods", len(pods))
+	return ctrl.Result{RequeueAfter: nodeScanPeriod}, nil
+}
+
+// listPods reads the pods by pages, keeping the ones scheduled on a node and not terminated.
+func (r *NodeReconciler) listPods(ctx context.Context) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+	list := &corev1.PodList{}
+	for {
+		if err := r.APIReader.List(ctx, list, client.Limit(podPageSize), client.Continue(list.Continue)); err != nil {
+			return nil, err
+		}
+		for _, pod := range list.Items {
+			if pod.Spec.NodeName != "" && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				pods = append(pods, pod)
+			}
+		}
+		if list.Continue == "" {
+			return pods, nil
+		}
+	}
+}
+
+// usageKey identifies the pods of a class on a node, for a resource.
+type usageKey struct {
+	node     string
+	class    string
+	resource corev1.ResourceName
+}
+
+// usage holds the requests and limits of a set of pods for a resource.
+type usage struct {
+	requests resource.Quantity
+	limits   resource.Quantity
+}
+
+// nodeScan sums the requests and limits of the pods by node and by the class recorded on them.
+type nodeScan struct {
+	allocatable map[string]corev1.ResourceList
+	usage       map[usageKey]*usage
+	// pods are the pods of every class
+	pods map[string]int32
+}
+
+func newNodeScan(nodes []corev1.Node) *nodeScan {
+	scan := &nodeScan{
+		allocatable: make(map[string]corev1.ResourceList, len(nodes)),
+		usage:       map[usageKey]*usage{},
+		pods:        map[string]int32{},
+	}
+	for _, node := range nodes {
+		scan.allocatable[node.Name] = node.Status.Allocatable
+	}
+	return scan
+}
+
+// add records the requests and limits of a pod under its node and the class recorded in its applied annotation,
+// empty for a pod never mutated. Pods of nodes that no longer exist are ignored.
+func (s *nodeScan) add(pod *corev1.Pod) {
+	if _, exists := s.allocatable[pod.Spec.NodeName]; !exists {
+		return
+	}
+	class := pod.Annotations[overcommit.AppliedAnnotation]
+	s.pods[class]++
+	requests := podResources(&pod.Spec, false)
+	limits := podResources(&pod.Spec, true)
+	for _, name := range scannedResources {
+		key := usageKey{node: pod.Spec.NodeName, class: class, resource: name}
+		if s.usage[key] == nil {
+			s.usage[key] = &usage{}
+		}
+		if quantity, ok := requests[name]; ok {
+			s.usage[key].requests.Add(quantity)
+		}
+		if quantity, ok := limits[name]; ok {
+			s.usage[key].limits.Add(quantity)
+		}
+	}
+}
+
+// status summarizes the scan for the whole cluster. The classes are the ones recorded on the pods, sorted by
+// name, leaving out the pods never mutated.
+func (s *nodeScan) status() *overcommit.NodeOvercommitStatus {
+	status := &overcommit.NodeOvercommitStatus{Nodes: int32(len(s.allocatable))}
+	for _, name := range scannedResources {
+		summary := s.summary(name)
+		if name == corev1.ResourceCPU {
+			status.Cpu = summary
+		} else {
+			status.Memory = summary
+		}
+	}
+
+	classes := map[string]*overcommit.ClassNodeOvercommit{}
+	for key, used := range s.usage {
+		if key.class == "" {
+			continue
+		}
+		if classes[key.class] == nil {
+			classes[key.class] = &overcommit.ClassNodeOvercommit{Class: key.class, Pods: s.pods[key.class]}
+		}
+		class := classes[key.class]
+		if key.resource == corev1.ResourceCPU {
+			class.CpuRequestsRatio += used.requests.AsApproximateFloat64()
+			class.CpuLimitsRatio += used.limits.AsApproximateFloat64()
+		} else {
+			class.MemoryRequestsRatio += used.requests.AsApproximateFloat64()
+			class.MemoryLimitsRatio += used.limits.AsApproximateFloat64()
+		}
+	}
+	cpu := status.Cpu.Allocatable.AsApproximateFloat64()
+	memory := status.Memory.Allocatable.AsApproximateFloat64()
+	for _, class := range classes {
+		class.CpuRequestsRatio = ratio(class.CpuRequestsRatio, cpu)
+		class.CpuLimitsRatio = ratio(class.CpuLimitsRatio, cpu)
+		class.MemoryRequestsRatio = ratio(class.MemoryRequestsRatio, memory)
+		class.MemoryLimitsRatio = ratio(class.MemoryLimitsRatio, memory)
+		status.Classes = append(status.Classes, *class)
+	}
+	sort.Slice(status.Classes, func(i, j int) bool { return status.Classes[i].Class < status.Classes[j].Class })
+	return status
+}
+
+// summary sums the allocatable resources, requests and limits of every node for a resource, finding the node
+// with the highest limits ratio.
+func (s *nodeScan) summary(name corev1.ResourceName) overcommit.ResourceOvercommit {
+	nodeRequests := map[string]*resource.Quantity{}
+	nodeLimits := map[string]*resource.Quantity{}
+	for key, used := range s.usage {
+		if key.resource != name {
+			continue
+		}
+		if nodeRequests[key.node] == nil {
+			nodeRequests[key.node], nodeLimits[key.node] = &resource.Quantity{}, &resource.Quantity{}
+		}
+		nodeRequests[key.node].Add(used.requests)
+		nodeLimits[key.node].Add(used.limits)
+	}
+
+	summary := overcommit.ResourceOvercommit{}
+	nodes := make([]string, 0, len(s.allocatable))
+	for node := range s.allocatable {
+		nodes = append(nodes, node)
+	}
+	// Nodes are visited by name for the most overcommitted one to be stable between scans
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		allocatable, ok := s.allocatable[node][name]
+		if !ok {
+			continue
+		}
+		summary.Allocatable.Add(allocatable)
+		if nodeRequests[node] == nil {
+			continue
+		}
+		summary.Requests.Add(*nodeRequests[node])
+		summary.Limits.Add(*nodeLimits[node])
+		if limitsRatio := ratio(nodeLimits[node].AsApproximateFloat64(), allocatable.AsApproximateFloat64()); limitsRatio > summary.MaxLimitsRatio {
+			summary.MaxLimitsRatio = limitsRatio
+			summary.MaxLimitsNode = node
+		}
+	}
+	summary.RequestsRatio = fraction(&summary.Requests, &summary.Allocatable)
+	summary.LimitsRatio = fraction(&summary.Limits, &summary.Allocatable)
+	return summary
+}
+
+// fraction returns the ratio of two quantities.
+func fraction(used, allocatable *resource.Quantity) float64 {
+	return ratio(used.AsApproximateFloat64(), allocatable.AsApproximateFloat64())
+}
+
+// ratio returns used against allocatable rounded to two decimals, 0 without allocatable resources.
+func ratio(used, allocatable float64) float64 {
+	if allocatable <= 0 {
+		return 0
+	}
+	return math.Round(used/allocatable*100) / 100
+}
+
+// podResources returns the resources of a pod as the scheduler accounts for them: the sum of its containers and
+// sidecars, or the largest regular init container along with the sidecars started before it if greater, plus
+// the overhead of its runtime class. The requests are returned, or the limits with limits, the containers not
+// setting a resource not counting in it.
+func podResources(spec *corev1.PodSpec, limits bool) corev1.ResourceList {
+	source := func(resources corev1.ResourceRequirements) corev1.ResourceList {
+		if limits {
+			return resources.Limits
+		}
+		return resources.Requests
+	}
+	total := corev1.ResourceList{}
+	for _, container := range spec.Containers {
+		addResources(total, source(container.Resources))
+	}
+
+	sidecars := corev1.ResourceList{}
+	initialization := corev1.ResourceList{}
+	for _, container := range spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addResources(total, source(container.Resources))
+			addResources(sidecars, source(container.Resources))
+			continue
+		}
+		running := sidecars.DeepCopy()
+		addResources(running, source(container.Resources))
+		maxResources(initialization, running)
+	}
+	maxResources(total, initialization)
+
+	for name, quantity := range spec.Overhead {
+		// The overhead only counts in the limits set by the containers
+		if value, ok := total[name]; ok || !limits {
+			value.Add(quantity)
+			total[name] = value
+		}
+	}
+	return total
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		value := total[name]
+		value.Add(quantity)
+		total[name] = value
+	}
+}
+
+func maxResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		if value, ok := total[name]; !ok || quantity.Cmp(value) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+)
+
+var _ = Describe("Node", func() {
+	var (
+		k8sClient client.Client
+		r         *NodeReconciler
+	)
+
+	resources, container := controllertest.Resources, controllertest.Container
+	node := func(name, cpu, memory string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: corev1.NodeStatus{Allocatable: resources(cpu, memory)}}
+	}
+	pod := func(name, nodeName, class string, containers ...corev1.Container) *corev1.Pod {
+		pod := controllertest.Pod("apps", name, class, containers...)
+		pod.Spec.NodeName = nodeName
+		return pod
+	}
+	limitsRatio := func(node, class, resource string) float64 {
+		return testutil.ToFloat64(metrics.K8sOvercommitOperatorNodeLimitsRatio.WithLabelValues(node, class, resource))
+	}
+	requestsRatio := func(node, class, resource string) float64 {
+		return testutil.ToFloat64(metrics.K8sOvercommitOperatorNodeRequestsRatio.WithLabelValues(node, class, resource))
+	}
+
+	BeforeEach(func() {
+		finished := pod("finished", "node-1", "high", container(resources("4", "4Gi"), resources("4", "4Gi")))
+		finished.Status.Phase = corev1.PodSucceeded
+		k8sClient = controllertest.NewClientBuilder().
+			WithStatusSubresource(&overcommit.Overcommit{}).
+			WithObjects(
+				&overcommit.Overcommit{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+				node("node-1", "4", "8Gi"),
+				node("node-2", "4", "8Gi"),
+				pod("web", "node-1", "high", container(resources("1", "2Gi"), resources("4", "8Gi"))),
+				pod("api", "node-1", "high", container(resources("1", "2Gi"), resources("2", "4Gi"))),
+				pod("db", "node-2", "", container(resources("2", "4Gi"), resources("2", "4Gi"))),
+				// Best effort pods count in neither the requests nor the limits
+				pod("debug", "node-2", "", container(nil, nil)),
+				// Pods not running on a node are left out
+				pod("pending", "", "high", container(resources("4", "4Gi"), resources("4", "4Gi"))),
+				finished,
+				pod("gone", "node-3", "high", container(resources("4", "4Gi"), resources("4", "4Gi"))),
+			).
+			Build()
+		r = &NodeReconciler{Client: k8sClient, Scheme: controllertest.Scheme(), APIReader: k8sClient}
+		metrics.K8sOvercommitOperatorNodeRequestsRatio.Reset()
+		metrics.K8sOvercommitOperatorNodeLimitsRatio.Reset()
+	})
+
+	It("publishes the ratios of every node by class", func() {
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "cluster"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(nodeScanPeriod))
+
+		Expect(requestsRatio("node-1", "high", "cpu")).To(Equal(0.5))
+		Expect(limitsRatio("node-1", "high", "cpu")).To(Equal(1.5))
+		Expect(limitsRatio("node-1", "high", "memory")).To(Equal(1.5))
+		Expect(requestsRatio("node-2", "", "memory")).To(Equal(0.5))
+		Expect(limitsRatio("node-2", "", "cpu")).To(Equal(0.5))
+		Expect(testutil.CollectAndCount(metrics.K8sOvercommitOperatorNodeLimitsRatio)).To(Equal(4))
+	})
+
+	It("summarizes the nodes in the status of the Overcommit", func() {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "cluster"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &overcommit.Overcommit{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "cluster"}, updated)).To(Succeed())
+		status := updated.Status.Nodes
+		Expect(status).NotTo(BeNil())
+		Expect(status.LastScanTime).NotTo(BeNil())
+		Expect(status.Nodes).To(Equal(int32(2)))
+
+		Expect(status.Cpu.Allocatable.Cmp(resource.MustParse("8"))).To(Equal(0))
+		Expect(status.Cpu.Requests.Cmp(resource.MustParse("4"))).To(Equal(0))
+		Expect(status.Cpu.Limits.Cmp(resource.MustParse("8"))).To(Equal(0))
+		Expect(status.Cpu.RequestsRatio).To(Equal(0.5))
+		Expect(status.Cpu.LimitsRatio).To(Equal(1.0))
+		Expect(status.Cpu.MaxLimitsRatio).To(Equal(1.5))
+		Expect(status.Cpu.MaxLimitsNode).To(Equal("node-1"))
+		Expect(status.Memory.LimitsRatio).To(Equal(1.0))
+
+		Expect(status.Classes).To(Equal([]overcommit.ClassNodeOvercommit{{
+			Class:               "high",
+			Pods:                2,
+			CpuRequestsRatio:    0.25,
+			CpuLimitsRatio:      0.75,
+			MemoryRequestsRatio: 0.25,
+			MemoryLimitsRatio:   0.75,
+		}}))
+	})
+
+	It("clears the metrics when the Overcommit is deleted", func() {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "cluster"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Delete(context.Background(), &overcommit.Overcommit{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})).To(Succeed())
+
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "cluster"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(testutil.CollectAndCount(metrics.K8sOvercommitOperatorNodeLimitsRatio)).To(BeZero())
+	})
+
+	Describe("podResources", func() {
+		It("accounts for the init containers, the sidecars and the overhead", func() {
+			spec := &corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					{Name: "sidecar", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways), Resources: corev1.ResourceRequirements{
+						Requests: resources("100m", "64Mi"),
+					}},
+					{Name: "migrate", Resources: corev1.ResourceRequirements{
+						Requests: resources("2", "128Mi"),
+						Limits:   resources("2", "128Mi"),
+					}},
+				},
+				Containers: []corev1.Container{container(resources("500m", "1Gi"), corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")})},
+				Overhead:   resources("50m", "16Mi"),
+			}
+
+			requests := podResources(spec, false)
+			// The migration with the sidecar requests more CPU than the app and the sidecar
+			Expect(requests.Cpu().MilliValue()).To(Equal(int64(2150)))
+			Expect(requests.Memory().Value()).To(Equal(int64((1024 + 64 + 16) * 1024 * 1024)))
+
+			limits := podResources(spec, true)
+			Expect(limits.Cpu().MilliValue()).To(Equal(int64(2050)))
+			Expect(limits.Memory().Value()).To(Equal(int64((2048 + 16) * 1024 * 1024)))
+		})
+
+		It("leaves the overhead out of the limits not set", func() {
+			spec := &corev1.PodSpec{
+				Containers: []corev1.Container{container(resources("500m", "1Gi"), nil)},
+				Overhead:   resources("50m", "16Mi"),
+			}
+
+			Expect(podResources(spec, true)).To(BeEmpty())
+			requests := podResources(spec, false)
+			Expect(requests.Cpu().MilliValue()).To(Equal(int64(550)))
+		})
+	})
+})
@@ -9,10 +9,9 @@ package controller
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
-	"k8s.io/apimachinery/pkg/api/errors"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,6 +19,9 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certs"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
 	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 )
@@ -30,6 +32,7 @@ var logger = logf.Log.WithName("overcommit_controller")
 type OvercommitReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	Config config.Config
 }
 
 // +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommits,verbs=get;list;watch;create;update;patch;delete
@@ -48,15 +51,9 @@ type OvercommitReconciler struct {
 func (r *OvercommitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger.Info("Starting reconciliation", "name", req.Name, "namespace", req.Namespace, "time", time.Now().Format("15:04:05"))
 
-	label, err := utils.GetOvercommitLabel(ctx, r.Client)
-	if err != nil {
-		logger.Error(err, "Failed to get Overcommit")
-		return ctrl.Result{}, err
-	}
-
 	overcommit := &overcommit.Overcommit{}
 
-	err = r.Get(ctx, req.NamespacedName, overcommit)
+	err := r.Get(ctx, req.NamespacedName, overcommit)
 	if err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			return ctrl.Result{}, err
@@ -91,294 +88,140 @@ func (r *OvercommitReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{RequeueAfter: time.Second * 1}, nil
 	}
 
+	// Tear down every generated object before letting the Overcommit go. The per-class mutating webhooks
+	// would otherwise outlive the services they call and block the admission of pods.
+	if !overcommit.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(overcommit, utils.CleanupFinalizer) {
+			logger.Info("Overcommit deleted, removing the generated resources")
+			if err = utils.DeleteResources(ctx, r.Client, r.Config.PodNamespace); err != nil {
+				logger.Error(err, "Failed to remove the generated resources")
+				return ctrl.Result{}, err
+			}
+			controllerutil.RemoveFinalizer(overcommit, utils.CleanupFinalizer)
+			if err = r.Update(ctx, overcommit); err != nil {
+				logger.Error(err, "Failed to remove the cleanup finalizer")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if controllerutil.AddFinalizer(overcommit, utils.CleanupFinalizer) {
+		if err = r.Update(ctx, overcommit); err != nil {
+			logger.Error(err, "Failed to add the cleanup finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The mutating webhooks are removed by the OvercommitClass controller while paused
+	if overcommit.Spec.Paused {
+		logger.Info("Overcommit is paused, pods will not be mutated")
+		metrics.K8sOvercommitOperatorPaused.Set(1)
+	} else {
+		metrics.K8sOvercommitOperatorPaused.Set(0)
+	}
+
 	// Reconcile Issuer
-	issuer := resources.GenerateIssuer()
+	issuer := resources.GenerateIssuer(r.Config)
 	if issuer == nil {
 		logger.Error(nil, "Generated issuer is nil")
 		return ctrl.Result{}, fmt.Errorf("generated issuer is nil")
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, issuer, func() error {
-		// Only set controller reference if this is a new resource
-		if issuer.CreationTimestamp.IsZero() {
-			return ctrl.SetControllerReference(overcommit, issuer, r.Scheme)
+	// With builtin certificates the operator acts as its own CA instead of relying on cert-manager
+	var ca *certs.CA
+	var renewals []time.Time
+	if overcommit.UsesBuiltinCertificates() {
+		ca, err = certs.EnsureCA(ctx, r.Client, r.Scheme, r.Config.PodNamespace, overcommit)
+		if err != nil {
+			logger.Error(err, "Failed to reconcile CA")
+			return ctrl.Result{}, err
 		}
-		return nil
-	})
-	if err != nil {
+		if err = r.deleteCertManagerResources(ctx, issuer); err != nil {
+			logger.Error(err, "Failed to remove cert-manager resources")
+			return ctrl.Result{}, err
+		}
+	} else if err = utils.Apply(ctx, r.Client, overcommit, issuer); err != nil {
 		logger.Error(err, "Failed to reconcile issuer")
 		return ctrl.Result{}, err
 	}
 
+	// reconcileCertificate issues the serving certificate of a webhook, either with cert-manager or with the builtin CA
+	reconcileCertificate := func(certificate *certmanagerv1.Certificate) error {
+		if ca == nil {
+			return utils.Apply(ctx, r.Client, overcommit, certificate)
+		}
+		renewal, err := certs.EnsureServingCertificate(ctx, r.Client, r.Scheme, ca, certificate.Namespace,
+			certificate.Spec.SecretName, certificate.Spec.DNSNames, overcommit)
+		if err != nil {
+			return err
+		}
+		renewals = append(renewals, renewal)
+		return nil
+	}
+
 	// Reconcile OvercommitClassValidator
-	overcommitClassDeployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommit)
+	overcommitClassDeployment := resources.GenerateOvercommitClassValidatingDeployment(r.Config, *overcommit)
 	overcommitClassService := resources.GenerateOvercommitClassValidatingService(*overcommitClassDeployment)
 	overcommitClassCertificate := resources.GenerateCertificateValidatingOvercommitClass(*issuer, *overcommitClassService)
-	overcommitClassWebhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate)
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, overcommitClassCertificate, func() error {
-		// Only set spec if this is a new resource or there are changes
-		if overcommitClassCertificate.CreationTimestamp.IsZero() {
-			updatedCertificate := resources.GenerateCertificateValidatingOvercommitClass(*issuer, *overcommitClassService)
-			overcommitClassCertificate.Spec = updatedCertificate.Spec
-			return ctrl.SetControllerReference(overcommit, overcommitClassCertificate, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
+	overcommitClassWebhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate, overcommit.Spec.Webhook)
+	if ca != nil {
+		resources.SetValidatingWebhookCABundle(overcommitClassWebhook, ca.CertPEM)
+	}
+
+	if err = reconcileCertificate(overcommitClassCertificate); err != nil {
 		logger.Error(err, "Failed to reconcile OvercommitClass Certificate")
 		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, overcommitClassDeployment, func() error {
-		// Regenerate the desired deployment spec
-		updatedDeployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommit)
-		updatedDeployment.Spec.Template.Spec.Containers[0].Image = os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION")
-
-		// Only update if there are actual differences
-		if overcommitClassDeployment.CreationTimestamp.IsZero() {
-			// New deployment, set everything
-			overcommitClassDeployment.Spec = updatedDeployment.Spec
-			overcommitClassDeployment.Labels = updatedDeployment.Labels
-			overcommitClassDeployment.Annotations = updatedDeployment.Annotations
-			return ctrl.SetControllerReference(overcommit, overcommitClassDeployment, r.Scheme)
-		}
-		// Existing deployment, only update specific fields if needed
-		updated := false
-		if updatedDeployment.Spec.Template.Spec.Containers[0].Image != overcommitClassDeployment.Spec.Template.Spec.Containers[0].Image {
-			overcommitClassDeployment.Spec.Template.Spec.Containers[0].Image = updatedDeployment.Spec.Template.Spec.Containers[0].Image
-			updated = true
-		}
-		// Update environment variables if they changed
-		if !envVarsEqual(updatedDeployment.Spec.Template.Spec.Containers[0].Env, overcommitClassDeployment.Spec.Template.Spec.Containers[0].Env) {
-			overcommitClassDeployment.Spec.Template.Spec.Containers[0].Env = updatedDeployment.Spec.Template.Spec.Containers[0].Env
-			updated = true
-		}
-		// Update template annotations if they changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Annotations, overcommitClassDeployment.Spec.Template.Annotations) {
-			overcommitClassDeployment.Spec.Template.Annotations = updatedDeployment.Spec.Template.Annotations
-			updated = true
-		}
-		// Update template labels if they changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Labels, overcommitClassDeployment.Spec.Template.Labels) {
-			overcommitClassDeployment.Spec.Template.Labels = updatedDeployment.Spec.Template.Labels
-			updated = true
-		}
-		// Update nodeSelector if it changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Spec.NodeSelector, overcommitClassDeployment.Spec.Template.Spec.NodeSelector) {
-			overcommitClassDeployment.Spec.Template.Spec.NodeSelector = updatedDeployment.Spec.Template.Spec.NodeSelector
-			updated = true
-		}
-		// Update tolerations if they changed
-		if !utils.TolerationsEqual(ctx, updatedDeployment.Spec.Template.Spec.Tolerations, overcommitClassDeployment.Spec.Template.Spec.Tolerations) {
-			overcommitClassDeployment.Spec.Template.Spec.Tolerations = updatedDeployment.Spec.Template.Spec.Tolerations
-			updated = true
-		}
-		// Only set controller reference if we actually updated something
-		if updated {
-			return ctrl.SetControllerReference(overcommit, overcommitClassDeployment, r.Scheme)
-		}
-
-		return nil
-	})
-	if err != nil {
+	if err = utils.Apply(ctx, r.Client, overcommit, overcommitClassDeployment); err != nil {
 		logger.Error(err, "Failed to reconcile OvercommitClass Deployment")
 		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, overcommitClassService, func() error {
-		// Only update spec if this is a new resource
-		if overcommitClassService.CreationTimestamp.IsZero() {
-			updatedService := resources.GenerateOvercommitClassValidatingService(*overcommitClassDeployment)
-			overcommitClassService.Spec = updatedService.Spec
-			return ctrl.SetControllerReference(overcommit, overcommitClassService, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
+	if err = utils.Apply(ctx, r.Client, overcommit, overcommitClassService); err != nil {
 		logger.Error(err, "Failed to reconcile OvercommitClass Service")
 		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, overcommitClassWebhook, func() error {
-		// Only update webhooks if this is a new resource
-		if overcommitClassWebhook.CreationTimestamp.IsZero() {
-			updatedWebhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate)
-			overcommitClassWebhook.Annotations = updatedWebhook.Annotations
-			overcommitClassWebhook.Webhooks = updatedWebhook.Webhooks
-			return ctrl.SetControllerReference(overcommit, overcommitClassWebhook, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
+	if err = utils.Apply(ctx, r.Client, overcommit, overcommitClassWebhook); err != nil {
 		logger.Error(err, "Failed to reconcile OvercommitClass Webhook")
 		return ctrl.Result{}, err
 	}
 
 	// Reconcile PodValidator
-	validatingPodDeployment := resources.GeneratePodValidatingDeployment(*overcommit)
+	validatingPodDeployment := resources.GeneratePodValidatingDeployment(r.Config, *overcommit)
 	validatingPodService := resources.GeneratePodValidatingService(*validatingPodDeployment)
 	validatingpodCertificate := resources.GenerateCertificateValidatingPods(*issuer, *validatingPodService)
-	validatingPodWebhook := resources.GeneratePodValidatingWebhookConfiguration(*validatingPodDeployment, *validatingPodService, *validatingpodCertificate, label)
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, validatingpodCertificate, func() error {
-		// Only update spec if this is a new resource
-		if validatingpodCertificate.CreationTimestamp.IsZero() {
-			updatedCertificate := resources.GenerateCertificateValidatingPods(*issuer, *validatingPodService)
-			validatingpodCertificate.Spec = updatedCertificate.Spec
-			return ctrl.SetControllerReference(overcommit, validatingpodCertificate, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
+	validatingPodWebhook := resources.GeneratePodValidatingWebhookConfiguration(*validatingPodDeployment, *validatingPodService, *validatingpodCertificate, overcommit.Spec.Webhook)
+	if ca != nil {
+		resources.SetValidatingWebhookCABundle(validatingPodWebhook, ca.CertPEM)
+	}
+
+	if err = reconcileCertificate(validatingpodCertificate); err != nil {
 		logger.Error(err, "Failed to reconcile Pod Validating Certificate")
 		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, validatingPodDeployment, func() error {
-		// Regenerate the desired deployment spec
-		updatedDeployment := resources.GeneratePodValidatingDeployment(*overcommit)
-		updatedDeployment.Spec.Template.Spec.Containers[0].Image = os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION")
-
-		// Only update if there are actual differences
-		if validatingPodDeployment.CreationTimestamp.IsZero() {
-			// New deployment, set everything
-			validatingPodDeployment.Spec = updatedDeployment.Spec
-			validatingPodDeployment.Labels = updatedDeployment.Labels
-			validatingPodDeployment.Annotations = updatedDeployment.Annotations
-			return ctrl.SetControllerReference(overcommit, validatingPodDeployment, r.Scheme)
-		}
-		// Existing deployment, only update specific fields if needed
-		updated := false
-		if updatedDeployment.Spec.Template.Spec.Containers[0].Image != validatingPodDeployment.Spec.Template.Spec.Containers[0].Image {
-			validatingPodDeployment.Spec.Template.Spec.Containers[0].Image = updatedDeployment.Spec.Template.Spec.Containers[0].Image
-			updated = true
-		}
-		// Update environment variables if they changed
-		if !envVarsEqual(updatedDeployment.Spec.Template.Spec.Containers[0].Env, validatingPodDeployment.Spec.Template.Spec.Containers[0].Env) {
-			validatingPodDeployment.Spec.Template.Spec.Containers[0].Env = updatedDeployment.Spec.Template.Spec.Containers[0].Env
-			updated = true
-		}
-		// Update template annotations if they changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Annotations, validatingPodDeployment.Spec.Template.Annotations) {
-			validatingPodDeployment.Spec.Template.Annotations = updatedDeployment.Spec.Template.Annotations
-			updated = true
-		}
-		// Update template labels if they changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Labels, validatingPodDeployment.Spec.Template.Labels) {
-			validatingPodDeployment.Spec.Template.Labels = updatedDeployment.Spec.Template.Labels
-			updated = true
-		}
-		// Update nodeSelector if it changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Spec.NodeSelector, validatingPodDeployment.Spec.Template.Spec.NodeSelector) {
-			validatingPodDeployment.Spec.Template.Spec.NodeSelector = updatedDeployment.Spec.Template.Spec.NodeSelector
-			updated = true
-		}
-		// Update tolerations if they changed
-		if !utils.TolerationsEqual(ctx, updatedDeployment.Spec.Template.Spec.Tolerations, validatingPodDeployment.Spec.Template.Spec.Tolerations) {
-			validatingPodDeployment.Spec.Template.Spec.Tolerations = updatedDeployment.Spec.Template.Spec.Tolerations
-			updated = true
-		}
-		// Only set controller reference if we actually updated something
-		if updated {
-			return ctrl.SetControllerReference(overcommit, validatingPodDeployment, r.Scheme)
-		}
-
-		return nil
-	})
-	if err != nil {
+	if err = utils.Apply(ctx, r.Client, overcommit, validatingPodDeployment); err != nil {
 		logger.Error(err, "Failed to reconcile Pod Validating Deployment")
 		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, validatingPodService, func() error {
-		// Only update spec if this is a new resource
-		if validatingPodService.CreationTimestamp.IsZero() {
-			updatedService := resources.GeneratePodValidatingService(*validatingPodDeployment)
-			validatingPodService.Spec = updatedService.Spec
-			return ctrl.SetControllerReference(overcommit, validatingPodService, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
+	if err = utils.Apply(ctx, r.Client, overcommit, validatingPodService); err != nil {
 		logger.Error(err, "Failed to reconcile Pod Validating Service")
 		return ctrl.Result{}, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, validatingPodWebhook, func() error {
-		// Only update webhooks if this is a new resource
-		if validatingPodWebhook.CreationTimestamp.IsZero() {
-			updatedWebhook := resources.GeneratePodValidatingWebhookConfiguration(*validatingPodDeployment, *validatingPodService, *validatingpodCertificate, label)
-			validatingPodWebhook.Webhooks = updatedWebhook.Webhooks
-			return ctrl.SetControllerReference(overcommit, validatingPodWebhook, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
+	if err = utils.Apply(ctx, r.Client, overcommit, validatingPodWebhook); err != nil {
 		logger.Error(err, "Failed to reconcile Pod Validating Webhook")
-		// For resource conflicts, don't fail the reconciliation to avoid immediate retry
-		if errors.IsConflict(err) {
-			logger.Info("Resource conflict detected, will retry in next scheduled reconciliation")
-		} else {
-			return ctrl.Result{}, err
-		}
+		return ctrl.Result{}, err
 	}
 
 	// Reconcile Overcommit Class Controller
-	occontroller := resources.GenerateOvercommitClassControllerDeployment(*overcommit)
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, occontroller, func() error {
-		// Regenerate the desired deployment spec
-		updatedDeployment := resources.GenerateOvercommitClassControllerDeployment(*overcommit)
-		updatedDeployment.Spec.Template.Spec.Containers[0].Image = os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION")
-
-		// Only update if there are actual differences
-		if occontroller.CreationTimestamp.IsZero() {
-			// New deployment, set everything
-			occontroller.Spec = updatedDeployment.Spec
-			occontroller.Labels = updatedDeployment.Labels
-			occontroller.Annotations = updatedDeployment.Annotations
-			logger.Info("Creating new OvercommitClass Controller deployment")
-			return ctrl.SetControllerReference(overcommit, occontroller, r.Scheme)
-		}
-		// Existing deployment, only update specific fields if needed
-		updated := false
-		if updatedDeployment.Spec.Template.Spec.Containers[0].Image != occontroller.Spec.Template.Spec.Containers[0].Image {
-			occontroller.Spec.Template.Spec.Containers[0].Image = updatedDeployment.Spec.Template.Spec.Containers[0].Image
-			updated = true
-		}
-		// Update environment variables if they changed
-		if !envVarsEqual(updatedDeployment.Spec.Template.Spec.Containers[0].Env, occontroller.Spec.Template.Spec.Containers[0].Env) {
-			occontroller.Spec.Template.Spec.Containers[0].Env = updatedDeployment.Spec.Template.Spec.Containers[0].Env
-			updated = true
-		}
-		// Update template annotations if they changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Annotations, occontroller.Spec.Template.Annotations) {
-			occontroller.Spec.Template.Annotations = updatedDeployment.Spec.Template.Annotations
-			updated = true
-		}
-		// Update template labels if they changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Labels, occontroller.Spec.Template.Labels) {
-			occontroller.Spec.Template.Labels = updatedDeployment.Spec.Template.Labels
-			updated = true
-		}
-		// Update nodeSelector if it changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Spec.NodeSelector, occontroller.Spec.Template.Spec.NodeSelector) {
-			occontroller.Spec.Template.Spec.NodeSelector = updatedDeployment.Spec.Template.Spec.NodeSelector
-			updated = true
-		}
-		// Update tolerations if they changed
-		if !utils.TolerationsEqual(ctx, updatedDeployment.Spec.Template.Spec.Tolerations, occontroller.Spec.Template.Spec.Tolerations) {
-			occontroller.Spec.Template.Spec.Tolerations = updatedDeployment.Spec.Template.Spec.Tolerations
-			updated = true
-		}
-		// Only set controller reference if we actually updated something
-		if updated {
-			return ctrl.SetControllerReference(overcommit, occontroller, r.Scheme)
-		}
-
-		return nil
-	})
-	if err != nil {
+	occontroller := resources.GenerateOvercommitClassControllerDeployment(r.Config, *overcommit)
+	if err = utils.Apply(ctx, r.Client, overcommit, occontroller); err != nil {
+		logger.Error(err, "Failed to reconcile OvercommitClass Controller")
 		return ctrl.Result{}, err
 	}
 
@@ -389,11 +232,13 @@ func (r *OvercommitReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	logger.Info("Reconciliation completed successfully", "time", time.Now().Format("15:04:05"))
-	return ctrl.Result{}, nil
+	// Come back when the builtin certificates have to be rotated
+	return ctrl.Result{RequeueAfter: certs.RequeueAfter(renewals...)}, nil
 }
 
 // +kubebuilder:rbac:groups=apps, resources=deployments;replicasets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="", resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="", resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io, resources=leases,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io, resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;create;update;patch;delete
 
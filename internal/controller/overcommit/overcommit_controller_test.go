@@ -7,7 +7,6 @@ package controller
 
 import (
 	"context"
-	"os"
 	"time"
 
 	overcommitv1 "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
@@ -53,7 +52,7 @@ var _ = Describe("Overcommit Controller", func() {
 			// Create the namespace
 			Expect(k8sClient.Create(ctx, &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: os.Getenv("POD_NAMESPACE"),
+					Name: testConfig.PodNamespace,
 				},
 			}))
 
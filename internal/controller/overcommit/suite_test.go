@@ -26,17 +26,25 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	// +kubebuilder:scaffold:imports
 )
 
 // These tests use Ginkgo (BDD-style Go testing framework). Refer to
 // http://onsi.github.io/ginkgo/ to learn more about Ginkgo.
 var (
-	cfg       *rest.Config
-	k8sClient client.Client
-	testEnv   *envtest.Environment
-	ctx       context.Context
-	cancel    context.CancelFunc
+	cfg        *rest.Config
+	k8sClient  client.Client
+	testEnv    *envtest.Environment
+	ctx        context.Context
+	cancel     context.CancelFunc
+	testConfig = config.Config{
+		PodNamespace:       "k8s-overcommit",
+		ImageRegistry:      "test-registry",
+		ImageRepository:    "test-repo",
+		AppVersion:         "v1.0.0",
+		ServiceAccountName: "test-service-account",
+	}
 )
 
 func TestControllers(t *testing.T) {
@@ -93,10 +101,10 @@ var _ = BeforeSuite(func() {
 	err = (&OvercommitReconciler{
 		Client: k8sManager.GetClient(),
 		Scheme: k8sManager.GetScheme(),
+		Config: testConfig,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
-	os.Setenv("POD_NAMESPACE", "k8s-overcommit")
 	os.Setenv("LABEL_OVERCOMMIT_CLASS", "test")
 
 	go func() {
@@ -110,7 +118,6 @@ var _ = AfterSuite(func() {
 	By("tearing down the test environment")
 	cancel()
 	err := testEnv.Stop()
-	os.Unsetenv("POD_NAMESPACE")
 	os.Unsetenv("LABEL_OVERCOMMIT_CLASS")
 	Expect(err).NotTo(HaveOccurred())
 })
@@ -11,11 +11,14 @@ import (
 	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certs"
 	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,13 +47,33 @@ func (r *OvercommitReconciler) updateOvercommitStatus(ctx context.Context, overc
 	}
 
 	// Check Issuer status
-	issuer := resources.GenerateIssuer()
-	checkResourceStatus(issuer.Name, "issuer", func() error {
-		return r.Get(ctx, client.ObjectKey{Name: issuer.Name, Namespace: issuer.Namespace}, issuer)
-	})
+	issuer := resources.GenerateIssuer(r.Config)
+	builtinCerts := overcommitObject.UsesBuiltinCertificates()
+	if builtinCerts {
+		checkResourceStatus(certs.CASecretName, "issuer", func() error {
+			return r.Get(ctx, client.ObjectKey{Name: certs.CASecretName, Namespace: issuer.Namespace}, &corev1.Secret{})
+		})
+	} else {
+		checkResourceStatus(issuer.Name, "issuer", func() error {
+			return r.Get(ctx, client.ObjectKey{Name: issuer.Name, Namespace: issuer.Namespace}, issuer)
+		})
+	}
+
+	// With builtin certificates the certificates are plain Secrets
+	checkCertificateStatus := func(certificate *certmanagerv1.Certificate, resourceType string) {
+		if builtinCerts {
+			checkResourceStatus(certificate.Spec.SecretName, resourceType, func() error {
+				return r.Get(ctx, client.ObjectKey{Name: certificate.Spec.SecretName, Namespace: certificate.Namespace}, &corev1.Secret{})
+			})
+			return
+		}
+		checkResourceStatus(certificate.Name, resourceType, func() error {
+			return r.Get(ctx, client.ObjectKey{Name: certificate.Name, Namespace: certificate.Namespace}, certificate)
+		})
+	}
 
 	// Check OvercommitClass Validator components
-	overcommitClassDeployment := resources.GenerateOvercommitClassValidatingDeployment(*overcommitObject)
+	overcommitClassDeployment := resources.GenerateOvercommitClassValidatingDeployment(r.Config, *overcommitObject)
 	checkResourceStatus(overcommitClassDeployment.Name, "overcommitclass-deployment", func() error {
 		return r.Get(ctx, client.ObjectKey{Name: overcommitClassDeployment.Name, Namespace: overcommitClassDeployment.Namespace}, overcommitClassDeployment)
 	})
@@ -61,17 +84,20 @@ func (r *OvercommitReconciler) updateOvercommitStatus(ctx context.Context, overc
 	})
 
 	overcommitClassCertificate := resources.GenerateCertificateValidatingOvercommitClass(*issuer, *overcommitClassService)
-	checkResourceStatus(overcommitClassCertificate.Name, "overcommitclass-certificate", func() error {
-		return r.Get(ctx, client.ObjectKey{Name: overcommitClassCertificate.Name, Namespace: overcommitClassCertificate.Namespace}, overcommitClassCertificate)
-	})
+	checkCertificateStatus(overcommitClassCertificate, "overcommitclass-certificate")
 
-	overcommitClassWebhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate)
+	overcommitClassWebhook := resources.GenerateOvercommitClassValidatingWebhookConfiguration(*overcommitClassDeployment, *overcommitClassService, *overcommitClassCertificate, overcommitObject.Spec.Webhook)
+	overcommitObject.Status.ClassWebhook = nil
 	checkResourceStatus(overcommitClassWebhook.Name, "overcommitclass-webhook", func() error {
-		return r.Get(ctx, client.ObjectKey{Name: overcommitClassWebhook.Name}, overcommitClassWebhook)
+		if err := r.Get(ctx, client.ObjectKey{Name: overcommitClassWebhook.Name}, overcommitClassWebhook); err != nil {
+			return err
+		}
+		overcommitObject.Status.ClassWebhook = utils.ValidatingWebhookSettings(overcommitClassWebhook)
+		return nil
 	})
 
 	// Check Pod Validator components
-	podDeployment := resources.GeneratePodValidatingDeployment(*overcommitObject)
+	podDeployment := resources.GeneratePodValidatingDeployment(r.Config, *overcommitObject)
 	checkResourceStatus(podDeployment.Name, "pod-deployment", func() error {
 		return r.Get(ctx, client.ObjectKey{Name: podDeployment.Name, Namespace: podDeployment.Namespace}, podDeployment)
 	})
@@ -82,24 +108,21 @@ func (r *OvercommitReconciler) updateOvercommitStatus(ctx context.Context, overc
 	})
 
 	podCertificate := resources.GenerateCertificateValidatingPods(*issuer, *podService)
-	checkResourceStatus(podCertificate.Name, "pod-certificate", func() error {
-		return r.Get(ctx, client.ObjectKey{Name: podCertificate.Name, Namespace: podCertificate.Namespace}, podCertificate)
-	})
-
-	// Check Pod Webhook (handle label errors gracefully)
-	label, err := utils.GetOvercommitLabel(ctx, r.Client)
-	if err != nil {
-		logger.Info("Failed to get Overcommit label, using default", "error", err)
-		label = "overcommit.inditex.dev/class"
-	}
+	checkCertificateStatus(podCertificate, "pod-certificate")
 
-	podWebhook := resources.GeneratePodValidatingWebhookConfiguration(*podDeployment, *podService, *podCertificate, label)
+	// Check Pod Webhook
+	podWebhook := resources.GeneratePodValidatingWebhookConfiguration(*podDeployment, *podService, *podCertificate, overcommitObject.Spec.Webhook)
+	overcommitObject.Status.Webhook = nil
 	checkResourceStatus(podWebhook.Name, "pod-webhook", func() error {
-		return r.Get(ctx, client.ObjectKey{Name: podWebhook.Name}, podWebhook)
+		if err := r.Get(ctx, client.ObjectKey{Name: podWebhook.Name}, podWebhook); err != nil {
+			return err
+		}
+		overcommitObject.Status.Webhook = utils.ValidatingWebhookSettings(podWebhook)
+		return nil
 	})
 
 	// Check OvercommitClass Controller
-	ocController := resources.GenerateOvercommitClassControllerDeployment(*overcommitObject)
+	ocController := resources.GenerateOvercommitClassControllerDeployment(r.Config, *overcommitObject)
 	checkResourceStatus(ocController.Name, "overcommitclass-controller", func() error {
 		return r.Get(ctx, client.ObjectKey{Name: ocController.Name, Namespace: ocController.Namespace}, ocController)
 	})
@@ -147,6 +170,19 @@ func (r *OvercommitReconciler) updateOvercommitStatus(ctx context.Context, overc
 
 	setCondition(&overcommitObject.Status, condition)
 
+	pausedCondition := metav1.Condition{
+		Type:    "Paused",
+		Status:  metav1.ConditionFalse,
+		Reason:  "Active",
+		Message: "Pods are being mutated",
+	}
+	if overcommitObject.Spec.Paused {
+		pausedCondition.Status = metav1.ConditionTrue
+		pausedCondition.Reason = "Paused"
+		pausedCondition.Message = "Overcommit is paused, the mutating webhooks are removed and pods are not mutated"
+	}
+	setCondition(&overcommitObject.Status, pausedCondition)
+
 	// Update the status in the API
 	if err := r.Status().Update(ctx, overcommitObject); err != nil {
 		logger.Error(err, "Failed to update Overcommit status")
@@ -245,54 +281,20 @@ func setCondition(status *overcommit.OvercommitStatus, newCondition metav1.Condi
 	status.Conditions = append(status.Conditions, newCondition)
 }
 
-// envVarsEqual compares two slices of environment variables to see if they're equal
-// rsEqual compares two slices of environment variables to see if they're equal
-func envVarsEqual(a, b []corev1.EnvVar) bool {
-	if len(a) != len(b) {
-		return false
+// deleteCertManagerResources removes the cert-manager objects left behind when switching to builtin
+// certificates, so cert-manager does not keep overwriting the Secrets managed by the operator.
+func (r *OvercommitReconciler) deleteCertManagerResources(ctx context.Context, issuer *certmanagerv1.Issuer) error {
+	objects := []client.Object{
+		resources.GenerateCertificateValidatingOvercommitClass(*issuer, corev1.Service{}),
+		resources.GenerateCertificateValidatingPods(*issuer, corev1.Service{}),
+		issuer,
 	}
-
-	// Create maps for easier comparison
-	mapA := make(map[string]string)
-	mapB := make(map[string]string)
-
-	for _, env := range a {
-		mapA[env.Name] = env.Value
-	}
-
-	for _, env := range b {
-		mapB[env.Name] = env.Value
-	}
-
-	// Compare maps
-	for key, valueA := range mapA {
-		if valueB, exists := mapB[key]; !exists || valueA != valueB {
-			return false
-		}
-	}
-
-	return true
-}
-
-// annotationsEqual compares two annotation maps to see if they're equal
-func mapsEqual(a, b map[string]string) bool {
-	// Handle nil cases
-	if a == nil && b == nil {
-		return true
-	}
-	if a == nil || b == nil {
-		return false
-	}
-
-	if len(a) != len(b) {
-		return false
-	}
-
-	for key, valueA := range a {
-		if valueB, exists := b[key]; !exists || valueA != valueB {
-			return false
+	for _, obj := range objects {
+		err := r.Delete(ctx, obj)
+		// cert-manager may not even be installed in the cluster
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
 		}
 	}
-
-	return true
+	return nil
 }
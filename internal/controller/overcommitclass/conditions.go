@@ -30,3 +30,49 @@ func setCondition(status *overcommit.OvercommitClassStatus, newCondition metav1.
 	newCondition.LastTransitionTime = metav1.Now()
 	status.Conditions = append(status.Conditions, newCondition)
 }
+
+const (
+	// reasonOvercommitPaused is set on the Suspended condition while the Overcommit is paused.
+	reasonOvercommitPaused = "OvercommitPaused"
+	// reasonClassSuspended is set on the Suspended condition while the class is suspended.
+	reasonClassSuspended = "ClassSuspended"
+)
+
+// suspensionReason returns why the mutation of the class is disabled, or an empty string when it is active.
+func suspensionReason(overcommitResource *overcommit.Overcommit, overcommitClass *overcommit.OvercommitClass) string {
+	switch {
+	case overcommitResource.Spec.Paused:
+		return reasonOvercommitPaused
+	case overcommitClass.Spec.Suspended:
+		return reasonClassSuspended
+	default:
+		return ""
+	}
+}
+
+// suspendedCondition reports whether the pods of the class are being mutated.
+func suspendedCondition(reason string) metav1.Condition {
+	switch reason {
+	case reasonOvercommitPaused:
+		return metav1.Condition{
+			Type:    "Suspended",
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: "Overcommit is paused, pods are not mutated",
+		}
+	case reasonClassSuspended:
+		return metav1.Condition{
+			Type:    "Suspended",
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: "The class is suspended, pods are not mutated",
+		}
+	default:
+		return metav1.Condition{
+			Type:    "Suspended",
+			Status:  metav1.ConditionFalse,
+			Reason:  "Active",
+			Message: "Pods of the class are being mutated",
+		}
+	}
+}
@@ -11,27 +11,37 @@ import (
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 
+	"github.com/InditexTech/k8s-overcommit-operator/internal/certs"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	resources "github.com/InditexTech/k8s-overcommit-operator/internal/resources"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // OvercommitClassReconciler reconciles a OvercommitClass object
 type OvercommitClassReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	Config config.Config
 }
 
 // +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses/finalizers,verbs=update
-// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;delete
-// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -47,6 +57,9 @@ type OvercommitClassReconciler struct {
 func (r *OvercommitClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&overcommit.OvercommitClass{}).
+		// Pausing the Overcommit affects every class
+		Watches(&overcommit.Overcommit{}, handler.EnqueueRequestsFromMapFunc(r.requestsForOvercommit),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Named("OvercommitClass").
 		Complete(r)
 }
@@ -96,6 +109,12 @@ func (r *OvercommitClassReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
+	// The Overcommit controller is tearing down every generated object, do not recreate them
+	if !overcommitResource.DeletionTimestamp.IsZero() {
+		logger.Info("Overcommit is being deleted, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
 	needsOwnerUpdate := false
 	if len(overcommitClass.OwnerReferences) == 0 {
 		needsOwnerUpdate = true
@@ -134,248 +153,68 @@ func (r *OvercommitClassReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	logger.Info("Reconciling resources for the class", "name", overcommitClass.Name)
 
 	// Create resource definitions
-	deployment := resources.CreateDeployment(*overcommitClass)
-	service := resources.CreateService(overcommitClass.Name)
+	deployment := resources.CreateDeployment(r.Config, *overcommitClass)
+	service := resources.CreateService(r.Config, overcommitClass.Name)
 	certificate := resources.CreateCertificate(overcommitClass.Name, *service)
 	webhookConfig := resources.CreateMutatingWebhookConfiguration(*overcommitClass, *service, *certificate, label)
 
-	// Reconcile Deployment
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
-		// Regenerate the desired deployment spec
-		updatedDeployment := resources.CreateDeployment(*overcommitClass)
-
-		// Only update if there are actual differences
-		if deployment.CreationTimestamp.IsZero() {
-			// New deployment, set everything
-			deployment.Spec = updatedDeployment.Spec
-			deployment.Labels = updatedDeployment.Labels
-			deployment.Annotations = updatedDeployment.Annotations
-			return controllerutil.SetControllerReference(overcommitClass, deployment, r.Scheme)
-		}
-		// Existing deployment, only update specific fields if needed
-		updated := false
-
-		// Check if image changed
-		if len(updatedDeployment.Spec.Template.Spec.Containers) > 0 && len(deployment.Spec.Template.Spec.Containers) > 0 {
-			if updatedDeployment.Spec.Template.Spec.Containers[0].Image != deployment.Spec.Template.Spec.Containers[0].Image {
-				deployment.Spec.Template.Spec.Containers[0].Image = updatedDeployment.Spec.Template.Spec.Containers[0].Image
-				updated = true
-			}
-		}
-
-		// Update environment variables if they changed
-		if len(updatedDeployment.Spec.Template.Spec.Containers) > 0 && len(deployment.Spec.Template.Spec.Containers) > 0 {
-			if !envVarsEqual(updatedDeployment.Spec.Template.Spec.Containers[0].Env, deployment.Spec.Template.Spec.Containers[0].Env) {
-				deployment.Spec.Template.Spec.Containers[0].Env = updatedDeployment.Spec.Template.Spec.Containers[0].Env
-				updated = true
-			}
-		}
-
-		// Update template annotations if they changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Annotations, deployment.Spec.Template.Annotations) {
-			deployment.Spec.Template.Annotations = updatedDeployment.Spec.Template.Annotations
-			updated = true
-		}
-
-		// Update template labels if they changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Labels, deployment.Spec.Template.Labels) {
-			deployment.Spec.Template.Labels = updatedDeployment.Spec.Template.Labels
-			updated = true
-		}
-
-		// Update nodeSelector if it changed
-		if !mapsEqual(updatedDeployment.Spec.Template.Spec.NodeSelector, deployment.Spec.Template.Spec.NodeSelector) {
-			deployment.Spec.Template.Spec.NodeSelector = updatedDeployment.Spec.Template.Spec.NodeSelector
-			updated = true
-		}
-
-		// Update tolerations if they changed
-		if !utils.TolerationsEqual(ctx, updatedDeployment.Spec.Template.Spec.Tolerations, deployment.Spec.Template.Spec.Tolerations) {
-			deployment.Spec.Template.Spec.Tolerations = updatedDeployment.Spec.Template.Spec.Tolerations
-			updated = true
+	// With builtin certificates the serving certificate is issued by the operator CA
+	var ca *certs.CA
+	if overcommitResource.UsesBuiltinCertificates() {
+		ca, err = certs.EnsureCA(ctx, r.Client, r.Scheme, r.Config.PodNamespace, nil)
+		if err != nil {
+			logger.Error(err, "Failed to get the webhook CA")
+			return ctrl.Result{}, err
 		}
+	}
 
-		// Only set controller reference if we actually updated something
-		if updated {
-			return controllerutil.SetControllerReference(overcommitClass, deployment, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to create or update Deployment")
+	// Reconcile Deployment
+	if err = utils.Apply(ctx, r.Client, overcommitClass, deployment); err != nil {
+		logger.Error(err, "Failed to reconcile Deployment")
 		return ctrl.Result{}, err
 	}
 
 	// Reconcile Service
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
-		// Regenerate the desired service spec
-		updatedService := resources.CreateService(overcommitClass.Name)
-
-		// Only update if there are actual differences
-		if service.CreationTimestamp.IsZero() {
-			// New service, set everything
-			service.Spec = updatedService.Spec
-			service.Labels = updatedService.Labels
-			service.Annotations = updatedService.Annotations
-			return controllerutil.SetControllerReference(overcommitClass, service, r.Scheme)
-		}
-		// Existing service, only update specific fields if needed
-		updated := false
-
-		// Check if selector changed
-		if !mapsEqual(updatedService.Spec.Selector, service.Spec.Selector) {
-			service.Spec.Selector = updatedService.Spec.Selector
-			updated = true
-		}
-
-		// Check if ports changed
-		if !portsEqual(updatedService.Spec.Ports, service.Spec.Ports) {
-			service.Spec.Ports = updatedService.Spec.Ports
-			updated = true
-		}
-
-		// Check if service type changed
-		if updatedService.Spec.Type != service.Spec.Type {
-			service.Spec.Type = updatedService.Spec.Type
-			updated = true
-		}
-
-		// Update annotations if they changed
-		if !mapsEqual(updatedService.Annotations, service.Annotations) {
-			service.Annotations = updatedService.Annotations
-			updated = true
-		}
-
-		// Update labels if they changed
-		if !mapsEqual(updatedService.Labels, service.Labels) {
-			service.Labels = updatedService.Labels
-			updated = true
-		}
-
-		// Only set controller reference if we actually updated something
-		if updated {
-			return controllerutil.SetControllerReference(overcommitClass, service, r.Scheme)
-		}
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to create or update Service")
+	if err = utils.Apply(ctx, r.Client, overcommitClass, service); err != nil {
+		logger.Error(err, "Failed to reconcile Service")
 		return ctrl.Result{}, err
 	}
 
 	// Reconcile Certificate
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, certificate, func() error {
-		// Regenerate the desired certificate spec
-		updatedCertificate := resources.CreateCertificate(overcommitClass.Name, *service)
-
-		// Only update if there are actual differences
-		if certificate.CreationTimestamp.IsZero() {
-			// New certificate, set everything
-			certificate.Spec = updatedCertificate.Spec
-			certificate.Labels = updatedCertificate.Labels
-			certificate.Annotations = updatedCertificate.Annotations
-			return controllerutil.SetControllerReference(overcommitClass, certificate, r.Scheme)
-		}
-		// Existing certificate, only update specific fields if needed
-		updated := false
-
-		// Check if DNS names changed
-		if !slicesEqual(updatedCertificate.Spec.DNSNames, certificate.Spec.DNSNames) {
-			certificate.Spec.DNSNames = updatedCertificate.Spec.DNSNames
-			updated = true
-		}
-
-		// Check if issuer ref changed
-		if updatedCertificate.Spec.IssuerRef.Name != certificate.Spec.IssuerRef.Name ||
-			updatedCertificate.Spec.IssuerRef.Kind != certificate.Spec.IssuerRef.Kind {
-			certificate.Spec.IssuerRef = updatedCertificate.Spec.IssuerRef
-			updated = true
-		}
-
-		// Check if secret name changed
-		if updatedCertificate.Spec.SecretName != certificate.Spec.SecretName {
-			certificate.Spec.SecretName = updatedCertificate.Spec.SecretName
-			updated = true
-		}
-
-		// Update annotations if they changed
-		if !mapsEqual(updatedCertificate.Annotations, certificate.Annotations) {
-			certificate.Annotations = updatedCertificate.Annotations
-			updated = true
-		}
-
-		// Update labels if they changed
-		if !mapsEqual(updatedCertificate.Labels, certificate.Labels) {
-			certificate.Labels = updatedCertificate.Labels
-			updated = true
+	var renewal time.Time
+	if ca != nil {
+		renewal, err = certs.EnsureServingCertificate(ctx, r.Client, r.Scheme, ca, certificate.Namespace,
+			certificate.Spec.SecretName, certificate.Spec.DNSNames, overcommitClass)
+		if err != nil {
+			logger.Error(err, "Failed to reconcile certificate secret")
+			return ctrl.Result{}, err
 		}
-
-		// Only set controller reference if we actually updated something
-		if updated {
-			return controllerutil.SetControllerReference(overcommitClass, certificate, r.Scheme)
+		// Make sure cert-manager does not keep overwriting the Secret
+		if deleteErr := r.Delete(ctx, certificate); deleteErr != nil && !apierrors.IsNotFound(deleteErr) && !meta.IsNoMatchError(deleteErr) {
+			logger.Error(deleteErr, "Failed to delete cert-manager Certificate")
+			return ctrl.Result{}, deleteErr
 		}
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to create or update Certificate")
+	} else if err = utils.Apply(ctx, r.Client, overcommitClass, certificate); err != nil {
+		logger.Error(err, "Failed to reconcile Certificate")
 		return ctrl.Result{}, err
 	}
 
-	// Reconcile MutatingWebhookConfiguration
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, webhookConfig, func() error {
-		// Regenerate the desired webhook configuration
-		updatedWebhookConfig := resources.CreateMutatingWebhookConfiguration(*overcommitClass, *service, *certificate, label)
-
-		// Only update if there are actual differences
-		if webhookConfig.CreationTimestamp.IsZero() {
-			// New webhook config, set everything
-			webhookConfig.Annotations = updatedWebhookConfig.Annotations
-			webhookConfig.Labels = updatedWebhookConfig.Labels
-			webhookConfig.Webhooks = updatedWebhookConfig.Webhooks
-			return controllerutil.SetControllerReference(overcommitClass, webhookConfig, r.Scheme)
-		}
-		// Existing webhook config, only update specific fields if needed
-		updated := false
-
-		// Update annotations if they changed
-		if !mapsEqual(updatedWebhookConfig.Annotations, webhookConfig.Annotations) {
-			webhookConfig.Annotations = updatedWebhookConfig.Annotations
-			updated = true
-		}
-
-		// Update labels if they changed
-		if !mapsEqual(updatedWebhookConfig.Labels, webhookConfig.Labels) {
-			webhookConfig.Labels = updatedWebhookConfig.Labels
-			updated = true
+	// Reconcile MutatingWebhookConfiguration, removed while the mutation is paused or suspended
+	suspendedReason := suspensionReason(&overcommitResource, overcommitClass)
+	if suspendedReason != "" {
+		logger.Info("Overcommit mutation is disabled for the class, removing its webhook", "name", overcommitClass.Name, "reason", suspendedReason)
+		if err = r.Delete(ctx, webhookConfig); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "Failed to delete MutatingWebhookConfiguration")
+			return ctrl.Result{}, err
 		}
-
-		// Check if webhooks changed (simplified comparison)
-		if len(updatedWebhookConfig.Webhooks) != len(webhookConfig.Webhooks) {
-			webhookConfig.Webhooks = updatedWebhookConfig.Webhooks
-			updated = true
-		} else {
-			// Compare each webhook
-			for i, updatedWebhook := range updatedWebhookConfig.Webhooks {
-				if i < len(webhookConfig.Webhooks) {
-					currentWebhook := webhookConfig.Webhooks[i]
-					if webhookChanged(updatedWebhook, currentWebhook) {
-						webhookConfig.Webhooks = updatedWebhookConfig.Webhooks
-						updated = true
-						break
-					}
-				}
-			}
+	} else {
+		if ca != nil {
+			resources.SetMutatingWebhookCABundle(webhookConfig, ca.CertPEM)
 		}
-
-		// Only set controller reference if we actually updated something
-		if updated {
-			return controllerutil.SetControllerReference(overcommitClass, webhookConfig, r.Scheme)
+		if err = utils.Apply(ctx, r.Client, overcommitClass, webhookConfig); err != nil {
+			logger.Error(err, "Failed to reconcile MutatingWebhookConfiguration")
+			return ctrl.Result{}, err
 		}
-		return nil
-	})
-	if err != nil {
-		logger.Error(err, "Failed to create or update MutatingWebhookConfiguration")
-		return ctrl.Result{}, err
 	}
 
 	if getTotalClasses(ctx, r.Client) != nil {
@@ -384,11 +223,27 @@ func (r *OvercommitClassReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	// Update the status of the resources
-	if err := r.updateResourcesStatus(ctx, overcommitClass); err != nil {
+	if err := r.updateResourcesStatus(ctx, overcommitClass, suspendedReason); err != nil {
 		logger.Error(err, "Error updating resource status")
 		return ctrl.Result{}, err
 	}
 
 	logger.Info("Reconciliation completed successfully", "time", time.Now().Format("15:04:05"))
-	return ctrl.Result{}, nil
+	// Come back when the builtin serving certificate has to be rotated
+	return ctrl.Result{RequeueAfter: certs.RequeueAfter(renewal)}, nil
+}
+
+// requestsForOvercommit enqueues every OvercommitClass when the Overcommit changes.
+func (r *OvercommitClassReconciler) requestsForOvercommit(ctx context.Context, _ client.Object) []reconcile.Request {
+	overcommitClasses := &overcommit.OvercommitClassList{}
+	if err := r.List(ctx, overcommitClasses); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list OvercommitClasses")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(overcommitClasses.Items))
+	for _, overcommitClass := range overcommitClasses.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&overcommitClass)})
+	}
+	return requests
 }
@@ -6,7 +6,6 @@
 package controller
 
 import (
-	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -33,7 +32,7 @@ var _ = Describe("OvercommitClass Controller", func() {
 
 			Expect(k8sClient.Delete(ctx, &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: os.Getenv("POD_NAMESPACE"),
+					Name: testConfig.PodNamespace,
 				},
 			})).To(Succeed())
 		})
@@ -55,7 +54,7 @@ var _ = Describe("OvercommitClass Controller", func() {
 			// Create the namespace
 			Expect(k8sClient.Create(ctx, &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: os.Getenv("POD_NAMESPACE"),
+					Name: testConfig.PodNamespace,
 				},
 			}))
 
@@ -65,17 +64,17 @@ var _ = Describe("OvercommitClass Controller", func() {
 			// Verify that dependent resources are created
 			deployment := &appsv1.Deployment{}
 			Eventually(func() error {
-				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-overcommit-webhook", Namespace: os.Getenv("POD_NAMESPACE")}, deployment)
+				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-overcommit-webhook", Namespace: testConfig.PodNamespace}, deployment)
 			}, 10*time.Second, 250*time.Millisecond).Should(Succeed())
 
 			service := &corev1.Service{}
 			Eventually(func() error {
-				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-webhook-service", Namespace: os.Getenv("POD_NAMESPACE")}, service)
+				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-webhook-service", Namespace: testConfig.PodNamespace}, service)
 			}, 10*time.Second, 250*time.Millisecond).Should(Succeed())
 
 			certificate := &certmanager.Certificate{}
 			Eventually(func() error {
-				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-webhook-certificate", Namespace: os.Getenv("POD_NAMESPACE")}, certificate)
+				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-webhook-certificate", Namespace: testConfig.PodNamespace}, certificate)
 			}, 10*time.Second, 250*time.Millisecond).Should(Succeed())
 
 			webhookConfig := &admissionv1.MutatingWebhookConfiguration{}
@@ -111,17 +110,17 @@ var _ = Describe("OvercommitClass Controller", func() {
 			// Verify that dependent resources are deleted
 			deployment := &appsv1.Deployment{}
 			Eventually(func() error {
-				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-overcommitclass-2-webhook-deployment", Namespace: os.Getenv("POD_NAMESPACE")}, deployment)
+				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-overcommitclass-2-webhook-deployment", Namespace: testConfig.PodNamespace}, deployment)
 			}).ShouldNot(Succeed())
 
 			service := &corev1.Service{}
 			Eventually(func() error {
-				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-overcommitclass-2-webhook-service", Namespace: os.Getenv("POD_NAMESPACE")}, service)
+				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-overcommitclass-2-webhook-service", Namespace: testConfig.PodNamespace}, service)
 			}).ShouldNot(Succeed())
 
 			certificate := &certmanager.Certificate{}
 			Eventually(func() error {
-				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-overcommitclass-2-webhook-certificate", Namespace: os.Getenv("POD_NAMESPACE")}, certificate)
+				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-overcommitclass-2-webhook-certificate", Namespace: testConfig.PodNamespace}, certificate)
 			}).ShouldNot(Succeed())
 
 			webhookConfig := &admissionv1.MutatingWebhookConfiguration{}
@@ -131,3 +130,37 @@ var _ = Describe("OvercommitClass Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("OvercommitClass Controller", func() {
+	Context("When suspending an OvercommitClass resource", func() {
+		It("Should remove the mutating webhook configuration", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-suspended",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+			Expect(k8sClient.Create(ctx, overcommitClass)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, overcommitClass))).To(Succeed())
+			})
+
+			webhookConfig := &admissionv1.MutatingWebhookConfiguration{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-suspended-overcommit-webhook"}, webhookConfig)
+			}, 10*time.Second, 250*time.Millisecond).Should(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(overcommitClass), overcommitClass)).To(Succeed())
+			overcommitClass.Spec.Suspended = true
+			Expect(k8sClient.Update(ctx, overcommitClass)).To(Succeed())
+
+			Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKey{Name: "test-suspended-overcommit-webhook"}, webhookConfig)
+			}, 10*time.Second, 250*time.Millisecond).ShouldNot(Succeed())
+		})
+	})
+})
@@ -7,9 +7,9 @@ package controller
 
 import (
 	"context"
-	"os"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 	certmanager "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
@@ -22,7 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func (r *OvercommitClassReconciler) updateResourcesStatus(ctx context.Context, overcommitClass *overcommit.OvercommitClass) error {
+func (r *OvercommitClassReconciler) updateResourcesStatus(ctx context.Context, overcommitClass *overcommit.OvercommitClass, suspendedReason string) error {
 	logger := log.FromContext(ctx)
 
 	// Resources
@@ -31,7 +31,7 @@ func (r *OvercommitClassReconciler) updateResourcesStatus(ctx context.Context, o
 	// Deployment
 	deployName := overcommitClass.Name + "-overcommit-webhook"
 	deploy := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{Name: deployName, Namespace: os.Getenv("POD_NAMESPACE")}, deploy)
+	err := r.Get(ctx, types.NamespacedName{Name: deployName, Namespace: r.Config.PodNamespace}, deploy)
 	if err == nil {
 		readyStatus["deployment"] = overcommit.ResourceStatus{Name: overcommitClass.Name + "-webhook-deployment", Ready: true}
 	} else {
@@ -41,31 +41,39 @@ func (r *OvercommitClassReconciler) updateResourcesStatus(ctx context.Context, o
 	// Service
 	svcName := overcommitClass.Name + "-webhook-service"
 	svc := &corev1.Service{}
-	err = r.Get(ctx, types.NamespacedName{Name: svcName, Namespace: os.Getenv("POD_NAMESPACE")}, svc)
+	err = r.Get(ctx, types.NamespacedName{Name: svcName, Namespace: r.Config.PodNamespace}, svc)
 	if err == nil {
 		readyStatus["service"] = overcommit.ResourceStatus{Name: svcName, Ready: true}
 	} else {
 		readyStatus["service"] = overcommit.ResourceStatus{Name: svcName, Ready: false}
 	}
 
-	// Certificate
+	// Certificate, a plain Secret when the operator manages the certificates itself
 	certName := overcommitClass.Name + "-webhook-certificate"
-	cert := &certmanager.Certificate{}
-	err = r.Get(ctx, types.NamespacedName{Name: certName, Namespace: os.Getenv("POD_NAMESPACE")}, cert)
+	var cert client.Object = &certmanager.Certificate{}
+	if overcommitResource, getErr := utils.GetOvercommit(ctx, r.Client); getErr == nil && overcommitResource.UsesBuiltinCertificates() {
+		certName = overcommitClass.Name + "-webhook-secret"
+		cert = &corev1.Secret{}
+	}
+	err = r.Get(ctx, types.NamespacedName{Name: certName, Namespace: r.Config.PodNamespace}, cert)
 	if err == nil {
 		readyStatus["certificate"] = overcommit.ResourceStatus{Name: certName, Ready: true}
 	} else {
 		readyStatus["certificate"] = overcommit.ResourceStatus{Name: certName, Ready: false}
 	}
 
-	// Webhook Configuration
-	webhookName := overcommitClass.Name + "-overcommit-webhook"
-	webhook := &admissionv1.MutatingWebhookConfiguration{}
-	err = r.Get(ctx, client.ObjectKey{Name: webhookName}, webhook)
-	if err == nil {
-		readyStatus["webhook"] = overcommit.ResourceStatus{Name: webhookName, Ready: true}
-	} else {
-		readyStatus["webhook"] = overcommit.ResourceStatus{Name: webhookName, Ready: false}
+	// Webhook Configuration, intentionally missing while the class is suspended
+	overcommitClass.Status.Webhook = nil
+	if suspendedReason == "" {
+		webhookName := overcommitClass.Name + "-overcommit-webhook"
+		webhook := &admissionv1.MutatingWebhookConfiguration{}
+		err = r.Get(ctx, client.ObjectKey{Name: webhookName}, webhook)
+		if err == nil {
+			readyStatus["webhook"] = overcommit.ResourceStatus{Name: webhookName, Ready: true}
+			overcommitClass.Status.Webhook = utils.MutatingWebhookSettings(webhook)
+		} else {
+			readyStatus["webhook"] = overcommit.ResourceStatus{Name: webhookName, Ready: false}
+		}
 	}
 
 	// Convert map values to a slice
@@ -107,6 +115,7 @@ func (r *OvercommitClassReconciler) updateResourcesStatus(ctx context.Context, o
 
 	// Update or add the condition
 	setCondition(&overcommitClass.Status, condition)
+	setCondition(&overcommitClass.Status, suspendedCondition(suspendedReason))
 
 	// Update status in the API
 	if err := r.Status().Update(ctx, overcommitClass); err != nil {
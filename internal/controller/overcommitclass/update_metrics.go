@@ -25,6 +25,7 @@ func getTotalClasses(ctx context.Context, client client.Client) error {
 	totalClasses := len(overcommitClasses.Items)
 	metrics.K8sOvercommitOperatorTotalClasses.Set(float64(totalClasses))
 	metrics.K8sOvercommitOperatorClass.Reset()
+	metrics.K8sOvercommitOperatorClassSuspended.Reset()
 	for _, overcommitClass := range overcommitClasses.Items {
 		metrics.K8sOvercommitOperatorClass.WithLabelValues(
 			overcommitClass.GetName(),
@@ -32,6 +33,11 @@ func getTotalClasses(ctx context.Context, client client.Client) error {
 			fmt.Sprintf("%f", overcommitClass.Spec.MemoryOvercommit),
 			fmt.Sprintf("%t", overcommitClass.Spec.IsDefault),
 		).Set(1)
+		suspended := 0.0
+		if overcommitClass.Spec.Suspended {
+			suspended = 1
+		}
+		metrics.K8sOvercommitOperatorClassSuspended.WithLabelValues(overcommitClass.GetName()).Set(suspended)
 	}
 	return nil
 }
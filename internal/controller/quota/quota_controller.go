@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/quota"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// QuotaReconciler annotates the ResourceQuotas with the class overcommitting the pods of their namespace when
+// the resourceQuotas policy of the Overcommit is enabled. It warns through events when the ratios of the class
+// make the limits quotas unreachable, and with scaleRequests scales the requests quotas by the ratios.
+type QuotaReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;update
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *QuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("overcommit-quota")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		// The usage of the quotas changes their status only
+		For(&corev1.ResourceQuota{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		// The class label of a namespace selects the class of its quotas
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Watches(&overcommit.OvercommitClass{}, handler.EnqueueRequestsFromMapFunc(r.requestsForAll),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&overcommit.Overcommit{}, handler.EnqueueRequestsFromMapFunc(r.requestsForAll),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("OvercommitResourceQuota").
+		Complete(r)
+}
+
+func (r *QuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	resourceQuota := &corev1.ResourceQuota{}
+	if err := r.Get(ctx, req.NamespacedName, resourceQuota); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	overcommitResource := &overcommit.Overcommit{}
+	if err := r.Get(ctx, client.ObjectKey{Name: "cluster"}, overcommitResource); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		overcommitResource = nil
+	}
+
+	var overcommitClass *overcommit.OvercommitClass
+	if overcommitResource.ResourceQuotasEnabled() {
+		var err error
+		if overcommitClass, err = r.namespaceClass(ctx, overcommitResource, resourceQuota.Namespace); err != nil {
+			logger.Error(err, "Failed to resolve the class of the namespace", "namespace", resourceQuota.Namespace)
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := resourceQuota.DeepCopy()
+	unreachable := r.update(resourceQuota, overcommitClass, overcommitResource.ScalesRequestsQuotas())
+	if equality.Semantic.DeepEqual(original.Annotations, resourceQuota.Annotations) && equality.Semantic.DeepEqual(original.Spec, resourceQuota.Spec) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Patch(ctx, resourceQuota, client.MergeFrom(original)); err != nil {
+		logger.Error(err, "Failed to update the ResourceQuota", "namespace", resourceQuota.Namespace, "name", resourceQuota.Name)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The recorded unreachable resources keep the warning from being repeated on every update of the quota
+	if unreachable != "" && unreachable != original.Annotations[quota.UnreachableAnnotation] {
+		r.Recorder.Eventf(resourceQuota, corev1.EventTypeWarning, "OvercommitLimitsQuotaUnreachable",
+			"The %s limits of ResourceQuota %s cannot be reached: with the ratios of OvercommitClass %s the requests quota is exhausted first",
+			unreachable, resourceQuota.Name, overcommitClass.Name)
+	}
+	logger.Info("ResourceQuota updated", "namespace", resourceQuota.Namespace, "name", resourceQuota.Name, "unreachable", unreachable)
+	return ctrl.Result{}, nil
+}
+
+// update sets the annotations and the requests hard limits of the quota for the class, returning the resources
+// whose limits quota is unreachable. Without a class, the annotations are removed and the original requests
+// hard limits restored.
+func (r *QuotaReconciler) update(resourceQuota *corev1.ResourceQuota, overcommitClass *overcommit.OvercommitClass, scale bool) string {
+	originals := quota.OriginalRequests(resourceQuota)
+	if resourceQuota.Annotations == nil {
+		resourceQuota.Annotations = map[string]string{}
+	}
+	for name := range resourceQuota.Annotations {
+		if strings.HasPrefix(name, quota.OriginalAnnotationPrefix) {
+			delete(resourceQuota.Annotations, name)
+		}
+	}
+	for _, name := range []string{quota.ClassAnnotation, quota.CPUAnnotation, quota.MemoryAnnotation, quota.UnreachableAnnotation} {
+		delete(resourceQuota.Annotations, name)
+	}
+
+	if overcommitClass == nil {
+		for name, quantity := range originals {
+			resourceQuota.Spec.Hard[name] = quantity
+		}
+		return ""
+	}
+
+	ratios := quota.Ratios{CPU: overcommitClass.Spec.CpuOvercommit, Memory: overcommitClass.Spec.MemoryOvercommit}
+	hard := originals
+	if scale {
+		hard = quota.Scale(originals, ratios)
+		for name, quantity := range originals {
+			resourceQuota.Annotations[quota.OriginalAnnotationPrefix+string(name)] = quantity.String()
+		}
+	}
+	for name, quantity := range hard {
+		resourceQuota.Spec.Hard[name] = quantity
+	}
+	resourceQuota.Annotations[quota.ClassAnnotation] = overcommitClass.Name
+	resourceQuota.Annotations[quota.CPUAnnotation] = quota.Annotation(ratios.CPU)
+	resourceQuota.Annotations[quota.MemoryAnnotation] = quota.Annotation(ratios.Memory)
+
+	unreachable := quota.Unreachable(resourceQuota.Spec.Hard, ratios)
+	if len(unreachable) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(unreachable))
+	for _, name := range unreachable {
+		names = append(names, string(name))
+	}
+	resourceQuota.Annotations[quota.UnreachableAnnotation] = strings.Join(names, ",")
+	return resourceQuota.Annotations[quota.UnreachableAnnotation]
+}
+
+// namespaceClass returns the class the mutating webhooks apply to the pods of the namespace created without a
+// class label, nil when they are left untouched.
+func (r *QuotaReconciler) namespaceClass(ctx context.Context, overcommitResource *overcommit.Overcommit, name string) (*overcommit.OvercommitClass, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	overcommitClasses := &overcommit.OvercommitClassList{}
+	if err := r.List(ctx, overcommitClasses); err != nil {
+		return nil, err
+	}
+	route, err := overcommitpkg.RouteClass(overcommitResource, overcommitClasses.Items, namespace, nil)
+	return route.Class, err
+}
+
+// requestsForNamespace enqueues the quotas of a namespace when its labels change.
+func (r *QuotaReconciler) requestsForNamespace(ctx context.Context, namespace client.Object) []reconcile.Request {
+	return r.requestsForQuotas(ctx, client.InNamespace(namespace.GetName()))
+}
+
+// requestsForAll enqueues every quota when a class or the Overcommit changes.
+func (r *QuotaReconciler) requestsForAll(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.requestsForQuotas(ctx)
+}
+
+func (r *QuotaReconciler) requestsForQuotas(ctx context.Context, opts ...client.ListOption) []reconcile.Request {
+	resourceQuotas := &corev1.ResourceQuotaList{}
+	if err := r.List(ctx, resourceQuotas, opts...); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list ResourceQuotas")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(resourceQuotas.Items))
+	for _, resourceQuota := range resourceQuotas.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&resourceQuota)})
+	}
+	return requests
+}
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/quota"
+)
+
+var _ = Describe("Quota", func() {
+	var (
+		k8sClient client.Client
+		recorder  *record.FakeRecorder
+		r         *QuotaReconciler
+	)
+
+	cluster := func(policy *overcommit.ResourceQuotaPolicy) *overcommit.Overcommit {
+		cluster := controllertest.Cluster()
+		cluster.Spec.ResourceQuotas = policy
+		return cluster
+	}
+	class := func(name string, cpu float64, isDefault bool) *overcommit.OvercommitClass {
+		class := controllertest.Class(name, cpu, 0.8)
+		class.Spec.IsDefault, class.Spec.ExcludedNamespaces = isDefault, "^kube-.*"
+		return class
+	}
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	resourceQuota := func(namespace string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "compute"},
+			Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("10"),
+				corev1.ResourceLimitsCPU:      resource.MustParse("20"),
+				corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+			}},
+		}
+	}
+	setup := func(objects ...client.Object) {
+		k8sClient = controllertest.NewClientBuilder(objects...).Build()
+		recorder = record.NewFakeRecorder(10)
+		r = &QuotaReconciler{Client: k8sClient, Scheme: controllertest.Scheme(), Recorder: recorder}
+	}
+	reconcile := func(namespace string) *corev1.ResourceQuota {
+		key := client.ObjectKey{Namespace: namespace, Name: "compute"}
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		updated := &corev1.ResourceQuota{}
+		Expect(k8sClient.Get(context.Background(), key, updated)).To(Succeed())
+		return updated
+	}
+	hard := func(resourceQuota *corev1.ResourceQuota, name corev1.ResourceName) string {
+		quantity := resourceQuota.Spec.Hard[name]
+		return quantity.String()
+	}
+
+	It("annotates the quotas with the class of their namespace", func() {
+		setup(cluster(&overcommit.ResourceQuotaPolicy{Enabled: true}),
+			class("standard", 0.5, true), class("high", 0.25, false),
+			namespace("apps", nil), namespace("batch", map[string]string{controllertest.ClassLabel: "high"}),
+			resourceQuota("apps"), resourceQuota("batch"))
+
+		apps := reconcile("apps")
+		Expect(apps.Annotations).To(Equal(map[string]string{
+			quota.ClassAnnotation:  "standard",
+			quota.CPUAnnotation:    "0.5000",
+			quota.MemoryAnnotation: "0.8000",
+		}))
+		Expect(hard(apps, corev1.ResourceRequestsCPU)).To(Equal("10"))
+		Expect(reconcile("batch").Annotations).To(HaveKeyWithValue(quota.ClassAnnotation, "high"))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("warns when the ratios make the limits quota unreachable", func() {
+		setup(cluster(&overcommit.ResourceQuotaPolicy{Enabled: true}),
+			class("standard", 0.8, true), namespace("apps", nil), resourceQuota("apps"))
+
+		apps := reconcile("apps")
+		Expect(apps.Annotations).To(HaveKeyWithValue(quota.UnreachableAnnotation, "cpu"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("OvercommitLimitsQuotaUnreachable")))
+
+		// The warning is not repeated while the quota stays unreachable
+		reconcile("apps")
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("scales the requests quotas and restores them", func() {
+		setup(cluster(&overcommit.ResourceQuotaPolicy{Enabled: true, ScaleRequests: true}),
+			class("standard", 0.5, true), namespace("apps", nil), resourceQuota("apps"))
+
+		apps := reconcile("apps")
+		Expect(hard(apps, corev1.ResourceRequestsCPU)).To(Equal("5"))
+		Expect(hard(apps, corev1.ResourceRequestsMemory)).To(Equal("6871947673"))
+		Expect(hard(apps, corev1.ResourceLimitsCPU)).To(Equal("20"))
+		Expect(apps.Annotations).To(HaveKeyWithValue(quota.OriginalAnnotationPrefix+"requests.cpu", "10"))
+		Expect(apps.Annotations).To(HaveKeyWithValue(quota.OriginalAnnotationPrefix+"requests.memory", "8Gi"))
+
+		// A change of the class rescales the original quota
+		standard := &overcommit.OvercommitClass{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "standard"}, standard)).To(Succeed())
+		standard.Spec.CpuOvercommit = 0.25
+		Expect(k8sClient.Update(context.Background(), standard)).To(Succeed())
+		Expect(hard(reconcile("apps"), corev1.ResourceRequestsCPU)).To(Equal("2500m"))
+
+		// Disabling the policy restores the original quota
+		overcommitResource := &overcommit.Overcommit{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "cluster"}, overcommitResource)).To(Succeed())
+		overcommitResource.Spec.ResourceQuotas = nil
+		Expect(k8sClient.Update(context.Background(), overcommitResource)).To(Succeed())
+		apps = reconcile("apps")
+		Expect(hard(apps, corev1.ResourceRequestsCPU)).To(Equal("10"))
+		Expect(hard(apps, corev1.ResourceRequestsMemory)).To(Equal("8Gi"))
+		Expect(apps.Annotations).To(BeEmpty())
+	})
+
+	It("keeps the requests quota changed by its owner", func() {
+		setup(cluster(&overcommit.ResourceQuotaPolicy{Enabled: true, ScaleRequests: true}),
+			class("standard", 0.5, true), namespace("apps", nil), resourceQuota("apps"))
+		apps := reconcile("apps")
+
+		apps.Spec.Hard[corev1.ResourceRequestsCPU] = resource.MustParse("12")
+		Expect(k8sClient.Update(context.Background(), apps)).To(Succeed())
+
+		apps = reconcile("apps")
+		Expect(hard(apps, corev1.ResourceRequestsCPU)).To(Equal("6"))
+		Expect(apps.Annotations).To(HaveKeyWithValue(quota.OriginalAnnotationPrefix+"requests.cpu", "12"))
+	})
+
+	It("leaves the quotas of the namespaces without class untouched", func() {
+		setup(cluster(&overcommit.ResourceQuotaPolicy{Enabled: true}),
+			class("standard", 0.5, true), class("suspended", 0.5, false),
+			namespace("kube-system", nil), namespace("paused", map[string]string{controllertest.ClassLabel: "suspended"}),
+			resourceQuota("kube-system"), resourceQuota("paused"))
+		suspended := &overcommit.OvercommitClass{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "suspended"}, suspended)).To(Succeed())
+		suspended.Spec.Suspended = true
+		Expect(k8sClient.Update(context.Background(), suspended)).To(Succeed())
+
+		Expect(reconcile("kube-system").Annotations).To(BeEmpty())
+		Expect(reconcile("paused").Annotations).To(BeEmpty())
+	})
+
+	It("does nothing while the policy is disabled", func() {
+		setup(cluster(nil), class("standard", 0.5, true), namespace("apps", nil), resourceQuota("apps"))
+
+		Expect(reconcile("apps").Annotations).To(BeEmpty())
+	})
+})
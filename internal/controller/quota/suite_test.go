@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The quota annotations are tested against a fake client, it does not need a test environment.
+func TestQuota(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Quota Controller Suite")
+}
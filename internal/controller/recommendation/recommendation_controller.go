@@ -0,0 +1,283 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/recommender"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// recommendationResyncPeriod is how often the usage is sampled and the ratios recommended.
+	recommendationResyncPeriod = 5 * time.Minute
+	// applyThreshold is the smallest change of a ratio applied to the class, smaller ones would restart or
+	// resize its pods for nothing.
+	applyThreshold = 0.05
+	// podPageSize is the number of pods read from the API server at once.
+	podPageSize = 500
+)
+
+// Reasons of the conditions of an OvercommitRecommendation.
+const (
+	reasonRecommended         = "Recommended"
+	reasonClassNotFound       = "ClassNotFound"
+	reasonUsageUnavailable    = "UsageUnavailable"
+	reasonNoSamples           = "NoSamples"
+	reasonApplied             = "Applied"
+	reasonUpToDate            = "UpToDate"
+	reasonObservationTooShort = "ObservationTooShort"
+)
+
+// RecommendationReconciler recommends the ratios of the classes from the observed usage of their pods, and
+// applies them to the classes whose recommendation enables autoApply.
+type RecommendationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// APIReader reads the pods of the classes and their metrics, which are not cached.
+	APIReader client.Reader
+	Recorder  record.EventRecorder
+	// NewSource returns the source of the usage of a recommendation, a MetricsAPISource or a PrometheusSource
+	// when unset.
+	NewSource func(recommendation *overcommit.OvercommitRecommendation) recommender.Source
+
+	mu sync.Mutex
+	// sources are kept between reconciles, a MetricsAPISource keeping the samples taken in memory
+	sources map[string]cachedSource
+}
+
+// cachedSource is the source of a recommendation, along the spec it was created for.
+type cachedSource struct {
+	kind   overcommit.UsageSource
+	url    string
+	source recommender.Source
+}
+
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitrecommendations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitrecommendations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;update
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RecommendationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("overcommit-recommendation")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&overcommit.OvercommitRecommendation{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("OvercommitRecommendation").
+		Complete(r)
+}
+
+func (r *RecommendationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	recommendation := &overcommit.OvercommitRecommendation{}
+	if err := r.Get(ctx, req.NamespacedName, recommendation); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.forgetSource(req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !recommendation.DeletionTimestamp.IsZero() {
+		r.forgetSource(recommendation.Name)
+		return ctrl.Result{}, nil
+	}
+	original := recommendation.DeepCopy()
+
+	overcommitClass := &overcommit.OvercommitClass{}
+	if err := r.Get(ctx, client.ObjectKey{Name: recommendation.Spec.ClassName}, overcommitClass); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.setCondition(recommendation, overcommit.RecommendationConditionReady, metav1.ConditionFalse, reasonClassNotFound,
+			fmt.Sprintf("OvercommitClass %s does not exist", recommendation.Spec.ClassName))
+		return r.updateStatus(ctx, original, recommendation)
+	}
+
+	pods, err := r.classPods(ctx, overcommitClass.Name)
+	if err != nil {
+		logger.Error(err, "Failed to list the pods of the class", "class", overcommitClass.Name)
+		return ctrl.Result{}, err
+	}
+	samples, err := r.source(recommendation).Samples(ctx, pods, recommendation.Spec.Window.Duration)
+	if err != nil {
+		logger.Error(err, "Failed to read the usage of the pods", "class", overcommitClass.Name)
+		r.setCondition(recommendation, overcommit.RecommendationConditionReady, metav1.ConditionFalse, reasonUsageUnavailable, err.Error())
+		return r.updateStatus(ctx, original, recommendation)
+	}
+
+	byOwner := recommendation.Spec.Scope == overcommit.RecommendationScopeOwner
+	recommended, owners := recommender.Recommend(pods, samples, recommendation.Spec.Percentile, byOwner)
+	now := metav1.Now()
+	recommendation.Status.CpuOvercommit = recommended.CpuOvercommit
+	recommendation.Status.MemoryOvercommit = recommended.MemoryOvercommit
+	recommendation.Status.Samples = recommended.Samples
+	recommendation.Status.Owners = owners
+	recommendation.Status.LastUpdateTime = &now
+	if recommended.Samples == 0 {
+		r.setCondition(recommendation, overcommit.RecommendationConditionReady, metav1.ConditionFalse, reasonNoSamples,
+			fmt.Sprintf("No usage of the containers with limits of the pods of OvercommitClass %s was observed", overcommitClass.Name))
+		return r.updateStatus(ctx, original, recommendation)
+	}
+	r.setCondition(recommendation, overcommit.RecommendationConditionReady, metav1.ConditionTrue, reasonRecommended,
+		fmt.Sprintf("Recommended from %d samples over %s", recommended.Samples, recommended.Observed.Round(time.Second)))
+
+	if err := r.apply(ctx, recommendation, overcommitClass, recommended); err != nil {
+		logger.Error(err, "Failed to apply the recommended ratios", "class", overcommitClass.Name)
+		return ctrl.Result{}, err
+	}
+	return r.updateStatus(ctx, original, recommendation)
+}
+
+// classPods returns the running pods mutated by the class.
+func (r *RecommendationReconciler) classPods(ctx context.Context, className string) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+	list := &corev1.PodList{}
+	for {
+		if err := r.APIReader.List(ctx, list, client.Limit(podPageSize), client.Continue(list.Continue)); err != nil {
+			return nil, err
+		}
+		for _, pod := range list.Items {
+			if pod.Annotations[overcommit.AppliedAnnotation] == className && pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp.IsZero() {
+				pods = append(pods, pod)
+			}
+		}
+		if list.Continue == "" {
+			return pods, nil
+		}
+	}
+}
+
+// apply applies the recommended ratios, within the bounds of autoApply, to the class once the usage was
+// observed for half the window at least. The ratios of a resource without samples, or changing less than
+// applyThreshold, are left unchanged.
+func (r *RecommendationReconciler) apply(ctx context.Context, recommendation *overcommit.OvercommitRecommendation, overcommitClass *overcommit.OvercommitClass, recommended recommender.Recommendation) error {
+	if !recommendation.AutoApplyEnabled() {
+		meta.RemoveStatusCondition(&recommendation.Status.Conditions, overcommit.RecommendationConditionApplied)
+		return nil
+	}
+	if recommended.Observed < recommendation.Spec.Window.Duration/2 {
+		r.setCondition(recommendation, overcommit.RecommendationConditionApplied, metav1.ConditionFalse, reasonObservationTooShort,
+			fmt.Sprintf("The usage was observed for %s, half the window is required", recommended.Observed.Round(time.Second)))
+		return nil
+	}
+
+	policy := recommendation.Spec.AutoApply
+	cpu := boundedRatio(overcommitClass.Spec.CpuOvercommit, recommended.CpuOvercommit, policy.MinCpuOvercommit, policy.MaxCpuOvercommit)
+	memory := boundedRatio(overcommitClass.Spec.MemoryOvercommit, recommended.MemoryOvercommit, policy.MinMemoryOvercommit, policy.MaxMemoryOvercommit)
+	if cpu == overcommitClass.Spec.CpuOvercommit && memory == overcommitClass.Spec.MemoryOvercommit {
+		r.setCondition(recommendation, overcommit.RecommendationConditionApplied, metav1.ConditionTrue, reasonUpToDate,
+			fmt.Sprintf("OvercommitClass %s reflects the recommended ratios", overcommitClass.Name))
+		return nil
+	}
+
+	patch := client.MergeFrom(overcommitClass.DeepCopy())
+	overcommitClass.Spec.CpuOvercommit = cpu
+	overcommitClass.Spec.MemoryOvercommit = memory
+	if err := r.Patch(ctx, overcommitClass, patch); err != nil {
+		return err
+	}
+	now := metav1.Now()
+	recommendation.Status.LastAppliedTime = &now
+	message := fmt.Sprintf("Applied CPU Overcommit = %.2f, Memory Overcommit = %.2f to OvercommitClass %s", cpu, memory, overcommitClass.Name)
+	r.setCondition(recommendation, overcommit.RecommendationConditionApplied, metav1.ConditionTrue, reasonApplied, message)
+	r.Recorder.Event(recommendation, corev1.EventTypeNormal, "OvercommitRecommendationApplied", message)
+	log.FromContext(ctx).Info("Applied the recommended ratios", "class", overcommitClass.Name, "cpu", cpu, "memory", memory)
+	return nil
+}
+
+// boundedRatio returns the recommended ratio bounded to [lower, upper], or the current one when there is no
+// recommendation or it changes less than applyThreshold.
+func boundedRatio(current, recommended, lower, upper float64) float64 {
+	if recommended == 0 {
+		return current
+	}
+	bounded := math.Min(math.Max(recommended, lower), upper)
+	if math.Abs(bounded-current) < applyThreshold {
+		return current
+	}
+	return bounded
+}
+
+func (r *RecommendationReconciler) setCondition(recommendation *overcommit.OvercommitRecommendation, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&recommendation.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: recommendation.Generation,
+	})
+}
+
+// updateStatus patches the status of the recommendation, then requeues it for the next sample.
+func (r *RecommendationReconciler) updateStatus(ctx context.Context, original, recommendation *overcommit.OvercommitRecommendation) (ctrl.Result, error) {
+	if err := r.Status().Patch(ctx, recommendation, client.MergeFrom(original)); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.FromContext(ctx).Error(err, "Failed to update the recommendation status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: recommendationResyncPeriod}, nil
+}
+
+// source returns the source of the usage of a recommendation, created again when its source changed.
+func (r *RecommendationReconciler) source(recommendation *overcommit.OvercommitRecommendation) recommender.Source {
+	url := ""
+	if recommendation.Spec.Prometheus != nil {
+		url = recommendation.Spec.Prometheus.URL
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sources == nil {
+		r.sources = map[string]cachedSource{}
+	}
+	cached, ok := r.sources[recommendation.Name]
+	if ok && cached.kind == recommendation.Spec.Source && cached.url == url {
+		return cached.source
+	}
+
+	var source recommender.Source
+	switch {
+	case r.NewSource != nil:
+		source = r.NewSource(recommendation)
+	case recommendation.Spec.Source == overcommit.UsageSourcePrometheus:
+		source = &recommender.PrometheusSource{URL: url}
+	default:
+		source = &recommender.MetricsAPISource{Reader: r.APIReader}
+	}
+	r.sources[recommendation.Name] = cachedSource{kind: recommendation.Spec.Source, url: url, source: source}
+	return source
+}
+
+func (r *RecommendationReconciler) forgetSource(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sources, name)
+}
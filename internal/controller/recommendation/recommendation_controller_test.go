@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/recommender"
+)
+
+// stubSource returns fixed samples, or an error.
+type stubSource struct {
+	samples []recommender.Sample
+	err     error
+}
+
+func (s *stubSource) Samples(_ context.Context, _ []corev1.Pod, _ time.Duration) ([]recommender.Sample, error) {
+	return s.samples, s.err
+}
+
+var _ = Describe("Recommendation", func() {
+	var (
+		k8sClient client.Client
+		recorder  *record.FakeRecorder
+		source    *stubSource
+		r         *RecommendationReconciler
+	)
+
+	pod := func(name string) *corev1.Pod {
+		return controllertest.Pod("apps", name, "standard", controllertest.Container(nil, controllertest.Resources("1", "1Gi")))
+	}
+	// usage returns samples of the web pod over the span, using a share of its limits
+	usage := func(cpu, memory string, span time.Duration) []recommender.Sample {
+		cpuUsage, memoryUsage := resource.MustParse(cpu), resource.MustParse(memory)
+		now := time.Now()
+		return []recommender.Sample{
+			{Namespace: "apps", Pod: "web", Container: "app", Time: now.Add(-span), CPU: &cpuUsage, Memory: &memoryUsage},
+			{Namespace: "apps", Pod: "web", Container: "app", Time: now, CPU: &cpuUsage, Memory: &memoryUsage},
+		}
+	}
+	recommendation := func(autoApply *overcommit.AutoApplyPolicy) *overcommit.OvercommitRecommendation {
+		return &overcommit.OvercommitRecommendation{
+			ObjectMeta: metav1.ObjectMeta{Name: "standard"},
+			Spec: overcommit.OvercommitRecommendationSpec{
+				ClassName:  "standard",
+				Source:     overcommit.UsageSourceMetricsAPI,
+				Percentile: 95,
+				Window:     metav1.Duration{Duration: 24 * time.Hour},
+				Scope:      overcommit.RecommendationScopeClass,
+				AutoApply:  autoApply,
+			},
+		}
+	}
+	autoApply := &overcommit.AutoApplyPolicy{Enabled: true, MinCpuOvercommit: 0.1, MaxCpuOvercommit: 1, MinMemoryOvercommit: 0.5, MaxMemoryOvercommit: 1}
+	setup := func(objects ...client.Object) {
+		k8sClient = controllertest.NewClientBuilder().
+			WithStatusSubresource(&overcommit.OvercommitRecommendation{}).
+			WithObjects(objects...).
+			Build()
+		recorder = record.NewFakeRecorder(10)
+		source = &stubSource{}
+		r = &RecommendationReconciler{
+			Client:    k8sClient,
+			Scheme:    controllertest.Scheme(),
+			APIReader: k8sClient,
+			Recorder:  recorder,
+			NewSource: func(*overcommit.OvercommitRecommendation) recommender.Source { return source },
+		}
+	}
+	class := func() *overcommit.OvercommitClass {
+		return controllertest.Class("standard", 0.5, 0.8)
+	}
+	reconcile := func() ctrl.Result {
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "standard"}})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+	getRecommendation := func() *overcommit.OvercommitRecommendation {
+		recommendation := &overcommit.OvercommitRecommendation{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "standard"}, recommendation)).To(Succeed())
+		return recommendation
+	}
+	getClass := func() *overcommit.OvercommitClass {
+		overcommitClass := &overcommit.OvercommitClass{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "standard"}, overcommitClass)).To(Succeed())
+		return overcommitClass
+	}
+
+	It("should recommend the ratios without changing the class", func() {
+		setup(recommendation(nil), class(), pod("web"))
+		source.samples = usage("200m", "512Mi", time.Hour)
+
+		result := reconcile()
+
+		Expect(result.RequeueAfter).To(Equal(recommendationResyncPeriod))
+		status := getRecommendation().Status
+		Expect(status.CpuOvercommit).To(Equal(0.2))
+		Expect(status.MemoryOvercommit).To(Equal(0.5))
+		Expect(status.Samples).To(Equal(int32(2)))
+		Expect(status.LastUpdateTime).NotTo(BeNil())
+		Expect(meta.IsStatusConditionTrue(status.Conditions, overcommit.RecommendationConditionReady)).To(BeTrue())
+		Expect(meta.FindStatusCondition(status.Conditions, overcommit.RecommendationConditionApplied)).To(BeNil())
+		Expect(getClass().Spec.CpuOvercommit).To(Equal(0.5))
+	})
+
+	It("should recommend ratios for every owner with the Owner scope", func() {
+		byOwner := recommendation(nil)
+		byOwner.Spec.Scope = overcommit.RecommendationScopeOwner
+		setup(byOwner, class(), pod("web"))
+		source.samples = usage("200m", "512Mi", time.Hour)
+
+		reconcile()
+
+		owners := getRecommendation().Status.Owners
+		Expect(owners).To(HaveLen(1))
+		Expect(owners[0].Kind).To(Equal("Pod"))
+		Expect(owners[0].Name).To(Equal("web"))
+		Expect(owners[0].CpuOvercommit).To(Equal(0.2))
+	})
+
+	It("should apply the bounded ratios to the class", func() {
+		setup(recommendation(autoApply), class(), pod("web"))
+		source.samples = usage("50m", "256Mi", 13*time.Hour)
+
+		reconcile()
+
+		overcommitClass := getClass()
+		Expect(overcommitClass.Spec.CpuOvercommit).To(Equal(0.1))
+		Expect(overcommitClass.Spec.MemoryOvercommit).To(Equal(0.5))
+		status := getRecommendation().Status
+		Expect(status.LastAppliedTime).NotTo(BeNil())
+		applied := meta.FindStatusCondition(status.Conditions, overcommit.RecommendationConditionApplied)
+		Expect(applied).NotTo(BeNil())
+		Expect(applied.Reason).To(Equal(reasonApplied))
+		Expect(recorder.Events).To(Receive(ContainSubstring("OvercommitRecommendationApplied")))
+	})
+
+	It("should leave the class unchanged for small changes", func() {
+		setup(recommendation(autoApply), class(), pod("web"))
+		source.samples = usage("520m", "800Mi", 13*time.Hour)
+
+		reconcile()
+
+		Expect(getClass().Spec.CpuOvercommit).To(Equal(0.5))
+		applied := meta.FindStatusCondition(getRecommendation().Status.Conditions, overcommit.RecommendationConditionApplied)
+		Expect(applied.Reason).To(Equal(reasonUpToDate))
+	})
+
+	It("should wait for half the window before applying the ratios", func() {
+		setup(recommendation(autoApply), class(), pod("web"))
+		source.samples = usage("50m", "256Mi", time.Hour)
+
+		reconcile()
+
+		Expect(getClass().Spec.CpuOvercommit).To(Equal(0.5))
+		applied := meta.FindStatusCondition(getRecommendation().Status.Conditions, overcommit.RecommendationConditionApplied)
+		Expect(applied.Status).To(Equal(metav1.ConditionFalse))
+		Expect(applied.Reason).To(Equal(reasonObservationTooShort))
+	})
+
+	It("should report the pods without samples", func() {
+		setup(recommendation(autoApply), class(), pod("web"))
+
+		reconcile()
+
+		ready := meta.FindStatusCondition(getRecommendation().Status.Conditions, overcommit.RecommendationConditionReady)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(reasonNoSamples))
+	})
+
+	It("should report the usage that cannot be read", func() {
+		setup(recommendation(nil), class(), pod("web"))
+		source.err = errors.New("the server could not find the requested resource")
+
+		reconcile()
+
+		ready := meta.FindStatusCondition(getRecommendation().Status.Conditions, overcommit.RecommendationConditionReady)
+		Expect(ready.Reason).To(Equal(reasonUsageUnavailable))
+		Expect(ready.Message).To(ContainSubstring("could not find"))
+	})
+
+	It("should report a missing class", func() {
+		setup(recommendation(nil))
+
+		reconcile()
+
+		ready := meta.FindStatusCondition(getRecommendation().Status.Conditions, overcommit.RecommendationConditionReady)
+		Expect(ready.Reason).To(Equal(reasonClassNotFound))
+	})
+})
@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// reportPeriod is how often the reports are regenerated.
+	reportPeriod = 10 * time.Minute
+	// podPageSize is the number of pods read from the API server at once.
+	podPageSize = 500
+	// defaultTopWorkloads is the number of top workloads of the report created by the operator.
+	defaultTopWorkloads = 10
+)
+
+// ReportReconciler regularly regenerates the OvercommitReports from the pods mutated by the classes, summing
+// the resources their overcommit saves by class, namespace and workload. It creates the default report along
+// with the Overcommit.
+type ReportReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// APIReader reads the pods, whose specs are not cached.
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitreports,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitreports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommits,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&overcommit.OvercommitReport{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The default report is created once the Overcommit exists
+		Watches(&overcommit.Overcommit{}, handler.EnqueueRequestsFromMapFunc(r.requestsForOvercommit),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("OvercommitReport").
+		Complete(r)
+}
+
+func (r *ReportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	report := &overcommit.OvercommitReport{}
+	if err := r.Get(ctx, req.NamespacedName, report); err != nil {
+		if !apierrors.IsNotFound(err) || req.Name != overcommit.DefaultReportName {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		if report, err = r.createDefaultReport(ctx); report == nil || err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if !report.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	pods, err := r.listPods(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to list Pods")
+		return ctrl.Result{}, err
+	}
+
+	patch := client.MergeFrom(report.DeepCopy())
+	report.Status = newReport(pods).status(report.Spec)
+	now := metav1.Now()
+	report.Status.LastUpdateTime = &now
+	if err := r.Status().Patch(ctx, report, patch); err != nil {
+		logger.Error(err, "Failed to update the report status", "report", report.Name)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	logger.Info("Report regenerated", "report", report.Name, "pods", len(pods))
+	return ctrl.Result{RequeueAfter: reportPeriod}, nil
+}
+
+// createDefaultReport creates the default report, owned by the Overcommit to be deleted along with it. It
+// returns nil while the Overcommit does not exist.
+func (r *ReportReconciler) createDefaultReport(ctx context.Context) (*overcommit.OvercommitReport, error) {
+	overcommitResource := &overcommit.Overcommit{}
+	if err := r.Get(ctx, client.ObjectKey{Name: "cluster"}, overcommitResource); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	report := &overcommit.OvercommitReport{
+		ObjectMeta: metav1.ObjectMeta{Name: overcommit.DefaultReportName},
+		Spec: overcommit.OvercommitReportSpec{
+			TopWorkloads: defaultTopWorkloads,
+			RankBy:       overcommit.ReportRankingMemory,
+		},
+	}
+	if err := ctrl.SetControllerReference(overcommitResource, report, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, report); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to create the default report")
+		return nil, err
+	}
+	return report, nil
+}
+
+// listPods reads the pods by pages, keeping the ones mutated by a class that are not terminated.
+func (r *ReportReconciler) listPods(ctx context.Context) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+	list := &corev1.PodList{}
+	for {
+		if err := r.APIReader.List(ctx, list, client.Limit(podPageSize), client.Continue(list.Continue)); err != nil {
+			return nil, err
+		}
+		for _, pod := range list.Items {
+			if pod.Annotations[overcommit.AppliedAnnotation] != "" && pod.DeletionTimestamp.IsZero() &&
+				pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				pods = append(pods, pod)
+			}
+		}
+		if list.Continue == "" {
+			return pods, nil
+		}
+	}
+}
+
+// requestsForOvercommit enqueues the default report when the Overcommit changes.
+func (r *ReportReconciler) requestsForOvercommit(_ context.Context, _ client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: overcommit.DefaultReportName}}}
+}
+
+// workloadKey identifies a workload.
+type workloadKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// workload holds the savings of a workload along the number of its pods mutated by every class.
+type workload struct {
+	savings overcommit.Savings
+	classes map[string]int32
+}
+
+// report sums the savings of the mutated pods by class, namespace and workload.
+type report struct {
+	total      overcommit.Savings
+	classes    map[string]*overcommit.Savings
+	namespaces map[string]*overcommit.Savings
+	workloads  map[workloadKey]*workload
+}
+
+func newReport(pods []corev1.Pod) *report {
+	r := &report{
+		classes:    map[string]*overcommit.Savings{},
+		namespaces: map[string]*overcommit.Savings{},
+		workloads:  map[workloadKey]*workload{},
+	}
+	for i := range pods {
+		r.add(&pods[i])
+	}
+	return r
+}
+
+// add records the savings of a pod under the class recorded in its applied annotation, its namespace and its
+// workload.
+func (r *report) add(pod *corev1.Pod) {
+	class := pod.Annotations[overcommit.AppliedAnnotation]
+	kind, name := utils.PodWorkload(pod)
+	key := workloadKey{kind: kind, namespace: pod.Namespace, name: name}
+	if r.classes[class] == nil {
+		r.classes[class] = &overcommit.Savings{}
+	}
+	if r.namespaces[pod.Namespace] == nil {
+		r.namespaces[pod.Namespace] = &overcommit.Savings{}
+	}
+	if r.workloads[key] == nil {
+		r.workloads[key] = &workload{classes: map[string]int32{}}
+	}
+	r.workloads[key].classes[class]++
+
+	for _, savings := range []*overcommit.Savings{&r.total, r.classes[class], r.namespaces[pod.Namespace], &r.workloads[key].savings} {
+		addPod(savings, &pod.Spec)
+	}
+}
+
+// status builds the status of a report, its classes and namespaces sorted by name and its top workloads by the
+// resource they save the most of.
+func (r *report) status(spec overcommit.OvercommitReportSpec) overcommit.OvercommitReportStatus {
+	total := r.total
+	status := overcommit.OvercommitReportStatus{Total: &total}
+	for class, savings := range r.classes {
+		status.Classes = append(status.Classes, overcommit.ClassSavings{Class: class, Savings: *savings})
+	}
+	sort.Slice(status.Classes, func(i, j int) bool { return status.Classes[i].Class < status.Classes[j].Class })
+	for namespace, savings := range r.namespaces {
+		status.Namespaces = append(status.Namespaces, overcommit.NamespaceSavings{Namespace: namespace, Savings: *savings})
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool { return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace })
+
+	workloads := make([]overcommit.WorkloadSavings, 0, len(r.workloads))
+	for key, observed := range r.workloads {
+		workloads = append(workloads, overcommit.WorkloadSavings{
+			Kind:      key.kind,
+			Namespace: key.namespace,
+			Name:      key.name,
+			Class:     observed.class(),
+			Savings:   observed.savings,
+		})
+	}
+	saved := func(savings *overcommit.Savings) *overcommit.ResourceSavings {
+		if spec.RankBy == overcommit.ReportRankingCPU {
+			return &savings.Cpu
+		}
+		return &savings.Memory
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		a, b := workloads[i], workloads[j]
+		if c := saved(&a.Savings).Saved.Cmp(saved(&b.Savings).Saved); c != 0 {
+			return c > 0
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	if len(workloads) > int(spec.TopWorkloads) {
+		workloads = workloads[:spec.TopWorkloads]
+	}
+	if len(workloads) > 0 {
+		status.TopWorkloads = workloads
+	}
+	return status
+}
+
+// class returns the class that mutated most of the pods of the workload, the first by name on a tie.
+func (w *workload) class() string {
+	var class string
+	for name, pods := range w.classes {
+		if pods > w.classes[class] || (pods == w.classes[class] && name < class) {
+			class = name
+		}
+	}
+	return class
+}
+
+// addPod adds a pod to the savings. The requests and limits of its containers and sidecars are summed, the
+// containers with limits saving the difference between their limits and requests.
+func addPod(savings *overcommit.Savings, spec *corev1.PodSpec) {
+	savings.Pods++
+	for _, container := range spec.Containers {
+		addContainer(savings, container.Resources)
+	}
+	for _, container := range spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addContainer(savings, container.Resources)
+		}
+	}
+}
+
+func addContainer(savings *overcommit.Savings, resources corev1.ResourceRequirements) {
+	for name, total := range map[corev1.ResourceName]*overcommit.ResourceSavings{corev1.ResourceCPU: &savings.Cpu, corev1.ResourceMemory: &savings.Memory} {
+		requests := resources.Requests[name]
+		total.Requests.Add(requests)
+		limits, ok := resources.Limits[name]
+		if !ok {
+			continue
+		}
+		total.Limits.Add(limits)
+		if limits.Cmp(requests) > 0 {
+			limits.Sub(requests)
+			total.Saved.Add(limits)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+)
+
+var _ = Describe("Report", func() {
+	var (
+		k8sClient client.Client
+		r         *ReportReconciler
+	)
+
+	resources, container, pod := controllertest.Resources, controllertest.Container, controllertest.Pod
+	replica := func(name, replicaSet, class string) *corev1.Pod {
+		pod := pod("apps", name, class, container(resources("500m", "1Gi"), resources("1", "2Gi")))
+		pod.Labels = map[string]string{"pod-template-hash": "7d9f8"}
+		pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: replicaSet, UID: "web", Controller: ptr.To(true)}}
+		return pod
+	}
+	setup := func(objects ...client.Object) {
+		k8sClient = controllertest.NewClientBuilder().
+			WithStatusSubresource(&overcommit.OvercommitReport{}).
+			WithObjects(objects...).
+			Build()
+		r = &ReportReconciler{Client: k8sClient, Scheme: controllertest.Scheme(), APIReader: k8sClient}
+	}
+	reconcile := func(name string) *overcommit.OvercommitReport {
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: name}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reportPeriod))
+		report := &overcommit.OvercommitReport{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: name}, report)).To(Succeed())
+		return report
+	}
+	quantity := func(value string) resource.Quantity {
+		return resource.MustParse(value)
+	}
+
+	It("creates the default report along with the Overcommit", func() {
+		setup(&overcommit.Overcommit{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			pod("apps", "web", "high", container(resources("1", "1Gi"), resources("2", "4Gi"))))
+
+		report := reconcile(overcommit.DefaultReportName)
+		Expect(report.Spec.TopWorkloads).To(BeEquivalentTo(defaultTopWorkloads))
+		// The report is deleted along with the Overcommit
+		Expect(report.OwnerReferences).To(HaveLen(1))
+		Expect(report.OwnerReferences[0].Kind).To(Equal("Overcommit"))
+		Expect(report.Status.Total.Pods).To(BeEquivalentTo(1))
+		Expect(report.Status.LastUpdateTime).NotTo(BeNil())
+	})
+
+	It("does not create the default report without the Overcommit", func() {
+		setup()
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: overcommit.DefaultReportName}})
+		Expect(err).NotTo(HaveOccurred())
+		reports := &overcommit.OvercommitReportList{}
+		Expect(k8sClient.List(context.Background(), reports)).To(Succeed())
+		Expect(reports.Items).To(BeEmpty())
+	})
+
+	It("sums the savings of the mutated pods by class, namespace and workload", func() {
+		finished := pod("apps", "finished", "high", container(resources("1", "1Gi"), resources("4", "4Gi")))
+		finished.Status.Phase = corev1.PodSucceeded
+		sidecar := container(resources("100m", "128Mi"), resources("200m", "256Mi"))
+		sidecar.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		batch := pod("batch", "job", "low",
+			container(resources("1", "1Gi"), resources("4", "2Gi")),
+			// Containers without limits save nothing
+			container(resources("1", "1Gi"), nil))
+		batch.Spec.InitContainers = []corev1.Container{sidecar, container(resources("8", "8Gi"), resources("8", "8Gi"))}
+		setup(&overcommit.OvercommitReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "savings"},
+			Spec:       overcommit.OvercommitReportSpec{TopWorkloads: 2, RankBy: overcommit.ReportRankingCPU},
+		},
+			replica("web-1", "web-7d9f8", "high"),
+			replica("web-2", "web-7d9f8", "high"),
+			batch,
+			pod("apps", "cache", "high", container(resources("1", "3Gi"), resources("1", "4Gi"))),
+			// Pods never mutated and finished pods are left out
+			pod("apps", "db", "", container(resources("1", "1Gi"), resources("2", "2Gi"))),
+			finished)
+
+		status := reconcile("savings").Status
+		Expect(status.Total.Pods).To(BeEquivalentTo(4))
+		Expect(status.Total.Cpu.Limits.Cmp(quantity("7200m"))).To(BeZero())
+		Expect(status.Total.Cpu.Requests.Cmp(quantity("4100m"))).To(BeZero())
+		Expect(status.Total.Cpu.Saved.Cmp(quantity("4100m"))).To(BeZero())
+		Expect(status.Total.Memory.Saved.Cmp(quantity("4224Mi"))).To(BeZero())
+
+		Expect(status.Classes).To(HaveLen(2))
+		Expect(status.Classes[0].Class).To(Equal("high"))
+		Expect(status.Classes[0].Pods).To(BeEquivalentTo(3))
+		Expect(status.Classes[0].Memory.Saved.Cmp(quantity("3Gi"))).To(BeZero())
+		Expect(status.Classes[1].Class).To(Equal("low"))
+		Expect(status.Classes[1].Cpu.Saved.Cmp(quantity("3100m"))).To(BeZero())
+
+		Expect(status.Namespaces).To(HaveLen(2))
+		Expect(status.Namespaces[0].Namespace).To(Equal("apps"))
+		Expect(status.Namespaces[0].Pods).To(BeEquivalentTo(3))
+		Expect(status.Namespaces[1].Namespace).To(Equal("batch"))
+
+		// The replicas of a Deployment are reported together, ranked by the cpu they save
+		Expect(status.TopWorkloads).To(HaveLen(2))
+		Expect(status.TopWorkloads[0].Kind).To(Equal("Pod"))
+		Expect(status.TopWorkloads[0].Name).To(Equal("job"))
+		Expect(status.TopWorkloads[0].Class).To(Equal("low"))
+		Expect(status.TopWorkloads[1].Kind).To(Equal("Deployment"))
+		Expect(status.TopWorkloads[1].Name).To(Equal("web"))
+		Expect(status.TopWorkloads[1].Pods).To(BeEquivalentTo(2))
+		Expect(status.TopWorkloads[1].Cpu.Saved.Cmp(quantity("1"))).To(BeZero())
+	})
+})
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// DecisionReconciler records again the decision of the pods whose limits were changed through the pods/resize
+// subresource. The webhook recomputes their requests on the resize, but the subresource drops the annotations
+// it writes, so the decision of the pod would no longer match its requests.
+type DecisionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Instance is the name of the pod running the controller, recorded in the decision of the resized pods.
+	Instance string
+}
+
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DecisionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Only the mutated pods whose limits change, or were changed while the controller was not running
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return e.Object.GetAnnotations()[overcommitpkg.AnnotationOvercommitDecision] != ""
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectNew.GetAnnotations()[overcommitpkg.AnnotationOvercommitDecision] != "" &&
+					limitsChanged(e.ObjectOld.(*corev1.Pod), e.ObjectNew.(*corev1.Pod))
+			},
+			DeleteFunc:  func(event.DeleteEvent) bool { return false },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		})).
+		Named("OvercommitDecision").
+		Complete(r)
+}
+
+func (r *DecisionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !pod.DeletionTimestamp.IsZero() || pod.Annotations[overcommitpkg.AnnotationOvercommitDecision] == "" {
+		return ctrl.Result{}, nil
+	}
+
+	overcommitClass := &overcommit.OvercommitClass{}
+	if err := r.Get(ctx, client.ObjectKey{Name: pod.Annotations[overcommit.AppliedAnnotation]}, overcommitClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	resynced := pod.DeepCopy()
+	changed, err := overcommitpkg.Resync(resynced, overcommitClass, r.Instance)
+	if err != nil {
+		logger.Error(err, "Failed to read the overcommit decision of the pod", "namespace", pod.Namespace, "pod", pod.Name)
+		return ctrl.Result{}, nil
+	}
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Patch(ctx, resynced, client.MergeFrom(pod)); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	logger.Info("Recorded the overcommit decision of the resized pod", "namespace", pod.Namespace, "pod", pod.Name, "class", overcommitClass.Name)
+	return ctrl.Result{}, nil
+}
+
+// limitsChanged reports whether the limits of any container differ between two versions of a pod.
+func limitsChanged(pod, resized *corev1.Pod) bool {
+	for i := range pod.Spec.Containers {
+		if i >= len(resized.Spec.Containers) || !equality.Semantic.DeepEqual(pod.Spec.Containers[i].Resources.Limits, resized.Spec.Containers[i].Resources.Limits) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+)
+
+var _ = Describe("Decision", func() {
+	var (
+		k8sClient client.Client
+		r         *DecisionReconciler
+	)
+
+	overcommitClass := controllertest.Class("standard", 0.5, 0.5)
+	overcommitClass.Generation = 2
+	// resized returns a running pod mutated by the standard class whose cpu limit was raised to 2 through the
+	// pods/resize subresource, with the given cpu request and the decision of its creation
+	resized := func(cpuRequest string) *corev1.Pod {
+		pod := controllertest.Pod("apps", "web", "standard", controllertest.Container(
+			corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			controllertest.Resources("1", "1Gi"),
+		))
+		pod.Annotations[overcommitpkg.AnnotationOvercommitDecision] = `{"class":"standard","cpu":0.25,"memory":0.5,"pass":1}`
+		// The decision of the creation is recorded with the ratios of the class
+		changed, err := overcommitpkg.Rebalance(pod, overcommitClass, "webhook-0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		pod.Spec.Containers[0].Resources.Limits[corev1.ResourceCPU] = resource.MustParse("2")
+		pod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse(cpuRequest)
+		return pod
+	}
+	setup := func(objects ...client.Object) {
+		k8sClient = controllertest.NewClientBuilder(append(objects, overcommitClass.DeepCopy())...).Build()
+		r = &DecisionReconciler{Client: k8sClient, Scheme: controllertest.Scheme(), Instance: "controller-0"}
+	}
+	reconcile := func() *overcommitpkg.Decision {
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "apps", Name: "web"}})
+		Expect(err).NotTo(HaveOccurred())
+		pod := &corev1.Pod{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "web"}, pod)).To(Succeed())
+		decision, err := overcommitpkg.GetDecision(pod)
+		Expect(err).NotTo(HaveOccurred())
+		return decision
+	}
+
+	It("should keep the original requests of the containers recomputed by the webhook", func() {
+		setup(resized("1"))
+
+		decision := reconcile()
+
+		Expect(decision.Pass).To(Equal(3))
+		Expect(decision.Webhook).To(Equal("controller-0"))
+		Expect(decision.Containers).To(HaveLen(1))
+		Expect(decision.Containers[0].Pass).To(Equal(3))
+		Expect(decision.Containers[0].Original.Requests.Cpu().MilliValue()).To(Equal(int64(100)))
+		Expect(decision.Containers[0].Original.Limits.Cpu().MilliValue()).To(Equal(int64(2000)))
+		Expect(decision.Containers[0].Requests.Cpu().MilliValue()).To(Equal(int64(1000)))
+	})
+
+	It("should take the requests left by the resize as the original ones", func() {
+		setup(resized("1500m"))
+
+		decision := reconcile()
+
+		Expect(decision.Containers[0].Pass).To(BeZero())
+		Expect(decision.Containers[0].Original.Requests.Cpu().MilliValue()).To(Equal(int64(1500)))
+		Expect(decision.Containers[0].Requests.Cpu().MilliValue()).To(Equal(int64(1500)))
+	})
+
+	It("should leave the decision of the pods whose limits did not change", func() {
+		pod := resized("1")
+		pod.Spec.Containers[0].Resources.Limits[corev1.ResourceCPU] = resource.MustParse("1")
+		pod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("500m")
+		recorded := pod.Annotations[overcommitpkg.AnnotationOvercommitDecision]
+		setup(pod)
+
+		decision := reconcile()
+
+		expected := &overcommitpkg.Decision{}
+		Expect(json.Unmarshal([]byte(recorded), expected)).To(Succeed())
+		Expect(decision).To(Equal(expected))
+	})
+})
@@ -0,0 +1,330 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// resizeResyncPeriod is how often the pods of a class are checked for outdated overcommit values.
+const resizeResyncPeriod = time.Minute
+
+// Outcomes of the in-place resize of a pod, used as the outcome label of the resize metric.
+const (
+	resizeOutcomeResized         = "resized"
+	resizeOutcomeRestartRequired = "restart_required"
+	resizeOutcomeFailed          = "failed"
+)
+
+// ResizeReconciler resizes in place the running pods of the classes with inPlaceResize enabled when their
+// ratios change, through the pods/resize subresource and without restarting them.
+type ResizeReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// APIReader reads the running pods, whose specs are not cached.
+	APIReader client.Reader
+	Recorder  record.EventRecorder
+	// Instance is the name of the pod running the controller, recorded in the decision of the resized pods.
+	Instance string
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=pods/resize,verbs=update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;update
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ResizeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("overcommit-resize")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&overcommit.OvercommitClass{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Resuming the Overcommit resumes the resizes of every class
+		Watches(&overcommit.Overcommit{}, handler.EnqueueRequestsFromMapFunc(r.requestsForOvercommit),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("OvercommitClassResize").
+		Complete(r)
+}
+
+func (r *ResizeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	overcommitClass := &overcommit.OvercommitClass{}
+	if err := r.Get(ctx, req.NamespacedName, overcommitClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.forgetLimiter(req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !overcommitClass.InPlaceResizeEnabled() || !overcommitClass.DeletionTimestamp.IsZero() {
+		r.forgetLimiter(overcommitClass.Name)
+		return ctrl.Result{}, nil
+	}
+
+	overcommitResource, err := utils.GetOvercommit(ctx, r.Client)
+	if err != nil {
+		logger.Error(err, "Failed to get Overcommit")
+		return ctrl.Result{}, err
+	}
+	// The webhook would not recompute the requests of the resized pods
+	if overcommitResource.Spec.Paused || overcommitClass.Spec.Suspended {
+		logger.Info("Overcommit is paused or the class is suspended, skipping the resize", "class", overcommitClass.Name)
+		return ctrl.Result{}, nil
+	}
+
+	outdated, err := r.outdatedPods(ctx, overcommitClass)
+	if err != nil {
+		logger.Error(err, "Failed to find the pods with outdated overcommit values")
+		return ctrl.Result{}, err
+	}
+
+	status := &overcommit.InPlaceResizeStatus{}
+	if overcommitClass.Status.InPlaceResize != nil {
+		status = overcommitClass.Status.InPlaceResize.DeepCopy()
+	}
+	if status.ObservedGeneration != overcommitClass.Generation {
+		status.ObservedGeneration = overcommitClass.Generation
+		status.ResizedPods = 0
+	}
+	status.RestartRequiredPods = 0
+
+	requeueAfter := resizeResyncPeriod
+	limiter := r.limiter(overcommitClass)
+	remaining := len(outdated)
+	for _, key := range outdated {
+		pod := &corev1.Pod{}
+		if err := r.APIReader.Get(ctx, key, pod); err != nil {
+			if apierrors.IsNotFound(err) {
+				remaining--
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		if pod.Status.Phase != corev1.PodRunning || resizing(pod) {
+			continue
+		}
+
+		resized := pod.DeepCopy()
+		changed, err := overcommitpkg.Rebalance(resized, overcommitClass, r.Instance)
+		if err != nil {
+			logger.Error(err, "Failed to recompute the requests of the pod", "namespace", pod.Namespace, "pod", pod.Name)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if containers := restartRequired(pod, resized); len(containers) > 0 {
+			status.RestartRequiredPods++
+			metrics.K8sOvercommitOperatorPodResizesTotal.WithLabelValues(overcommitClass.Name, resizeOutcomeRestartRequired).Inc()
+			r.Recorder.Eventf(pod, corev1.EventTypeWarning, "OvercommitResizeRequiresRestart",
+				"OvercommitClass %s changed, the pod must be restarted to apply it: the resize policy of containers %s requires a restart",
+				overcommitClass.Name, strings.Join(containers, ", "))
+			continue
+		}
+
+		if requestsChanged(pod, resized) {
+			if !limiter.Allow() {
+				reservation := limiter.Reserve()
+				requeueAfter = min(requeueAfter, reservation.Delay())
+				reservation.Cancel()
+				break
+			}
+			if err := r.resize(ctx, resized.DeepCopy()); err != nil {
+				logger.Error(err, "Failed to resize the pod", "namespace", pod.Namespace, "pod", pod.Name)
+				metrics.K8sOvercommitOperatorPodResizesTotal.WithLabelValues(overcommitClass.Name, resizeOutcomeFailed).Inc()
+				continue
+			}
+		}
+		// The annotations are dropped by the pods/resize subresource, they are recorded with a separate patch
+		annotated := pod.DeepCopy()
+		annotated.Annotations = resized.Annotations
+		if err := r.Patch(ctx, annotated, client.MergeFrom(pod)); err != nil {
+			logger.Error(err, "Failed to record the overcommit decision of the resized pod", "namespace", pod.Namespace, "pod", pod.Name)
+			metrics.K8sOvercommitOperatorPodResizesTotal.WithLabelValues(overcommitClass.Name, resizeOutcomeFailed).Inc()
+			continue
+		}
+
+		logger.Info("Resized pod with outdated overcommit values", "namespace", pod.Namespace, "pod", pod.Name, "class", overcommitClass.Name)
+		metrics.K8sOvercommitOperatorPodResizesTotal.WithLabelValues(overcommitClass.Name, resizeOutcomeResized).Inc()
+		r.Recorder.Eventf(pod, corev1.EventTypeNormal, "OvercommitResized",
+			"Resized Pod '%s' in place: OvercommitClass = %s, CPU Overcommit = %.2f, Memory Overcommit = %.2f",
+			pod.Name, overcommitClass.Name, overcommitClass.Spec.CpuOvercommit, overcommitClass.Spec.MemoryOvercommit)
+		remaining--
+		status.ResizedPods++
+		status.LastResizeTime = &metav1.Time{Time: time.Now()}
+	}
+	status.OutdatedPods = int32(remaining)
+
+	if equality.Semantic.DeepEqual(overcommitClass.Status.InPlaceResize, status) {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	patch := client.MergeFrom(overcommitClass.DeepCopy())
+	overcommitClass.Status.InPlaceResize = status
+	if err := r.Status().Patch(ctx, overcommitClass, patch); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to update the in-place resize status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// outdatedPods returns the pods mutated by the class with other ratios than its current ones, in a stable order.
+func (r *ResizeReconciler) outdatedPods(ctx context.Context, overcommitClass *overcommit.OvercommitClass) ([]client.ObjectKey, error) {
+	pods := &metav1.PartialObjectMetadataList{}
+	pods.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "PodList"})
+	if err := r.List(ctx, pods); err != nil {
+		return nil, err
+	}
+
+	keys := []client.ObjectKey{}
+	for i := range pods.Items {
+		annotations := pods.Items[i].GetAnnotations()
+		if !pods.Items[i].DeletionTimestamp.IsZero() ||
+			annotations[overcommit.AppliedAnnotation] != overcommitClass.Name ||
+			annotations[overcommitpkg.AnnotationOvercommitRevert] == "true" ||
+			!utils.OvercommitOutdated(annotations, &overcommitClass.Spec) {
+			continue
+		}
+		keys = append(keys, client.ObjectKeyFromObject(&pods.Items[i]))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys, nil
+}
+
+// resize requests the new requests of the containers through the pods/resize subresource. The webhook of the
+// class admits them unchanged, as the decision they were computed with is sent along.
+func (r *ResizeReconciler) resize(ctx context.Context, resized *corev1.Pod) error {
+	expected := resized.DeepCopy()
+	if err := r.SubResource("resize").Update(ctx, resized); err != nil {
+		return err
+	}
+	// Another mutating webhook could have changed the requests, the decision would then not match them
+	if requestsChanged(expected, resized) {
+		return fmt.Errorf("the requests of pod %s were changed by another webhook during its resize", resized.Name)
+	}
+	return nil
+}
+
+// resizing reports whether a resize of the pod is still pending or in progress.
+func resizing(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if (condition.Type == corev1.PodResizePending || condition.Type == corev1.PodResizeInProgress) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// requestsChanged reports whether the requests of any container differ between two versions of a pod.
+func requestsChanged(pod, resized *corev1.Pod) bool {
+	for i := range pod.Spec.Containers {
+		if i >= len(resized.Spec.Containers) || !equality.Semantic.DeepEqual(pod.Spec.Containers[i].Resources.Requests, resized.Spec.Containers[i].Resources.Requests) {
+			return true
+		}
+	}
+	return false
+}
+
+// restartRequired returns the containers whose requests change for a resource their resize policy restarts
+// them for.
+func restartRequired(pod, resized *corev1.Pod) []string {
+	var containers []string
+	for i, container := range pod.Spec.Containers {
+		for _, policy := range container.ResizePolicy {
+			if policy.RestartPolicy != corev1.RestartContainer {
+				continue
+			}
+			before, hadBefore := container.Resources.Requests[policy.ResourceName]
+			after, hasAfter := resized.Spec.Containers[i].Resources.Requests[policy.ResourceName]
+			if hadBefore != hasAfter || before.Cmp(after) != 0 {
+				containers = append(containers, container.Name)
+				break
+			}
+		}
+	}
+	return containers
+}
+
+// limiter returns the rate limiter of the resizes of a class, updated to its current rate.
+func (r *ResizeReconciler) limiter(overcommitClass *overcommit.OvercommitClass) *rate.Limiter {
+	podsPerMinute := max(overcommitClass.Spec.InPlaceResize.PodsPerMinute, 1)
+	limit := rate.Every(time.Minute / time.Duration(podsPerMinute))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.limiters == nil {
+		r.limiters = map[string]*rate.Limiter{}
+	}
+	limiter, ok := r.limiters[overcommitClass.Name]
+	if !ok {
+		limiter = rate.NewLimiter(limit, 1)
+		r.limiters[overcommitClass.Name] = limiter
+	} else if limiter.Limit() != limit {
+		limiter.SetLimit(limit)
+	}
+	return limiter
+}
+
+func (r *ResizeReconciler) forgetLimiter(className string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.limiters, className)
+}
+
+// requestsForOvercommit enqueues every OvercommitClass when the Overcommit changes.
+func (r *ResizeReconciler) requestsForOvercommit(ctx context.Context, _ client.Object) []reconcile.Request {
+	overcommitClasses := &overcommit.OvercommitClassList{}
+	if err := r.List(ctx, overcommitClasses); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list OvercommitClasses")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(overcommitClasses.Items))
+	for _, overcommitClass := range overcommitClasses.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&overcommitClass)})
+	}
+	return requests
+}
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+)
+
+var _ = Describe("Resize", func() {
+	var (
+		k8sClient client.Client
+		recorder  *record.FakeRecorder
+		r         *ResizeReconciler
+		resizes   int
+	)
+
+	limits := controllertest.Resources("1", "1Gi")
+	// mutated returns a running pod mutated by the standard class with ratios of 0.5
+	mutated := func(name string, resizePolicy ...corev1.ContainerResizePolicy) *corev1.Pod {
+		requests := controllertest.Resources("500m", "512Mi")
+		decision, err := json.Marshal(overcommitpkg.Decision{
+			Class:            "standard",
+			CpuOvercommit:    0.5,
+			MemoryOvercommit: 0.5,
+			Pass:             1,
+			Containers: []overcommitpkg.ContainerDecision{{
+				Name:     "app",
+				Original: corev1.ResourceRequirements{Limits: limits.DeepCopy()},
+				Requests: requests.DeepCopy(),
+				Pass:     1,
+			}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		container := controllertest.Container(requests, limits.DeepCopy())
+		container.ResizePolicy = resizePolicy
+		pod := controllertest.Pod("apps", name, "", container)
+		pod.Annotations = controllertest.Mutated("standard", "0.5000", "0.5000")
+		pod.Annotations[overcommitpkg.AnnotationOvercommitDecision] = string(decision)
+		return pod
+	}
+	class := func(inPlaceResize *overcommit.InPlaceResizePolicy) *overcommit.OvercommitClass {
+		class := controllertest.Class("standard", 0.25, 0.5)
+		class.Generation, class.Spec.InPlaceResize = 2, inPlaceResize
+		return class
+	}
+	resizesTotal := func(outcome string) float64 {
+		return testutil.ToFloat64(metrics.K8sOvercommitOperatorPodResizesTotal.WithLabelValues("standard", outcome))
+	}
+	setup := func(objects ...client.Object) {
+		resizes = 0
+		k8sClient = controllertest.NewClientBuilder().
+			WithStatusSubresource(&overcommit.OvercommitClass{}).
+			WithObjects(append(objects, &overcommit.Overcommit{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				// The fake client has no pods/resize subresource, it only updates the resources of the containers
+				SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					if subResource != "resize" {
+						return c.SubResource(subResource).Update(ctx, obj, opts...)
+					}
+					resized := obj.(*corev1.Pod)
+					stored := &corev1.Pod{}
+					if err := c.Get(ctx, client.ObjectKeyFromObject(resized), stored); err != nil {
+						return err
+					}
+					for i := range stored.Spec.Containers {
+						stored.Spec.Containers[i].Resources = resized.Spec.Containers[i].Resources
+					}
+					resizes++
+					if err := c.Update(ctx, stored); err != nil {
+						return err
+					}
+					stored.DeepCopyInto(resized)
+					return nil
+				},
+			}).
+			Build()
+		recorder = record.NewFakeRecorder(10)
+		r = &ResizeReconciler{Client: k8sClient, Scheme: controllertest.Scheme(), APIReader: k8sClient, Recorder: recorder, Instance: "controller-0"}
+	}
+	reconcile := func() ctrl.Result {
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "standard"}})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+	getPod := func(name string) *corev1.Pod {
+		pod := &corev1.Pod{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: name}, pod)).To(Succeed())
+		return pod
+	}
+	getStatus := func() *overcommit.InPlaceResizeStatus {
+		overcommitClass := &overcommit.OvercommitClass{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "standard"}, overcommitClass)).To(Succeed())
+		return overcommitClass.Status.InPlaceResize
+	}
+
+	It("should resize the outdated pods and record their new decision", func() {
+		setup(class(&overcommit.InPlaceResizePolicy{Enabled: true, PodsPerMinute: 10}), mutated("web"))
+		resized := resizesTotal(resizeOutcomeResized)
+
+		reconcile()
+
+		pod := getPod("web")
+		Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(250)))
+		Expect(pod.Spec.Containers[0].Resources.Requests.Memory().Value()).To(Equal(int64(536870912)))
+		Expect(pod.Annotations).To(HaveKeyWithValue("overcommit.inditex.dev/cpu", "0.2500"))
+		decision, err := overcommitpkg.GetDecision(pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decision.CpuOvercommit).To(Equal(0.25))
+		Expect(decision.ClassGeneration).To(Equal(int64(2)))
+		Expect(decision.Webhook).To(Equal("controller-0"))
+
+		status := getStatus()
+		Expect(status).NotTo(BeNil())
+		Expect(status.ObservedGeneration).To(Equal(int64(2)))
+		Expect(status.ResizedPods).To(Equal(int32(1)))
+		Expect(status.OutdatedPods).To(BeZero())
+		Expect(status.LastResizeTime).NotTo(BeNil())
+		Expect(resizesTotal(resizeOutcomeResized)).To(Equal(resized + 1))
+		Expect(recorder.Events).To(Receive(ContainSubstring("OvercommitResized")))
+	})
+
+	It("should leave the pods alone when the class does not enable it", func() {
+		setup(class(nil), mutated("web"))
+
+		reconcile()
+
+		Expect(resizes).To(BeZero())
+		Expect(getPod("web").Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(500)))
+		Expect(getStatus()).To(BeNil())
+	})
+
+	It("should report the pods whose resize policy requires a restart", func() {
+		setup(class(&overcommit.InPlaceResizePolicy{Enabled: true, PodsPerMinute: 10}),
+			mutated("web", corev1.ContainerResizePolicy{ResourceName: corev1.ResourceCPU, RestartPolicy: corev1.RestartContainer}))
+		restartRequired := resizesTotal(resizeOutcomeRestartRequired)
+
+		reconcile()
+
+		Expect(resizes).To(BeZero())
+		Expect(getPod("web").Annotations).To(HaveKeyWithValue("overcommit.inditex.dev/cpu", "0.5000"))
+		status := getStatus()
+		Expect(status.RestartRequiredPods).To(Equal(int32(1)))
+		Expect(status.OutdatedPods).To(Equal(int32(1)))
+		Expect(resizesTotal(resizeOutcomeRestartRequired)).To(Equal(restartRequired + 1))
+		Expect(recorder.Events).To(Receive(ContainSubstring("OvercommitResizeRequiresRestart")))
+	})
+
+	It("should only resize a memory restarting pod when its memory requests do not change", func() {
+		setup(class(&overcommit.InPlaceResizePolicy{Enabled: true, PodsPerMinute: 10}),
+			mutated("web", corev1.ContainerResizePolicy{ResourceName: corev1.ResourceMemory, RestartPolicy: corev1.RestartContainer}))
+
+		reconcile()
+
+		Expect(resizes).To(Equal(1))
+		Expect(getStatus().RestartRequiredPods).To(BeZero())
+	})
+
+	It("should not resize the pods that are not running", func() {
+		pending := mutated("web")
+		pending.Status.Phase = corev1.PodPending
+		setup(class(&overcommit.InPlaceResizePolicy{Enabled: true, PodsPerMinute: 10}), pending)
+
+		reconcile()
+
+		Expect(resizes).To(BeZero())
+		Expect(getStatus().OutdatedPods).To(Equal(int32(1)))
+	})
+
+	It("should limit the pods resized per minute", func() {
+		setup(class(&overcommit.InPlaceResizePolicy{Enabled: true, PodsPerMinute: 1}), mutated("api"), mutated("web"))
+
+		result := reconcile()
+
+		Expect(resizes).To(Equal(1))
+		Expect(getPod("api").Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(250)))
+		Expect(getPod("web").Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(500)))
+		Expect(getStatus().OutdatedPods).To(Equal(int32(1)))
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+	})
+})
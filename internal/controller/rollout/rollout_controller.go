@@ -0,0 +1,443 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// rolloutResyncPeriod is how often the pods of a class are checked for outdated overcommit values.
+	rolloutResyncPeriod = time.Minute
+	// restartedAtAnnotation is set on the pod template of a workload to restart it.
+	restartedAtAnnotation = "overcommit.inditex.dev/restartedAt"
+	// restartedForAnnotation records on a workload the class and generation it was restarted for, so it
+	// is never restarted twice for the same change of its class.
+	restartedForAnnotation = "overcommit.inditex.dev/restarted-for"
+)
+
+// workloadKinds are the workloads restarted by the rollout.
+var workloadKinds = map[string]schema.GroupVersionKind{
+	"Deployment":  {Group: "apps", Version: "v1", Kind: "Deployment"},
+	"StatefulSet": {Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	"DaemonSet":   {Group: "apps", Version: "v1", Kind: "DaemonSet"},
+}
+
+var replicaSetKind = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}
+
+// RolloutReconciler restarts the workloads whose pods run with outdated overcommit values after a change
+// of their OvercommitClass, when opted in by the class or by the workload.
+type RolloutReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// APIReader reads the status of the restarted workloads, which are not cached.
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=overcommit.inditex.dev,resources=overcommitclasses/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&overcommit.OvercommitClass{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Resuming the Overcommit resumes the restarts of every class
+		Watches(&overcommit.Overcommit{}, handler.EnqueueRequestsFromMapFunc(r.requestsForOvercommit),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("OvercommitClassRollout").
+		Complete(r)
+}
+
+func (r *RolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	overcommitClass := &overcommit.OvercommitClass{}
+	if err := r.Get(ctx, req.NamespacedName, overcommitClass); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !overcommitClass.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	overcommitResource, err := utils.GetOvercommit(ctx, r.Client)
+	if err != nil {
+		logger.Error(err, "Failed to get Overcommit")
+		return ctrl.Result{}, err
+	}
+	// Restarting the workloads would not update their requests
+	if overcommitResource.Spec.Paused || overcommitClass.Spec.Suspended {
+		logger.Info("Overcommit is paused or the class is suspended, skipping the rollout", "class", overcommitClass.Name)
+		return ctrl.Result{}, nil
+	}
+	// The pods of the class are resized in place instead
+	if overcommitClass.InPlaceResizeEnabled() {
+		logger.Info("The pods of the class are resized in place, skipping the rollout", "class", overcommitClass.Name)
+		return ctrl.Result{}, nil
+	}
+
+	outdated, err := r.outdatedWorkloads(ctx, overcommitClass)
+	if err != nil {
+		logger.Error(err, "Failed to find the workloads with outdated overcommit values")
+		return ctrl.Result{}, err
+	}
+
+	status := &overcommit.RolloutStatus{}
+	if overcommitClass.Status.Rollout != nil {
+		status = overcommitClass.Status.Rollout.DeepCopy()
+	}
+	if status.ObservedGeneration != overcommitClass.Generation {
+		status.ObservedGeneration = overcommitClass.Generation
+		status.RestartedWorkloads = 0
+	}
+	status.OutdatedWorkloads = int32(len(outdated))
+
+	// Free the slots of the restarts completed since the last reconciliation
+	restarting := []overcommit.WorkloadReference{}
+	for _, ref := range status.Restarting {
+		complete, err := r.restartComplete(ctx, ref)
+		if err != nil {
+			logger.Error(err, "Failed to check the restart of the workload", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name)
+			return ctrl.Result{}, err
+		}
+		if !complete {
+			restarting = append(restarting, ref)
+		}
+	}
+	status.Restarting = restarting
+
+	candidates, err := r.restartCandidates(ctx, overcommitClass, outdated, restarting)
+	if err != nil {
+		logger.Error(err, "Failed to select the workloads to restart")
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter := rolloutResyncPeriod
+	policy := overcommitClass.Spec.Rollout
+	if policy == nil {
+		policy = &overcommit.RolloutPolicy{}
+	}
+	now := time.Now()
+	if len(candidates) > 0 {
+		open, next := inWindow(policy.MaintenanceWindow, now)
+		if open {
+			slots := int(max(policy.MaxConcurrent, 1)) - len(status.Restarting)
+			for _, ref := range candidates[:max(min(slots, len(candidates)), 0)] {
+				if err := r.restart(ctx, ref, overcommitClass, now); err != nil {
+					logger.Error(err, "Failed to restart the workload", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name)
+					return ctrl.Result{}, err
+				}
+				logger.Info("Restarted workload with outdated overcommit values", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name, "class", overcommitClass.Name)
+				status.Restarting = append(status.Restarting, ref)
+				status.RestartedWorkloads++
+				status.LastRestartTime = &metav1.Time{Time: now}
+			}
+		} else {
+			logger.Info("Outside the maintenance window, postponing the rollout", "class", overcommitClass.Name, "next", next)
+			requeueAfter = min(requeueAfter, next.Sub(now))
+		}
+	}
+
+	if equality.Semantic.DeepEqual(overcommitClass.Status.Rollout, status) {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	patch := client.MergeFrom(overcommitClass.DeepCopy())
+	overcommitClass.Status.Rollout = status
+	if err := r.Status().Patch(ctx, overcommitClass, patch); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to update the rollout status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// outdatedWorkloads returns the workloads with pods mutated by the class with other ratios than its current ones.
+func (r *RolloutReconciler) outdatedWorkloads(ctx context.Context, overcommitClass *overcommit.OvercommitClass) ([]overcommit.WorkloadReference, error) {
+	pods := &metav1.PartialObjectMetadataList{}
+	pods.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "PodList"})
+	if err := r.List(ctx, pods); err != nil {
+		return nil, err
+	}
+
+	found := map[overcommit.WorkloadReference]bool{}
+	// The Deployment of each ReplicaSet, resolved once per reconciliation
+	deployments := map[types.NamespacedName]string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !pod.DeletionTimestamp.IsZero() || !outdatedPod(pod, overcommitClass) {
+			continue
+		}
+		ref, ok, err := r.podWorkload(ctx, pod, deployments)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			found[ref] = true
+		}
+	}
+
+	workloads := make([]overcommit.WorkloadReference, 0, len(found))
+	for ref := range found {
+		workloads = append(workloads, ref)
+	}
+	sortWorkloads(workloads)
+	return workloads, nil
+}
+
+// outdatedPod reports whether the pod was mutated by the class with other ratios than its current ones.
+func outdatedPod(pod client.Object, overcommitClass *overcommit.OvercommitClass) bool {
+	annotations := pod.GetAnnotations()
+	if annotations[overcommit.AppliedAnnotation] != overcommitClass.Name {
+		return false
+	}
+	return utils.OvercommitOutdated(annotations, &overcommitClass.Spec)
+}
+
+// podWorkload returns the Deployment, StatefulSet or DaemonSet running the pod, if any.
+func (r *RolloutReconciler) podWorkload(ctx context.Context, pod *metav1.PartialObjectMetadata, deployments map[types.NamespacedName]string) (overcommit.WorkloadReference, bool, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return overcommit.WorkloadReference{}, false, nil
+	}
+	switch owner.Kind {
+	case "StatefulSet", "DaemonSet":
+		return overcommit.WorkloadReference{Kind: owner.Kind, Namespace: pod.Namespace, Name: owner.Name}, true, nil
+	case "ReplicaSet":
+		key := types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}
+		deployment, resolved := deployments[key]
+		if !resolved {
+			replicaSet := &metav1.PartialObjectMetadata{}
+			replicaSet.SetGroupVersionKind(replicaSetKind)
+			if err := r.Get(ctx, key, replicaSet); err != nil {
+				if apierrors.IsNotFound(err) {
+					return overcommit.WorkloadReference{}, false, nil
+				}
+				return overcommit.WorkloadReference{}, false, err
+			}
+			if rsOwner := metav1.GetControllerOf(replicaSet); rsOwner != nil && rsOwner.Kind == "Deployment" {
+				deployment = rsOwner.Name
+			}
+			deployments[key] = deployment
+		}
+		if deployment == "" {
+			return overcommit.WorkloadReference{}, false, nil
+		}
+		return overcommit.WorkloadReference{Kind: "Deployment", Namespace: pod.Namespace, Name: deployment}, true, nil
+	default:
+		return overcommit.WorkloadReference{}, false, nil
+	}
+}
+
+// restartCandidates returns the outdated workloads opted in and not restarted yet for the generation of the class.
+func (r *RolloutReconciler) restartCandidates(ctx context.Context, overcommitClass *overcommit.OvercommitClass, outdated, restarting []overcommit.WorkloadReference) ([]overcommit.WorkloadReference, error) {
+	inProgress := map[overcommit.WorkloadReference]bool{}
+	for _, ref := range restarting {
+		inProgress[ref] = true
+	}
+	restartedFor := restartedForValue(overcommitClass)
+
+	candidates := []overcommit.WorkloadReference{}
+	for _, ref := range outdated {
+		if inProgress[ref] {
+			continue
+		}
+		workload := &metav1.PartialObjectMetadata{}
+		workload.SetGroupVersionKind(workloadKinds[ref.Kind])
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, workload); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		annotations := workload.GetAnnotations()
+		if annotations[restartedForAnnotation] == restartedFor || !optedIn(overcommitClass.Spec.Rollout, annotations) {
+			continue
+		}
+		candidates = append(candidates, ref)
+	}
+	return candidates, nil
+}
+
+// optedIn reports whether a workload is restarted, its annotation taking precedence over the policy of its class.
+func optedIn(policy *overcommit.RolloutPolicy, annotations map[string]string) bool {
+	switch annotations[overcommit.RolloutWorkloadAnnotation] {
+	case "true":
+		return true
+	case "false":
+		return false
+	default:
+		return policy != nil && policy.Enabled
+	}
+}
+
+// restart triggers a rolling restart of the workload, the same way as kubectl rollout restart.
+func (r *RolloutReconciler) restart(ctx context.Context, ref overcommit.WorkloadReference, overcommitClass *overcommit.OvercommitClass, now time.Time) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{restartedForAnnotation: restartedForValue(overcommitClass)},
+		},
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{restartedAtAnnotation: now.UTC().Format(time.RFC3339)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(workloadKinds[ref.Kind])
+	workload.SetNamespace(ref.Namespace)
+	workload.SetName(ref.Name)
+	return r.Patch(ctx, workload, client.RawPatch(types.MergePatchType, patch))
+}
+
+// restartComplete reports whether the restart of the workload rolled out, a deleted workload being done.
+func (r *RolloutReconciler) restartComplete(ctx context.Context, ref overcommit.WorkloadReference) (bool, error) {
+	gvk, ok := workloadKinds[ref.Kind]
+	if !ok {
+		return true, nil
+	}
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(gvk)
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, workload); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return rolloutComplete(workload), nil
+}
+
+// rolloutComplete reports whether every pod of the workload runs its current template, like kubectl rollout status.
+func rolloutComplete(workload *unstructured.Unstructured) bool {
+	status := func(field string) int64 {
+		value, _, _ := unstructured.NestedInt64(workload.Object, "status", field)
+		return value
+	}
+	if status("observedGeneration") < workload.GetGeneration() {
+		return false
+	}
+	switch workload.GetKind() {
+	case "Deployment", "StatefulSet":
+		replicas, found, _ := unstructured.NestedInt64(workload.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		if status("updatedReplicas") < replicas {
+			return false
+		}
+		if workload.GetKind() == "StatefulSet" {
+			current, _, _ := unstructured.NestedString(workload.Object, "status", "currentRevision")
+			update, _, _ := unstructured.NestedString(workload.Object, "status", "updateRevision")
+			return current == update
+		}
+		// The pods of the old ReplicaSets are gone
+		return status("replicas") <= status("updatedReplicas") && status("availableReplicas") >= status("updatedReplicas")
+	case "DaemonSet":
+		return status("updatedNumberScheduled") >= status("desiredNumberScheduled") && status("numberUnavailable") == 0
+	default:
+		return true
+	}
+}
+
+// inWindow reports whether now is inside the maintenance window, and otherwise when it opens next.
+// There is no window restricting the restarts when it is nil.
+func inWindow(window *overcommit.MaintenanceWindow, now time.Time) (bool, time.Time) {
+	if window == nil {
+		return true, now
+	}
+	start, errStart := time.Parse("15:04", window.Start)
+	end, errEnd := time.Parse("15:04", window.End)
+	if errStart != nil || errEnd != nil {
+		// Rejected by the validation of the CRD, never restart on a window that cannot be understood
+		return false, now.Add(rolloutResyncPeriod)
+	}
+
+	now = now.UTC()
+	minutes := func(t time.Time) int { return t.Hour()*60 + t.Minute() }
+	current, from, to := minutes(now), minutes(start), minutes(end)
+	var open bool
+	if from <= to {
+		open = current >= from && current < to
+	} else {
+		// The window spans midnight
+		open = current >= from || current < to
+	}
+	if open {
+		return true, now
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return false, next
+}
+
+// restartedForValue identifies the change of the class the workloads are restarted for.
+func restartedForValue(overcommitClass *overcommit.OvercommitClass) string {
+	return fmt.Sprintf("%s/%d", overcommitClass.Name, overcommitClass.Generation)
+}
+
+// sortWorkloads orders the workloads by namespace, kind and name, so they are restarted in a stable order.
+func sortWorkloads(workloads []overcommit.WorkloadReference) {
+	sort.Slice(workloads, func(i, j int) bool {
+		a, b := workloads[i], workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
+
+// requestsForOvercommit enqueues every OvercommitClass when the Overcommit changes.
+func (r *RolloutReconciler) requestsForOvercommit(ctx context.Context, _ client.Object) []reconcile.Request {
+	overcommitClasses := &overcommit.OvercommitClassList{}
+	if err := r.List(ctx, overcommitClasses); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list OvercommitClasses")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(overcommitClasses.Items))
+	for _, overcommitClass := range overcommitClasses.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&overcommitClass)})
+	}
+	return requests
+}
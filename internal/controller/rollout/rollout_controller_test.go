@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/controller/controllertest"
+)
+
+var _ = Describe("Rollout", func() {
+	Context("Maintenance window", func() {
+		day := func(hour, minute int) time.Time {
+			return time.Date(2025, 6, 10, hour, minute, 0, 0, time.UTC)
+		}
+
+		It("should always be open without a window", func() {
+			open, _ := inWindow(nil, day(12, 0))
+			Expect(open).To(BeTrue())
+		})
+
+		It("should open between start and end", func() {
+			window := &overcommit.MaintenanceWindow{Start: "02:00", End: "04:00"}
+			open, _ := inWindow(window, day(3, 30))
+			Expect(open).To(BeTrue())
+			open, next := inWindow(window, day(4, 0))
+			Expect(open).To(BeFalse())
+			Expect(next).To(Equal(day(2, 0).AddDate(0, 0, 1)))
+			open, next = inWindow(window, day(1, 0))
+			Expect(open).To(BeFalse())
+			Expect(next).To(Equal(day(2, 0)))
+		})
+
+		It("should span midnight when ending before it starts", func() {
+			window := &overcommit.MaintenanceWindow{Start: "22:00", End: "02:00"}
+			open, _ := inWindow(window, day(23, 0))
+			Expect(open).To(BeTrue())
+			open, _ = inWindow(window, day(1, 59))
+			Expect(open).To(BeTrue())
+			open, next := inWindow(window, day(12, 0))
+			Expect(open).To(BeFalse())
+			Expect(next).To(Equal(day(22, 0)))
+		})
+	})
+
+	Context("Outdated pods", func() {
+		overcommitClass := controllertest.Class("high", 0.5, 0.8)
+
+		It("should only flag the pods of the class mutated with other ratios", func() {
+			pod := func(class, cpu, memory string) *corev1.Pod {
+				return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: controllertest.Mutated(class, cpu, memory)}}
+			}
+			Expect(outdatedPod(pod("high", "0.5000", "0.8000"), overcommitClass)).To(BeFalse())
+			Expect(outdatedPod(pod("high", "0.4000", "0.8000"), overcommitClass)).To(BeTrue())
+			Expect(outdatedPod(pod("high", "0.5000", "0.9000"), overcommitClass)).To(BeTrue())
+			Expect(outdatedPod(pod("low", "0.4000", "0.9000"), overcommitClass)).To(BeFalse())
+		})
+
+		It("should let the workload annotation override the policy of the class", func() {
+			enabled := &overcommit.RolloutPolicy{Enabled: true}
+			Expect(optedIn(nil, nil)).To(BeFalse())
+			Expect(optedIn(enabled, nil)).To(BeTrue())
+			Expect(optedIn(enabled, map[string]string{overcommit.RolloutWorkloadAnnotation: "false"})).To(BeFalse())
+			Expect(optedIn(nil, map[string]string{overcommit.RolloutWorkloadAnnotation: "true"})).To(BeTrue())
+		})
+	})
+
+	Context("Rollout status", func() {
+		toUnstructured := func(obj runtime.Object) *unstructured.Unstructured {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			Expect(err).NotTo(HaveOccurred())
+			workload := &unstructured.Unstructured{Object: content}
+			return workload
+		}
+
+		It("should wait for every replica of a Deployment to be updated", func() {
+			deployment := &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 2, AvailableReplicas: 3},
+			}
+			Expect(rolloutComplete(toUnstructured(deployment))).To(BeFalse())
+			deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}
+			Expect(rolloutComplete(toUnstructured(deployment))).To(BeTrue())
+			deployment.Generation = 3
+			Expect(rolloutComplete(toUnstructured(deployment))).To(BeFalse())
+		})
+
+		It("should wait for a StatefulSet to reach its update revision", func() {
+			statefulSet := &appsv1.StatefulSet{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+				Spec:     appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+				Status:   appsv1.StatefulSetStatus{UpdatedReplicas: 2, CurrentRevision: "a", UpdateRevision: "b"},
+			}
+			Expect(rolloutComplete(toUnstructured(statefulSet))).To(BeFalse())
+			statefulSet.Status.CurrentRevision = "b"
+			Expect(rolloutComplete(toUnstructured(statefulSet))).To(BeTrue())
+		})
+
+		It("should wait for a DaemonSet to be updated on every node", func() {
+			daemonSet := &appsv1.DaemonSet{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+				Status:   appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 2},
+			}
+			Expect(rolloutComplete(toUnstructured(daemonSet))).To(BeFalse())
+			daemonSet.Status.UpdatedNumberScheduled = 3
+			Expect(rolloutComplete(toUnstructured(daemonSet))).To(BeTrue())
+		})
+	})
+
+	Context("Reconcile", func() {
+		var (
+			k8sClient client.Client
+			r         *RolloutReconciler
+		)
+
+		outdatedPod := func(name string, owner metav1.OwnerReference) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "apps",
+				OwnerReferences: []metav1.OwnerReference{owner},
+				Annotations:     controllertest.Mutated("high", "0.4000", "0.8000"),
+			}}
+		}
+		controllerRef := func(kind, name string) metav1.OwnerReference {
+			return metav1.OwnerReference{APIVersion: "apps/v1", Kind: kind, Name: name, UID: types.UID("uid-" + name), Controller: ptr.To(true)}
+		}
+
+		BeforeEach(func() {
+			overcommitClass := controllertest.Class("high", 0.5, 0.8)
+			overcommitClass.Generation = 2
+			overcommitClass.Spec.Rollout = &overcommit.RolloutPolicy{Enabled: true, MaxConcurrent: 1}
+			replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name: "web-7d4b9", Namespace: "apps", OwnerReferences: []metav1.OwnerReference{controllerRef("Deployment", "web")},
+			}}
+			k8sClient = controllertest.NewClientBuilder().
+				WithStatusSubresource(&overcommit.OvercommitClass{}).
+				WithObjects(
+					&overcommit.Overcommit{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+					overcommitClass,
+					replicaSet,
+					&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps"}},
+					&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"}},
+					&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "apps",
+						Annotations: map[string]string{overcommit.RolloutWorkloadAnnotation: "false"}}},
+					outdatedPod("web-7d4b9-a", controllerRef("ReplicaSet", "web-7d4b9")),
+					outdatedPod("web-7d4b9-b", controllerRef("ReplicaSet", "web-7d4b9")),
+					outdatedPod("db-0", controllerRef("StatefulSet", "db")),
+					outdatedPod("agent-x", controllerRef("DaemonSet", "agent")),
+				).
+				Build()
+			r = &RolloutReconciler{Client: k8sClient, Scheme: controllertest.Scheme(), APIReader: k8sClient}
+		})
+
+		It("should restart the opted in workloads one at a time", func() {
+			request := ctrl.Request{NamespacedName: client.ObjectKey{Name: "high"}}
+			_, err := r.Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+
+			overcommitClass := &overcommit.OvercommitClass{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "high"}, overcommitClass)).To(Succeed())
+			status := overcommitClass.Status.Rollout
+			Expect(status).NotTo(BeNil())
+			Expect(status.ObservedGeneration).To(Equal(int64(2)))
+			Expect(status.OutdatedWorkloads).To(Equal(int32(3)))
+			Expect(status.RestartedWorkloads).To(Equal(int32(1)))
+			Expect(status.Restarting).To(Equal([]overcommit.WorkloadReference{{Kind: "Deployment", Namespace: "apps", Name: "web"}}))
+
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "web"}, deployment)).To(Succeed())
+			Expect(deployment.Annotations).To(HaveKeyWithValue(restartedForAnnotation, "high/2"))
+			Expect(deployment.Spec.Template.Annotations).To(HaveKey(restartedAtAnnotation))
+
+			// The StatefulSet waits for the Deployment to roll out, the opted out DaemonSet is never restarted
+			statefulSet := &appsv1.StatefulSet{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "db"}, statefulSet)).To(Succeed())
+			Expect(statefulSet.Spec.Template.Annotations).NotTo(HaveKey(restartedAtAnnotation))
+
+			// Once the Deployment rolled out, the StatefulSet is restarted
+			deployment.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1, ObservedGeneration: deployment.Generation}
+			Expect(k8sClient.Status().Update(context.Background(), deployment)).To(Succeed())
+			_, err = r.Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "db"}, statefulSet)).To(Succeed())
+			Expect(statefulSet.Spec.Template.Annotations).To(HaveKey(restartedAtAnnotation))
+			daemonSet := &appsv1.DaemonSet{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "agent"}, daemonSet)).To(Succeed())
+			Expect(daemonSet.Spec.Template.Annotations).NotTo(HaveKey(restartedAtAnnotation))
+
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "high"}, overcommitClass)).To(Succeed())
+			Expect(overcommitClass.Status.Rollout.RestartedWorkloads).To(Equal(int32(2)))
+			Expect(overcommitClass.Status.Rollout.Restarting).To(Equal([]overcommit.WorkloadReference{{Kind: "StatefulSet", Namespace: "apps", Name: "db"}}))
+		})
+
+		It("should not restart anything while the class is suspended", func() {
+			overcommitClass := &overcommit.OvercommitClass{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "high"}, overcommitClass)).To(Succeed())
+			overcommitClass.Spec.Suspended = true
+			Expect(k8sClient.Update(context.Background(), overcommitClass)).To(Succeed())
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Name: "high"}})
+			Expect(err).NotTo(HaveOccurred())
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "apps", Name: "web"}, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Annotations).NotTo(HaveKey(restartedAtAnnotation))
+		})
+	})
+})
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package exclusions turns the namespaces excluded by an OvercommitClass into the match conditions and
+// namespace selector of its mutating webhook, and evaluates them the same way on the operator side.
+package exclusions
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	plugincel "k8s.io/apiserver/pkg/admission/plugin/cel"
+	"k8s.io/apiserver/pkg/admission/plugin/webhook/matchconditions"
+	"k8s.io/apiserver/pkg/cel/environment"
+)
+
+// namespaceField is the namespace of the admitted object in the CEL expressions.
+const namespaceField = "object.metadata.namespace"
+
+// Exclusions are the namespaces whose pods are left untouched by a class.
+type Exclusions struct {
+	// Regex is the legacy excludedNamespaces regex, ignored when empty.
+	Regex string
+	// Names are the exact names of the excluded namespaces.
+	Names []string
+	// Globs are patterns matched against the whole namespace name, where '*' matches any sequence of
+	// characters and '?' a single character.
+	Globs []string
+	// NamespaceSelector restricts the class to the namespaces whose labels match it, the others are excluded.
+	NamespaceSelector *metav1.LabelSelector
+}
+
+// ForClass returns every namespace exclusion of the class.
+func ForClass(class *overcommit.OvercommitClass) Exclusions {
+	result := Exclusions{Regex: class.Spec.ExcludedNamespaces}
+	if class.Spec.NamespaceExclusions != nil {
+		result.Names = class.Spec.NamespaceExclusions.Names
+		result.Globs = class.Spec.NamespaceExclusions.Globs
+		result.NamespaceSelector = class.Spec.NamespaceExclusions.NamespaceSelector
+	}
+	return result
+}
+
+// compiler compiles the match conditions with the CEL environment of the API server.
+var compiler = plugincel.NewCompiler(environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion()))
+
+// MatchConditions returns the match conditions keeping the webhook from being called for the excluded namespaces.
+// Every value is quoted as a CEL string literal, so it can never change the meaning of the expression.
+func (e Exclusions) MatchConditions() []admissionv1.MatchCondition {
+	matchConditions := []admissionv1.MatchCondition{}
+	if e.Regex != "" {
+		matchConditions = append(matchConditions, admissionv1.MatchCondition{
+			Name:       "exclude-namespaces",
+			Expression: "!" + namespaceField + ".matches(" + Quote(e.Regex) + ")",
+		})
+	}
+	if len(e.Names) > 0 {
+		quoted := make([]string, 0, len(e.Names))
+		for _, name := range e.Names {
+			quoted = append(quoted, Quote(name))
+		}
+		matchConditions = append(matchConditions, admissionv1.MatchCondition{
+			Name:       "exclude-namespace-names",
+			Expression: "!(" + namespaceField + " in [" + strings.Join(quoted, ", ") + "])",
+		})
+	}
+	if len(e.Globs) > 0 {
+		matches := make([]string, 0, len(e.Globs))
+		for _, glob := range e.Globs {
+			matches = append(matches, "!"+namespaceField+".matches("+Quote(globToRegex(glob))+")")
+		}
+		matchConditions = append(matchConditions, admissionv1.MatchCondition{
+			Name:       "exclude-namespace-globs",
+			Expression: strings.Join(matches, " && "),
+		})
+	}
+	return matchConditions
+}
+
+// Validate checks that the exclusions are well formed and that their match conditions compile
+// in the CEL environment used by the API server for webhook match conditions.
+func (e Exclusions) Validate() error {
+	var errs []error
+	if _, err := regexp.Compile(e.Regex); err != nil {
+		errs = append(errs, fmt.Errorf("invalid regex for excludedNamespaces: %w", err))
+	}
+	if e.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(e.NamespaceSelector); err != nil {
+			errs = append(errs, fmt.Errorf("invalid namespaceSelector: %w", err))
+		}
+	}
+	for _, matchCondition := range e.MatchConditions() {
+		condition := matchconditions.MatchCondition(matchCondition)
+		result := compiler.CompileCELExpression(&condition, plugincel.OptionalVariableDeclarations{HasAuthorizer: true}, environment.StoredExpressions)
+		if result.Error != nil {
+			errs = append(errs, fmt.Errorf("invalid match condition %s: %w", matchCondition.Name, result.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Excludes reports whether the pods of the namespace are left untouched.
+func (e Exclusions) Excludes(namespace *corev1.Namespace) (bool, error) {
+	if slices.Contains(e.Names, namespace.Name) {
+		return true, nil
+	}
+	for _, glob := range e.Globs {
+		if regexp.MustCompile(globToRegex(glob)).MatchString(namespace.Name) {
+			return true, nil
+		}
+	}
+	if e.Regex != "" {
+		excluded, err := regexp.MatchString(e.Regex, namespace.Name)
+		if err != nil || excluded {
+			return excluded, err
+		}
+	}
+	if e.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(e.NamespaceSelector)
+		if err != nil {
+			return false, err
+		}
+		labels := namespace.Labels
+		if labels == nil {
+			// Namespaces are always labelled with their name by the API server
+			labels = map[string]string{corev1.LabelMetadataName: namespace.Name}
+		}
+		return !selector.Matches(k8slabels.Set(labels)), nil
+	}
+	return false, nil
+}
+
+// Quote returns s as a single quoted CEL string literal.
+func Quote(s string) string {
+	var builder strings.Builder
+	builder.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\\', '\'':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteByte('\'')
+	return builder.String()
+}
+
+// globToRegex translates a glob into an anchored regex, every character but the wildcards matching itself.
+func globToRegex(glob string) string {
+	var builder strings.Builder
+	builder.WriteByte('^')
+	for _, r := range glob {
+		switch r {
+		case '*':
+			builder.WriteString(".*")
+		case '?':
+			builder.WriteByte('.')
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	builder.WriteByte('$')
+	return builder.String()
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package exclusions
+
+import (
+	"testing"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMatchConditionsQuoteValues(t *testing.T) {
+	e := Exclusions{
+		Regex: `^kube-.*|it's\d`,
+		Names: []string{"default"},
+		Globs: []string{"openshift-*"},
+	}
+
+	conditions := e.MatchConditions()
+	if len(conditions) != 3 {
+		t.Fatalf("Expected 3 match conditions, got '%v'", conditions)
+	}
+	if conditions[0].Expression != `!object.metadata.namespace.matches('^kube-.*|it\'s\\d')` {
+		t.Errorf("Expected the regex to be quoted, got '%s'", conditions[0].Expression)
+	}
+	if conditions[1].Expression != `!(object.metadata.namespace in ['default'])` {
+		t.Errorf("Expected the names to be listed, got '%s'", conditions[1].Expression)
+	}
+	if conditions[2].Expression != `!object.metadata.namespace.matches('^openshift-.*$')` {
+		t.Errorf("Expected the glob to be anchored, got '%s'", conditions[2].Expression)
+	}
+	if err := e.Validate(); err != nil {
+		t.Errorf("Expected the match conditions to compile, got error '%v'", err)
+	}
+}
+
+func TestMatchConditionsEmpty(t *testing.T) {
+	if conditions := (Exclusions{}).MatchConditions(); len(conditions) != 0 {
+		t.Errorf("Expected no match conditions, got '%v'", conditions)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := []Exclusions{
+		{Regex: "(unclosed"},
+		{NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}}}},
+	}
+	for _, e := range invalid {
+		if err := e.Validate(); err == nil {
+			t.Errorf("Expected exclusions '%+v' to be invalid", e)
+		}
+	}
+}
+
+func TestExcludes(t *testing.T) {
+	e := Exclusions{
+		Regex:             "^kube-",
+		Names:             []string{"default"},
+		Globs:             []string{"openshift-*"},
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"overcommit": "enabled"}},
+	}
+
+	cases := map[string]struct {
+		labels   map[string]string
+		excluded bool
+	}{
+		"kube-system":       {excluded: true},
+		"default":           {labels: map[string]string{"overcommit": "enabled"}, excluded: true},
+		"openshift-console": {labels: map[string]string{"overcommit": "enabled"}, excluded: true},
+		"team-a":            {labels: map[string]string{"overcommit": "enabled"}, excluded: false},
+		"team-b":            {labels: map[string]string{"overcommit": "disabled"}, excluded: true},
+	}
+	for name, c := range cases {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: c.labels}}
+		excluded, err := e.Excludes(namespace)
+		if err != nil {
+			t.Fatalf("Expected no error for namespace %s, got '%v'", name, err)
+		}
+		if excluded != c.excluded {
+			t.Errorf("Expected namespace %s excluded to be '%v', got '%v'", name, c.excluded, excluded)
+		}
+	}
+}
+
+func TestForClass(t *testing.T) {
+	class := &overcommit.OvercommitClass{Spec: overcommit.OvercommitClassSpec{ExcludedNamespaces: "^kube-"}}
+	if e := ForClass(class); e.Regex != "^kube-" || e.Names != nil || e.NamespaceSelector != nil {
+		t.Errorf("Expected only the regex exclusion, got '%v'", e)
+	}
+
+	class.Spec.NamespaceExclusions = &overcommit.NamespaceExclusions{
+		Names: []string{"default"},
+		Globs: []string{"openshift-*"},
+	}
+	e := ForClass(class)
+	if e.Regex != "^kube-" || len(e.Names) != 1 || len(e.Globs) != 1 {
+		t.Errorf("Expected the regex and the structured exclusions, got '%v'", e)
+	}
+}
@@ -53,6 +53,19 @@ var (
 		},
 		[]string{"name", "cpu", "memory", "isDefault"},
 	)
+	K8sOvercommitOperatorPaused = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "k8s_overcommit_operator_paused",
+			Help: "Whether the overcommit mutation is paused cluster-wide (1) or not (0)",
+		},
+	)
+	K8sOvercommitOperatorClassSuspended = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "k8s_overcommit_operator_class_suspended",
+			Help: "Whether the overcommit mutation is suspended for the class (1) or not (0)",
+		},
+		[]string{"name"},
+	)
 	K8sOvercommitPodMutated = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "k8s_overcommit_operator_pod_mutated",
@@ -60,6 +73,78 @@ var (
 		},
 		[]string{"class", "kind", "name", "namespace"},
 	)
+	K8sOvercommitOperatorMutationDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "k8s_overcommit_operator_mutation_duration_seconds",
+			Help:    "End-to-end latency of the overcommit mutation of a pod",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"class", "outcome"},
+	)
+	K8sOvercommitOperatorLookupDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "k8s_overcommit_operator_lookup_duration_seconds",
+			Help:    "Latency of the lookups done to resolve the overcommit of a pod",
+			Buckets: []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		[]string{"lookup", "class", "outcome"},
+	)
+	K8sOvercommitOperatorResolutionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "k8s_overcommit_operator_resolutions_total",
+			Help: "Total number of pods resolved by each resolution path",
+		},
+		[]string{"class", "path"},
+	)
+	K8sOvercommitOperatorResolutionErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "k8s_overcommit_operator_resolution_errors_total",
+			Help: "Total number of errors while resolving the overcommit of a pod",
+		},
+		[]string{"reason"},
+	)
+	K8sOvercommitOperatorPodsDrift = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "k8s_overcommit_operator_pods_drift",
+			Help: "Number of pods by how their overcommit reflects the current values of their class",
+		},
+		[]string{"class", "namespace", "state"},
+	)
+	K8sOvercommitOperatorPodResizesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "k8s_overcommit_operator_pod_resizes_total",
+			Help: "Total number of in-place resizes of running pods after a change of their class",
+		},
+		[]string{"class", "outcome"},
+	)
+	K8sOvercommitOperatorNodeRequestsRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "k8s_overcommit_operator_node_requests_ratio",
+			Help: "Requests of the pods scheduled on a node against its allocatable resources, by the class that mutated them",
+		},
+		[]string{"node", "class", "resource"},
+	)
+	K8sOvercommitOperatorNodeLimitsRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "k8s_overcommit_operator_node_limits_ratio",
+			Help: "Limits of the pods scheduled on a node against its allocatable resources, by the class that mutated them",
+		},
+		[]string{"node", "class", "resource"},
+	)
+	K8sOvercommitOperatorPodsRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "k8s_overcommit_operator_pods_requests",
+			Help: "Total requests of the running pods mutated by a class, in cores or bytes",
+		},
+		[]string{"class", "namespace", "resource"},
+	)
+	K8sOvercommitOperatorPodsLimits = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "k8s_overcommit_operator_pods_limits",
+			Help: "Total limits of the running pods mutated by a class, in cores or bytes",
+		},
+		[]string{"class", "namespace", "resource"},
+	)
 )
 
 func init() {
@@ -70,4 +155,16 @@ func init() {
 	metrics.Registry.MustRegister(K8sOvercommitOperatorVersion)
 	metrics.Registry.MustRegister(K8sOvercommitOperatorClass)
 	metrics.Registry.MustRegister(K8sOvercommitPodMutated)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorPaused)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorClassSuspended)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorMutationDurationSeconds)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorLookupDurationSeconds)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorResolutionsTotal)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorResolutionErrorsTotal)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorPodsDrift)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorPodResizesTotal)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorNodeRequestsRatio)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorNodeLimitsRatio)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorPodsRequests)
+	metrics.Registry.MustRegister(K8sOvercommitOperatorPodsLimits)
 }
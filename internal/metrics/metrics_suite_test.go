@@ -42,6 +42,51 @@ func (suite *MetricsTestSuite) TestK8sOvercommitOperatorPodsNotMutatedTotal() {
 	assert.Equal(suite.T(), 1.0, count)
 }
 
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorResolutionsTotal() {
+	K8sOvercommitOperatorResolutionsTotal.WithLabelValues("test", "pod_label").Inc()
+	count := testutil.ToFloat64(K8sOvercommitOperatorResolutionsTotal.WithLabelValues("test", "pod_label"))
+	assert.Equal(suite.T(), 1.0, count)
+}
+
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorResolutionErrorsTotal() {
+	K8sOvercommitOperatorResolutionErrorsTotal.WithLabelValues("test-reason").Inc()
+	count := testutil.ToFloat64(K8sOvercommitOperatorResolutionErrorsTotal.WithLabelValues("test-reason"))
+	assert.Equal(suite.T(), 1.0, count)
+}
+
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorDurationHistograms() {
+	K8sOvercommitOperatorMutationDurationSeconds.WithLabelValues("test", "mutated").Observe(0.002)
+	K8sOvercommitOperatorLookupDurationSeconds.WithLabelValues("class", "test", "found").Observe(0.0003)
+	assert.Equal(suite.T(), 1, testutil.CollectAndCount(K8sOvercommitOperatorMutationDurationSeconds))
+	assert.Equal(suite.T(), 1, testutil.CollectAndCount(K8sOvercommitOperatorLookupDurationSeconds))
+}
+
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorPodsDrift() {
+	K8sOvercommitOperatorPodsDrift.WithLabelValues("test", "namespace", "outdated").Set(3)
+	count := testutil.ToFloat64(K8sOvercommitOperatorPodsDrift.WithLabelValues("test", "namespace", "outdated"))
+	assert.Equal(suite.T(), 3.0, count)
+}
+
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorPodResizesTotal() {
+	K8sOvercommitOperatorPodResizesTotal.WithLabelValues("test", "resized").Inc()
+	count := testutil.ToFloat64(K8sOvercommitOperatorPodResizesTotal.WithLabelValues("test", "resized"))
+	assert.Equal(suite.T(), 1.0, count)
+}
+
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorNodeRatios() {
+	K8sOvercommitOperatorNodeRequestsRatio.WithLabelValues("node-1", "test", "cpu").Set(0.5)
+	K8sOvercommitOperatorNodeLimitsRatio.WithLabelValues("node-1", "test", "cpu").Set(1.5)
+	assert.Equal(suite.T(), 0.5, testutil.ToFloat64(K8sOvercommitOperatorNodeRequestsRatio.WithLabelValues("node-1", "test", "cpu")))
+	assert.Equal(suite.T(), 1.5, testutil.ToFloat64(K8sOvercommitOperatorNodeLimitsRatio.WithLabelValues("node-1", "test", "cpu")))
+}
+
+func (suite *MetricsTestSuite) TestK8sOvercommitOperatorPodsCapacity() {
+	K8sOvercommitOperatorPodsRequests.WithLabelValues("test", "namespace", "memory").Set(1024)
+	K8sOvercommitOperatorPodsLimits.WithLabelValues("test", "namespace", "memory").Set(4096)
+	assert.Equal(suite.T(), 1024.0, testutil.ToFloat64(K8sOvercommitOperatorPodsRequests.WithLabelValues("test", "namespace", "memory")))
+	assert.Equal(suite.T(), 4096.0, testutil.ToFloat64(K8sOvercommitOperatorPodsLimits.WithLabelValues("test", "namespace", "memory")))
+}
+
 func TestMetricsTestSuite(t *testing.T) {
 	suite.Run(t, new(MetricsTestSuite))
 }
@@ -0,0 +1,272 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Explain describes how a pod was mutated when it was created, from the decision recorded on it, and how it
+// would be mutated if it was created again now.
+func (p *Plugin) Explain(ctx context.Context, namespace, name string) error {
+	pod := &corev1.Pod{}
+	if err := p.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, pod); err != nil {
+		return err
+	}
+	p.field("Pod", "%s/%s\n", pod.Namespace, pod.Name)
+
+	decision, err := overcommitpkg.GetDecision(pod)
+	if err != nil {
+		return fmt.Errorf("error reading the decision recorded on the pod: %w", err)
+	}
+	fmt.Fprintln(p.Out, "\nAt creation:")
+	if decision == nil {
+		p.field("Outcome", "not mutated, the pod has no recorded decision\n")
+	} else {
+		p.field("Webhook", "%s, pass %d\n", decision.Webhook, decision.Pass)
+		p.printDecision(decision)
+		if outdated, err := p.outdated(ctx, pod, decision.Class); err != nil {
+			return err
+		} else if outdated != "" {
+			p.field("Drift", "%s\n", outdated)
+		}
+	}
+
+	// The pod is admitted again with the requests it had before the mutation
+	original := pod.DeepCopy()
+	if err := overcommitpkg.Restore(original); err != nil {
+		return err
+	}
+	admission, err := p.Admit(ctx, original)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(p.Out, "\nIf created now:")
+	p.printAdmission(admission)
+	return nil
+}
+
+// outdated explains how the current ratios of the class differ from the ones recorded on the pod, empty when
+// they do not.
+func (p *Plugin) outdated(ctx context.Context, pod *corev1.Pod, className string) (string, error) {
+	overcommitClass := &overcommit.OvercommitClass{}
+	if err := p.Client.Get(ctx, client.ObjectKey{Name: className}, overcommitClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("OvercommitClass %s no longer exists", className), nil
+		}
+		return "", err
+	}
+	if !utils.OvercommitOutdated(pod.Annotations, &overcommitClass.Spec) {
+		return "", nil
+	}
+	return fmt.Sprintf("OvercommitClass %s now has cpu %.4f, memory %.4f", className,
+		overcommitClass.Spec.CpuOvercommit, overcommitClass.Spec.MemoryOvercommit), nil
+}
+
+// Classes lists the classes with their effective settings, the namespaces resolving to them and the pods they
+// mutated, along how many of those were mutated with other ratios than the current ones.
+func (p *Plugin) Classes(ctx context.Context) error {
+	overcommitResource, err := utils.GetOvercommit(ctx, p.Client)
+	if err != nil {
+		return err
+	}
+	overcommitClasses := &overcommit.OvercommitClassList{}
+	if err := p.Client.List(ctx, overcommitClasses); err != nil {
+		return err
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := p.Client.List(ctx, namespaces); err != nil {
+		return err
+	}
+	pods := &metav1.PartialObjectMetadataList{}
+	pods.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "PodList"})
+	if err := p.Client.List(ctx, pods); err != nil {
+		return err
+	}
+
+	namespaceCounts := map[string]int{}
+	for i := range namespaces.Items {
+		if overcommitClass, _ := namespaceClass(&overcommitResource, overcommitClasses.Items, &namespaces.Items[i]); overcommitClass != nil {
+			namespaceCounts[overcommitClass.Name]++
+		}
+	}
+	podCounts, outdatedCounts := map[string]int{}, map[string]int{}
+	for _, pod := range pods.Items {
+		className := pod.Annotations[overcommit.AppliedAnnotation]
+		if className == "" || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		podCounts[className]++
+		if overcommitClass := findClass(overcommitClasses.Items, className); overcommitClass != nil && utils.OvercommitOutdated(pod.Annotations, &overcommitClass.Spec) {
+			outdatedCounts[className]++
+		}
+	}
+
+	w := tabwriter.NewWriter(p.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDEFAULT\tCPU\tMEMORY\tSTATE\tFAILURE POLICY\tTIMEOUT\tNAMESPACES\tPODS\tOUTDATED")
+	for _, overcommitClass := range overcommitClasses.Items {
+		state := "active"
+		if overcommitClass.Spec.Suspended {
+			state = "suspended"
+		} else if overcommitClass.Spec.Deprecated {
+			state = "deprecated"
+		}
+		settings := overcommitClass.Spec.Webhook.WithDefaults()
+		fmt.Fprintf(w, "%s\t%t\t%.4f\t%.4f\t%s\t%s\t%ds\t%d\t%d\t%d\n", overcommitClass.Name, overcommitClass.Spec.IsDefault,
+			overcommitClass.Spec.CpuOvercommit, overcommitClass.Spec.MemoryOvercommit, state,
+			settings.FailurePolicy, settings.TimeoutSeconds,
+			namespaceCounts[overcommitClass.Name], podCounts[overcommitClass.Name], outdatedCounts[overcommitClass.Name])
+	}
+	return w.Flush()
+}
+
+// Namespaces lists the class every namespace resolves to for the pods created without a class label, and why.
+func (p *Plugin) Namespaces(ctx context.Context) error {
+	overcommitResource, err := utils.GetOvercommit(ctx, p.Client)
+	if err != nil {
+		return err
+	}
+	overcommitClasses := &overcommit.OvercommitClassList{}
+	if err := p.Client.List(ctx, overcommitClasses); err != nil {
+		return err
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := p.Client.List(ctx, namespaces); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(p.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tCLASS\tREASON")
+	for i := range namespaces.Items {
+		overcommitClass, reason := namespaceClass(&overcommitResource, overcommitClasses.Items, &namespaces.Items[i])
+		name := "-"
+		if overcommitClass != nil {
+			name = overcommitClass.Name
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", namespaces.Items[i].Name, name, reason)
+	}
+	return w.Flush()
+}
+
+// namespaceClass returns the class applied to the pods created in the namespace without a class label, nil
+// when they are left untouched, and why.
+func namespaceClass(overcommitResource *overcommit.Overcommit, overcommitClasses []overcommit.OvercommitClass, namespace *corev1.Namespace) (*overcommit.OvercommitClass, string) {
+	// The reason of the route explains invalid exclusions
+	route, _ := overcommitpkg.RouteClass(overcommitResource, overcommitClasses, namespace, nil)
+	if route.Class == nil {
+		return nil, route.Reason
+	}
+	return route.Class, sourceDescriptions[route.Source]
+}
+
+// Simulate describes how the pods of the manifests would be mutated if they were created now. The manifests
+// hold pods or workloads with a pod template, the objects without a namespace being created in namespace.
+func (p *Plugin) Simulate(ctx context.Context, manifests io.Reader, namespace string) error {
+	pods, err := ManifestPods(manifests, namespace)
+	if err != nil {
+		return err
+	}
+	for i, pod := range pods {
+		if i > 0 {
+			fmt.Fprintln(p.Out)
+		}
+		admission, err := p.Admit(ctx, pod)
+		if err != nil {
+			return err
+		}
+		p.field("Pod", "%s/%s\n", pod.Namespace, pod.Name)
+		p.printAdmission(admission)
+	}
+	return nil
+}
+
+// ManifestPods reads the pods of the YAML or JSON manifests, the pods of a workload being built from its pod
+// template and named after the workload. Objects of other kinds are rejected.
+func ManifestPods(manifests io.Reader, namespace string) ([]*corev1.Pod, error) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, appsv1.AddToScheme, batchv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			return nil, err
+		}
+	}
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var pods []*corev1.Pod
+	reader := utilyaml.NewYAMLOrJSONDecoder(manifests, 4096)
+	for document := 1; ; document++ {
+		var raw runtime.RawExtension
+		if err := reader.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return pods, nil
+			}
+			return nil, fmt.Errorf("error reading manifest %d: %w", document, err)
+		}
+		if len(raw.Raw) == 0 {
+			continue
+		}
+		obj, _, err := decoder.Decode(raw.Raw, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding manifest %d: %w", document, err)
+		}
+		pod, err := manifestPod(obj)
+		if err != nil {
+			return nil, fmt.Errorf("manifest %d: %w", document, err)
+		}
+		if pod.Namespace == "" {
+			pod.Namespace = namespace
+		}
+		pods = append(pods, pod)
+	}
+}
+
+// manifestPod returns the pod of an object, built from the pod template of a workload.
+func manifestPod(obj runtime.Object) (*corev1.Pod, error) {
+	var meta metav1.ObjectMeta
+	var template corev1.PodTemplateSpec
+	switch workload := obj.(type) {
+	case *corev1.Pod:
+		return workload, nil
+	case *appsv1.Deployment:
+		meta, template = workload.ObjectMeta, workload.Spec.Template
+	case *appsv1.StatefulSet:
+		meta, template = workload.ObjectMeta, workload.Spec.Template
+	case *appsv1.DaemonSet:
+		meta, template = workload.ObjectMeta, workload.Spec.Template
+	case *appsv1.ReplicaSet:
+		meta, template = workload.ObjectMeta, workload.Spec.Template
+	case *batchv1.Job:
+		meta, template = workload.ObjectMeta, workload.Spec.Template
+	case *batchv1.CronJob:
+		meta, template = workload.ObjectMeta, workload.Spec.JobTemplate.Spec.Template
+	default:
+		return nil, fmt.Errorf("unsupported kind %s, only pods and workloads with a pod template are simulated", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	pod.Namespace = meta.Namespace
+	pod.Name = meta.Name
+	return pod, nil
+}
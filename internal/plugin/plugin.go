@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plugin implements the kubectl overcommit plugin. The admission of a pod is replayed with the
+// mutation of pkg/overcommit against the live cluster, routed to the webhook the webhook configurations would
+// call, so that its answers match the ones of the mutating webhooks.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// instance is the webhook recorded in the decisions computed by the plugin.
+const instance = "kubectl-overcommit"
+
+// sourceDescriptions explain where the class of a pod was resolved from.
+var sourceDescriptions = map[overcommitpkg.ClassSource]string{
+	overcommitpkg.ClassSourcePod:       "class label of the pod",
+	overcommitpkg.ClassSourceNamespace: "class label of the namespace",
+	overcommitpkg.ClassSourceDefault:   "default class",
+}
+
+// Plugin answers how the overcommit applies to the pods of a cluster. It only reads from the cluster.
+type Plugin struct {
+	Client client.Client
+	Out    io.Writer
+}
+
+// Admission is the outcome of the admission of a pod by the mutating webhooks.
+type Admission struct {
+	// Webhook is the class whose webhook is called for the pod, nil when no webhook is called.
+	Webhook *overcommit.OvercommitClass
+	// Reason explains why no webhook is called for the pod.
+	Reason string
+	// Result is what the webhook did to the pod.
+	Result overcommitpkg.Result
+	// Pod is the pod as admitted.
+	Pod *corev1.Pod
+	// Decision is the decision recorded on the admitted pod, nil when it was not mutated.
+	Decision *overcommitpkg.Decision
+}
+
+// Admit replays the admission of a pod being created. The pod is routed like the webhook configurations route
+// it, and mutated by the webhook of the selected class like the webhook does.
+func (p *Plugin) Admit(ctx context.Context, pod *corev1.Pod) (*Admission, error) {
+	admission := &Admission{Pod: pod.DeepCopy()}
+	overcommitResource, err := utils.GetOvercommit(ctx, p.Client)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			admission.Reason = "no Overcommit exists, no webhook is deployed"
+			return admission, nil
+		}
+		return nil, err
+	}
+	overcommitClasses := &overcommit.OvercommitClassList{}
+	if err := p.Client.List(ctx, overcommitClasses); err != nil {
+		return nil, err
+	}
+	namespace := &corev1.Namespace{}
+	if err := p.Client.Get(ctx, client.ObjectKey{Name: pod.Namespace}, namespace); err != nil {
+		return nil, fmt.Errorf("error getting the namespace %s: %w", pod.Namespace, err)
+	}
+
+	// The reason of the route explains invalid exclusions
+	route, _ := overcommitpkg.RouteClass(&overcommitResource, overcommitClasses.Items, namespace, pod.Labels)
+	if route.Webhook == nil {
+		admission.Reason = route.Reason
+		return admission, nil
+	}
+	admission.Webhook = route.Webhook
+	// Events are not recorded, the plugin leaves the cluster untouched
+	admission.Result = overcommitpkg.Overcommit(ctx, admission.Pod, &record.FakeRecorder{}, p.Client,
+		overcommitpkg.Options{ClassName: admission.Webhook.Name, Instance: instance})
+	if admission.Decision, err = overcommitpkg.GetDecision(admission.Pod); err != nil {
+		return nil, err
+	}
+	return admission, nil
+}
+
+func findClass(overcommitClasses []overcommit.OvercommitClass, name string) *overcommit.OvercommitClass {
+	for i := range overcommitClasses {
+		if overcommitClasses[i].Name == name {
+			return &overcommitClasses[i]
+		}
+	}
+	return nil
+}
+
+// printAdmission writes how a pod is admitted.
+func (p *Plugin) printAdmission(admission *Admission) {
+	if admission.Webhook == nil {
+		p.field("Webhook", "none, %s\n", admission.Reason)
+		p.field("Outcome", "not mutated, the requests are kept\n")
+		return
+	}
+	p.field("Webhook", "%s\n", admission.Webhook.Name)
+	outcome := admission.Result.Outcome
+	if admission.Result.SkipReason != "" {
+		outcome += " (" + admission.Result.SkipReason + ")"
+	}
+	p.field("Outcome", "%s\n", outcome)
+	if admission.Decision != nil {
+		p.printDecision(admission.Decision)
+	}
+}
+
+// printDecision writes the class of a decision, where it was resolved from, and the requests computed for
+// every container from its limits.
+func (p *Plugin) printDecision(decision *overcommitpkg.Decision) {
+	source, ok := sourceDescriptions[decision.Source]
+	if !ok {
+		source = "no class resolved, the ratios of the webhook are 1"
+	}
+	p.field("Class", "%s (%s)\n", decision.Class, source)
+	p.field("Ratios", "cpu %.4f, memory %.4f\n", decision.CpuOvercommit, decision.MemoryOvercommit)
+
+	fmt.Fprintln(p.Out)
+	w := tabwriter.NewWriter(p.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTAINER\tCPU LIMIT\tCPU REQUEST\tMEMORY LIMIT\tMEMORY REQUEST")
+	for _, container := range decision.Containers {
+		name := container.Name
+		if container.Init {
+			name += " (init)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name,
+			quantity(container.Original.Limits, corev1.ResourceCPU),
+			change(container.Original.Requests, container.Requests, corev1.ResourceCPU),
+			quantity(container.Original.Limits, corev1.ResourceMemory),
+			change(container.Original.Requests, container.Requests, corev1.ResourceMemory))
+	}
+	_ = w.Flush()
+}
+
+// field writes a field of the description of a pod.
+func (p *Plugin) field(name, format string, args ...any) {
+	fmt.Fprintf(p.Out, "%-9s "+format, append([]any{name + ":"}, args...)...)
+}
+
+// quantity formats a resource of a list, - when the list does not set it.
+func quantity(resources corev1.ResourceList, name corev1.ResourceName) string {
+	value, ok := resources[name]
+	if !ok {
+		return "-"
+	}
+	return value.String()
+}
+
+// change formats the change of a request by the mutation.
+func change(original, computed corev1.ResourceList, name corev1.ResourceName) string {
+	before, after := quantity(original, name), quantity(computed, name)
+	if computed == nil || before == after {
+		return before
+	}
+	return before + " -> " + after
+}
@@ -0,0 +1,335 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	overcommitpkg "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testPlugin(t *testing.T, objects ...client.Object) (*Plugin, *bytes.Buffer) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := overcommit.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	objects = append(objects,
+		&overcommit.Overcommit{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       overcommit.OvercommitSpec{OvercommitLabel: "inditex.com/overcommit-class"},
+		},
+		&overcommit.OvercommitClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: overcommit.OvercommitClassSpec{
+				CpuOvercommit:    0.5,
+				MemoryOvercommit: 0.8,
+				IsDefault:        true,
+				NamespaceExclusions: &overcommit.NamespaceExclusions{
+					Names: []string{"kube-system"},
+				},
+			},
+		},
+		&overcommit.OvercommitClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch"},
+			Spec:       overcommit.OvercommitClassSpec{CpuOvercommit: 0.1, MemoryOvercommit: 0.5},
+		},
+		&overcommit.OvercommitClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "frozen"},
+			Spec:       overcommit.OvercommitClassSpec{CpuOvercommit: 0.2, MemoryOvercommit: 0.2, Suspended: true},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "jobs",
+			Labels: map[string]string{"inditex.com/overcommit-class": "batch"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "legacy",
+			Labels: map[string]string{"inditex.com/overcommit-class": "frozen"},
+		}},
+	)
+	out := &bytes.Buffer{}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	return &Plugin{Client: k8sClient, Out: out}, out
+}
+
+func testPod(namespace string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web", Labels: labels},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "app",
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}},
+		}}},
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		webhook    string
+		reason     string
+		class      string
+		source     overcommitpkg.ClassSource
+		cpuRequest string
+	}{
+		{
+			name:       "default class",
+			pod:        testPod("apps", nil),
+			webhook:    "default",
+			class:      "default",
+			source:     overcommitpkg.ClassSourceDefault,
+			cpuRequest: "1",
+		},
+		{
+			name:       "class label of the namespace",
+			pod:        testPod("jobs", nil),
+			webhook:    "default",
+			class:      "batch",
+			source:     overcommitpkg.ClassSourceNamespace,
+			cpuRequest: "200m",
+		},
+		{
+			name:       "class label of the pod",
+			pod:        testPod("apps", map[string]string{"inditex.com/overcommit-class": "batch"}),
+			webhook:    "batch",
+			class:      "batch",
+			source:     overcommitpkg.ClassSourcePod,
+			cpuRequest: "200m",
+		},
+		{
+			name:   "excluded namespace",
+			pod:    testPod("kube-system", nil),
+			reason: "namespace kube-system is excluded by OvercommitClass default",
+		},
+		{
+			name:   "suspended class",
+			pod:    testPod("apps", map[string]string{"inditex.com/overcommit-class": "frozen"}),
+			reason: "OvercommitClass frozen is suspended, its webhook is removed",
+		},
+		{
+			name:   "missing class",
+			pod:    testPod("apps", map[string]string{"inditex.com/overcommit-class": "missing"}),
+			reason: "the class label of the pod names OvercommitClass missing, which does not exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := testPlugin(t)
+			admission, err := p.Admit(context.Background(), tt.pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.webhook == "" {
+				if admission.Webhook != nil || admission.Reason != tt.reason {
+					t.Errorf("Expected no webhook because %q, got %v because %q", tt.reason, admission.Webhook, admission.Reason)
+				}
+				return
+			}
+			if admission.Webhook == nil || admission.Webhook.Name != tt.webhook {
+				t.Fatalf("Expected the webhook of %s, got %v because %q", tt.webhook, admission.Webhook, admission.Reason)
+			}
+			if admission.Decision == nil || admission.Decision.Class != tt.class || admission.Decision.Source != tt.source {
+				t.Fatalf("Expected a decision of class %s from %s, got %+v", tt.class, tt.source, admission.Decision)
+			}
+			if cpu := admission.Pod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse(tt.cpuRequest)) != 0 {
+				t.Errorf("Expected the cpu request %s, got %s", tt.cpuRequest, cpu.String())
+			}
+			if _, ok := tt.pod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]; ok {
+				t.Errorf("Expected the admitted pod to be left untouched")
+			}
+		})
+	}
+}
+
+func TestAdmitPaused(t *testing.T) {
+	p, _ := testPlugin(t)
+	overcommitResource := &overcommit.Overcommit{}
+	if err := p.Client.Get(context.Background(), client.ObjectKey{Name: "cluster"}, overcommitResource); err != nil {
+		t.Fatal(err)
+	}
+	overcommitResource.Spec.Paused = true
+	if err := p.Client.Update(context.Background(), overcommitResource); err != nil {
+		t.Fatal(err)
+	}
+
+	admission, err := p.Admit(context.Background(), testPod("apps", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admission.Webhook != nil || admission.Reason != "the Overcommit is paused, the webhooks are removed" {
+		t.Errorf("Expected no webhook while the Overcommit is paused, got %v because %q", admission.Webhook, admission.Reason)
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	p, out := testPlugin(t)
+	if err := p.Namespaces(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range [][]string{
+		{"apps", "default", "default class"},
+		{"jobs", "batch", "class label of the namespace"},
+		{"kube-system", "-", "namespace kube-system is excluded by OvercommitClass default"},
+		{"legacy", "-", "OvercommitClass frozen is suspended"},
+	} {
+		if !containsFields(out.String(), want) {
+			t.Errorf("Expected the namespaces to list %v, got\n%s", want, out.String())
+		}
+	}
+}
+
+func TestClasses(t *testing.T) {
+	mutated := testPod("apps", nil)
+	mutated.Annotations = map[string]string{
+		overcommit.AppliedAnnotation:    "default",
+		"overcommit.inditex.dev/cpu":    "0.5000",
+		"overcommit.inditex.dev/memory": "0.8000",
+	}
+	outdated := testPod("jobs", nil)
+	outdated.Name = "job"
+	outdated.Annotations = map[string]string{
+		overcommit.AppliedAnnotation:    "batch",
+		"overcommit.inditex.dev/cpu":    "0.2000",
+		"overcommit.inditex.dev/memory": "0.5000",
+	}
+	p, out := testPlugin(t, mutated, outdated)
+	if err := p.Classes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range [][]string{
+		{"batch", "false", "0.1000", "0.5000", "active", "Fail", "10s", "1", "1", "1"},
+		{"default", "true", "0.5000", "0.8000", "active", "Fail", "10s", "1", "1", "0"},
+		{"frozen", "false", "0.2000", "0.2000", "suspended", "Fail", "10s", "0", "0", "0"},
+	} {
+		if !containsFields(out.String(), want) {
+			t.Errorf("Expected the classes to list %v, got\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	manifests := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: app
+        resources:
+          limits:
+            cpu: "2"
+            memory: 1Gi
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: job
+  namespace: jobs
+spec:
+  containers:
+  - name: worker
+    resources:
+      limits:
+        cpu: "1"
+`
+	p, out := testPlugin(t)
+	if err := p.Simulate(context.Background(), strings.NewReader(manifests), "apps"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Pod:      apps/web",
+		"Class:    default (default class)",
+		"app        2          - -> 1       1Gi           - -> 858993459",
+		"Pod:      jobs/job",
+		"Class:    batch (class label of the namespace)",
+		"worker     1          - -> 100m    -             -",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected the simulation to contain %q, got\n%s", want, out.String())
+		}
+	}
+
+	if err := p.Simulate(context.Background(), strings.NewReader("apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"), "apps"); err == nil {
+		t.Errorf("Expected the manifests of other kinds to be rejected")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	p, out := testPlugin(t)
+	admission, err := p.Admit(context.Background(), testPod("jobs", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Client.Create(context.Background(), admission.Pod); err != nil {
+		t.Fatal(err)
+	}
+	// The ratios of the class changed after the pod was created
+	batch := &overcommit.OvercommitClass{}
+	if err := p.Client.Get(context.Background(), client.ObjectKey{Name: "batch"}, batch); err != nil {
+		t.Fatal(err)
+	}
+	batch.Spec.CpuOvercommit = 0.25
+	if err := p.Client.Update(context.Background(), batch); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Explain(context.Background(), "jobs", "web"); err != nil {
+		t.Fatal(err)
+	}
+	atCreation, now, _ := strings.Cut(out.String(), "If created now:")
+	for _, want := range []string{
+		"Webhook:  kubectl-overcommit, pass 1",
+		"Class:    batch (class label of the namespace)",
+		"Drift:    OvercommitClass batch now has cpu 0.2500, memory 0.5000",
+		"app        2          - -> 200m",
+	} {
+		if !strings.Contains(atCreation, want) {
+			t.Errorf("Expected the decision at creation to contain %q, got\n%s", want, atCreation)
+		}
+	}
+	for _, want := range []string{"Ratios:   cpu 0.2500, memory 0.5000", "app        2          - -> 500m"} {
+		if !strings.Contains(now, want) {
+			t.Errorf("Expected the admission if created now to contain %q, got\n%s", want, now)
+		}
+	}
+}
+
+// containsFields reports whether a line of a table holds the fields, in order.
+func containsFields(table string, fields []string) bool {
+	for _, line := range strings.Split(table, "\n") {
+		if strings.Join(strings.Fields(line), " ") == strings.Join(fields, " ") {
+			return true
+		}
+	}
+	return false
+}
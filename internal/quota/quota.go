@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package quota computes how the overcommit of a class changes the way the ResourceQuotas of its namespaces are
+// consumed. The requests of the pods are derived from their limits, so the requests.* quotas are consumed at
+// the ratios of the class while the limits.* quotas are not.
+package quota
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// ClassAnnotation records on a ResourceQuota the class overcommitting the pods of its namespace.
+	ClassAnnotation = "overcommit.inditex.dev/class"
+	// CPUAnnotation and MemoryAnnotation record the ratios of the class, formatted like on the pods.
+	CPUAnnotation    = "overcommit.inditex.dev/cpu"
+	MemoryAnnotation = "overcommit.inditex.dev/memory"
+	// OriginalAnnotationPrefix prefixes the name of a requests hard limit to record its value before scaling.
+	OriginalAnnotationPrefix = "overcommit.inditex.dev/original-"
+	// UnreachableAnnotation lists the resources whose limits quota cannot be reached before the requests quota.
+	UnreachableAnnotation = "overcommit.inditex.dev/unreachable-limits"
+)
+
+// Ratios are the overcommit ratios of a class.
+type Ratios struct {
+	CPU    float64
+	Memory float64
+}
+
+// Annotation formats a ratio like the ones recorded on the mutated pods.
+func Annotation(ratio float64) string {
+	return fmt.Sprintf("%.4f", ratio)
+}
+
+// quotaResource is a resource whose requests are overcommitted, with the names of its hard limits.
+type quotaResource struct {
+	name corev1.ResourceName
+	// requests are the names of the requests hard limits, the plain name being an alias of requests.*
+	requests []corev1.ResourceName
+	limits   corev1.ResourceName
+}
+
+var quotaResources = []quotaResource{
+	{name: corev1.ResourceCPU, requests: []corev1.ResourceName{corev1.ResourceRequestsCPU, corev1.ResourceCPU}, limits: corev1.ResourceLimitsCPU},
+	{name: corev1.ResourceMemory, requests: []corev1.ResourceName{corev1.ResourceRequestsMemory, corev1.ResourceMemory}, limits: corev1.ResourceLimitsMemory},
+}
+
+func (r quotaResource) ratio(ratios Ratios) float64 {
+	if r.name == corev1.ResourceCPU {
+		return ratios.CPU
+	}
+	return ratios.Memory
+}
+
+// scale applies a ratio to a requests hard limit, truncating it like the webhook truncates the requests.
+func (r quotaResource) scale(quantity resource.Quantity, ratio float64) resource.Quantity {
+	if r.name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(int64(float64(quantity.MilliValue())*ratio), quantity.Format)
+	}
+	return *resource.NewQuantity(int64(float64(quantity.Value())*ratio), quantity.Format)
+}
+
+// annotatedRatios returns the ratios recorded on the quota, false when they are missing or invalid.
+func annotatedRatios(quota *corev1.ResourceQuota) (Ratios, bool) {
+	var ratios Ratios
+	_, cpuErr := fmt.Sscanf(quota.Annotations[CPUAnnotation], "%g", &ratios.CPU)
+	_, memoryErr := fmt.Sscanf(quota.Annotations[MemoryAnnotation], "%g", &ratios.Memory)
+	return ratios, cpuErr == nil && memoryErr == nil
+}
+
+// OriginalRequests returns the requests hard limits of the quota as set by its owner. A hard limit scaled by
+// the operator is read from its original annotation, unless it no longer matches the original scaled by the
+// recorded ratios: the owner then changed it and its current value is the original one.
+func OriginalRequests(quota *corev1.ResourceQuota) corev1.ResourceList {
+	recorded, scaled := annotatedRatios(quota)
+	originals := corev1.ResourceList{}
+	for _, r := range quotaResources {
+		for _, name := range r.requests {
+			hard, ok := quota.Spec.Hard[name]
+			if !ok {
+				continue
+			}
+			originals[name] = hard
+			annotation, annotated := quota.Annotations[OriginalAnnotationPrefix+string(name)]
+			if !annotated || !scaled {
+				continue
+			}
+			original, err := resource.ParseQuantity(annotation)
+			if err != nil {
+				continue
+			}
+			if expected := r.scale(original, r.ratio(recorded)); expected.Cmp(hard) == 0 {
+				originals[name] = original
+			}
+		}
+	}
+	return originals
+}
+
+// Scale returns the requests hard limits scaled by the ratios, the quota then admitting the same pods as
+// before the overcommit when their requests equal their limits.
+func Scale(requests corev1.ResourceList, ratios Ratios) corev1.ResourceList {
+	scaled := corev1.ResourceList{}
+	for _, r := range quotaResources {
+		for _, name := range r.requests {
+			if quantity, ok := requests[name]; ok {
+				scaled[name] = r.scale(quantity, r.ratio(ratios))
+			}
+		}
+	}
+	return scaled
+}
+
+// Unreachable returns the sorted resources whose limits hard limit cannot be reached with the ratios: the
+// pods exhaust the requests hard limit first, their requests being their limits scaled by the ratios.
+func Unreachable(hard corev1.ResourceList, ratios Ratios) []corev1.ResourceName {
+	var unreachable []corev1.ResourceName
+	for _, r := range quotaResources {
+		limits, ok := hard[r.limits]
+		if !ok {
+			continue
+		}
+		for _, name := range r.requests {
+			requests, ok := hard[name]
+			if ok && limits.AsApproximateFloat64()*r.ratio(ratios) > requests.AsApproximateFloat64()*(1+1e-9) {
+				unreachable = append(unreachable, r.name)
+				break
+			}
+		}
+	}
+	return unreachable
+}
+
+// Hard returns the hard limits the quota has with the ratios, its requests hard limits being scaled when
+// scale is set and restored to their original values otherwise.
+func Hard(quota *corev1.ResourceQuota, ratios Ratios, scale bool) corev1.ResourceList {
+	hard := quota.Spec.Hard.DeepCopy()
+	requests := OriginalRequests(quota)
+	if scale {
+		requests = Scale(requests, ratios)
+	}
+	for name, quantity := range requests {
+		hard[name] = quantity
+	}
+	return hard
+}
+
+// NewlyUnreachable returns the resources whose limits hard limit becomes unreachable when the ratios of the
+// class of the quota change from previous to next.
+func NewlyUnreachable(quota *corev1.ResourceQuota, previous, next Ratios, scale bool) []corev1.ResourceName {
+	before := Unreachable(Hard(quota, previous, scale), previous)
+	var newly []corev1.ResourceName
+	for _, name := range Unreachable(Hard(quota, next, scale), next) {
+		if !slices.Contains(before, name) {
+			newly = append(newly, name)
+		}
+	}
+	return newly
+}
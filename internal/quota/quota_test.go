@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testQuota(annotations map[string]string, hard map[corev1.ResourceName]string) *corev1.ResourceQuota {
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "compute", Annotations: annotations},
+		Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{}},
+	}
+	for name, value := range hard {
+		quota.Spec.Hard[name] = resource.MustParse(value)
+	}
+	return quota
+}
+
+func TestScale(t *testing.T) {
+	scaled := Scale(corev1.ResourceList{
+		corev1.ResourceRequestsCPU:    resource.MustParse("10"),
+		corev1.ResourceMemory:         resource.MustParse("10Gi"),
+		corev1.ResourceRequestsMemory: resource.MustParse("3"),
+	}, Ratios{CPU: 0.25, Memory: 0.5})
+
+	if cpu := scaled[corev1.ResourceRequestsCPU]; cpu.MilliValue() != 2500 {
+		t.Errorf("Expected requests.cpu to be scaled to 2500m, got %s", cpu.String())
+	}
+	if memory := scaled[corev1.ResourceMemory]; memory.Value() != 5*1024*1024*1024 {
+		t.Errorf("Expected memory to be scaled to 5Gi, got %s", memory.String())
+	}
+	// Bytes are truncated like the requests of the pods
+	if memory := scaled[corev1.ResourceRequestsMemory]; memory.Value() != 1 {
+		t.Errorf("Expected requests.memory to be truncated to 1, got %s", memory.String())
+	}
+}
+
+func TestOriginalRequests(t *testing.T) {
+	scaled := testQuota(map[string]string{
+		CPUAnnotation:    "0.5000",
+		MemoryAnnotation: "1.0000",
+		OriginalAnnotationPrefix + "requests.cpu": "10",
+	}, map[corev1.ResourceName]string{corev1.ResourceRequestsCPU: "5", corev1.ResourceLimitsCPU: "20"})
+	if cpu := OriginalRequests(scaled)[corev1.ResourceRequestsCPU]; cpu.Cmp(resource.MustParse("10")) != 0 {
+		t.Errorf("Expected the original requests.cpu to be read from the annotation, got %s", cpu.String())
+	}
+	if _, ok := OriginalRequests(scaled)[corev1.ResourceLimitsCPU]; ok {
+		t.Errorf("Expected the limits to be left out of the requests")
+	}
+
+	// The owner changed the hard limit after it was scaled
+	changed := scaled.DeepCopy()
+	changed.Spec.Hard[corev1.ResourceRequestsCPU] = resource.MustParse("8")
+	if cpu := OriginalRequests(changed)[corev1.ResourceRequestsCPU]; cpu.Cmp(resource.MustParse("8")) != 0 {
+		t.Errorf("Expected the changed requests.cpu to be the original one, got %s", cpu.String())
+	}
+
+	unscaled := testQuota(nil, map[corev1.ResourceName]string{corev1.ResourceCPU: "4"})
+	if cpu := OriginalRequests(unscaled)[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("4")) != 0 {
+		t.Errorf("Expected the current cpu to be the original one, got %s", cpu.String())
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	hard := testQuota(nil, map[corev1.ResourceName]string{
+		corev1.ResourceRequestsCPU:    "10",
+		corev1.ResourceLimitsCPU:      "20",
+		corev1.ResourceMemory:         "8Gi",
+		corev1.ResourceLimitsMemory:   "16Gi",
+		corev1.ResourceRequestsMemory: "16Gi",
+	}).Spec.Hard
+
+	if unreachable := Unreachable(hard, Ratios{CPU: 0.5, Memory: 0.5}); len(unreachable) != 0 {
+		t.Errorf("Expected the limits to be reachable at the ratios of the quotas, got %v", unreachable)
+	}
+	expected := []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+	if unreachable := Unreachable(hard, Ratios{CPU: 0.6, Memory: 0.6}); !slices.Equal(unreachable, expected) {
+		t.Errorf("Expected %v to be unreachable, got %v", expected, unreachable)
+	}
+	if unreachable := Unreachable(corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("1")}, Ratios{CPU: 1, Memory: 1}); len(unreachable) != 0 {
+		t.Errorf("Expected the limits without a requests quota to be reachable, got %v", unreachable)
+	}
+}
+
+func TestNewlyUnreachable(t *testing.T) {
+	quota := testQuota(nil, map[corev1.ResourceName]string{corev1.ResourceRequestsCPU: "10", corev1.ResourceLimitsCPU: "20"})
+
+	newly := NewlyUnreachable(quota, Ratios{CPU: 0.5, Memory: 1}, Ratios{CPU: 0.8, Memory: 1}, false)
+	if !slices.Equal(newly, []corev1.ResourceName{corev1.ResourceCPU}) {
+		t.Errorf("Expected the cpu limits to become unreachable, got %v", newly)
+	}
+	if newly := NewlyUnreachable(quota, Ratios{CPU: 0.8, Memory: 1}, Ratios{CPU: 0.9, Memory: 1}, false); len(newly) != 0 {
+		t.Errorf("Expected the already unreachable limits not to be reported, got %v", newly)
+	}
+	// Scaled requests quotas follow the ratios, a change of the ratios does not change which limits are reachable
+	if newly := NewlyUnreachable(quota, Ratios{CPU: 0.5, Memory: 1}, Ratios{CPU: 0.8, Memory: 1}, true); len(newly) != 0 {
+		t.Errorf("Expected no limits to become unreachable with scaled requests quotas, got %v", newly)
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package recommender
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// podMetricsListGVK is the list of the usage of the pods served by metrics-server.
+var podMetricsListGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+// MetricsAPISource samples the current usage of the containers from the metrics.k8s.io API. The API only
+// serves the latest usage, the samples of the pods taken on every call are kept in memory for the length of
+// the window.
+type MetricsAPISource struct {
+	// Reader reads the pod metrics, which cannot be watched nor cached.
+	Reader client.Reader
+
+	mu      sync.Mutex
+	samples []Sample
+}
+
+// Samples takes a new sample of the containers of the pods and returns the ones taken within the window.
+func (s *MetricsAPISource) Samples(ctx context.Context, pods []corev1.Pod, window time.Duration) ([]Sample, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	if err := s.Reader.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("unable to list the pod metrics: %w", err)
+	}
+	current, err := samplesFromPodMetrics(list)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := make(map[sampleID]bool, len(s.samples))
+	kept := s.samples[:0]
+	cutoff := time.Now().Add(-window)
+	for _, sample := range s.samples {
+		if sample.Time.After(cutoff) {
+			kept = append(kept, sample)
+			seen[sampleKey(sample)] = true
+		}
+	}
+	// metrics-server refreshes the usage every scrape, a sample polled twice is recorded once
+	indexed := indexPods(pods)
+	for _, sample := range current {
+		if indexed[podKey{namespace: sample.Namespace, name: sample.Pod}] != nil && !seen[sampleKey(sample)] {
+			kept = append(kept, sample)
+		}
+	}
+	s.samples = kept
+	return append([]Sample(nil), kept...), nil
+}
+
+// sampleID identifies a sample by its container and time.
+type sampleID struct {
+	namespace string
+	pod       string
+	container string
+	time      int64
+}
+
+func sampleKey(sample Sample) sampleID {
+	return sampleID{namespace: sample.Namespace, pod: sample.Pod, container: sample.Container, time: sample.Time.Unix()}
+}
+
+// samplesFromPodMetrics reads the usage of every container from a PodMetricsList.
+func samplesFromPodMetrics(list *unstructured.UnstructuredList) ([]Sample, error) {
+	var samples []Sample
+	for _, item := range list.Items {
+		timestamp, _, _ := unstructured.NestedString(item.Object, "timestamp")
+		sampled, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp of the metrics of pod %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+		}
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		for _, entry := range containers {
+			container, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(container, "name")
+			usage, _, _ := unstructured.NestedStringMap(container, "usage")
+			sample := Sample{Namespace: item.GetNamespace(), Pod: item.GetName(), Container: name, Time: sampled}
+			if sample.CPU, err = parseUsage(usage, "cpu"); err != nil {
+				return nil, err
+			}
+			if sample.Memory, err = parseUsage(usage, "memory"); err != nil {
+				return nil, err
+			}
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+func parseUsage(usage map[string]string, name string) (*resource.Quantity, error) {
+	value, ok := usage[name]
+	if !ok {
+		return nil, nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s usage %q: %w", name, value, err)
+	}
+	return &quantity, nil
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package recommender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// prometheusPoints is the number of points read for every container over the window.
+	prometheusPoints = 288
+	// prometheusSelector selects the series of the containers of the namespaces, leaving out the pod sandboxes.
+	prometheusSelector    = `container!="",container!="POD",namespace=~"%s"`
+	prometheusCPUQuery    = `sum by (namespace, pod, container) (rate(container_cpu_usage_seconds_total{` + prometheusSelector + `}[5m]))`
+	prometheusMemoryQuery = `sum by (namespace, pod, container) (container_memory_working_set_bytes{` + prometheusSelector + `})`
+)
+
+// defaultPrometheusClient gives up on the queries taking longer than a sample period.
+var defaultPrometheusClient = &http.Client{Timeout: time.Minute}
+
+// PrometheusSource reads the usage of the containers over the window from the cAdvisor metrics exposed by a
+// Prometheus-compatible HTTP API.
+type PrometheusSource struct {
+	URL string
+	// Client defaults to a client timing out after a minute.
+	Client *http.Client
+}
+
+// Samples reads the usage of the containers of the namespaces of the pods over the window.
+func (s *PrometheusSource) Samples(ctx context.Context, pods []corev1.Pod, window time.Duration) ([]Sample, error) {
+	if len(pods) == 0 {
+		return nil, nil
+	}
+	namespaces := map[string]bool{}
+	for _, pod := range pods {
+		namespaces[pod.Namespace] = true
+	}
+	names := make([]string, 0, len(namespaces))
+	for namespace := range namespaces {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+	selector := strings.Join(names, "|")
+
+	end := time.Now()
+	start := end.Add(-window)
+	step := max(window/prometheusPoints, time.Minute)
+
+	samples := map[sampleID]*Sample{}
+	sampleOf := func(id sampleID) *Sample {
+		if samples[id] == nil {
+			samples[id] = &Sample{Namespace: id.namespace, Pod: id.pod, Container: id.container, Time: time.Unix(id.time, 0)}
+		}
+		return samples[id]
+	}
+	cpu, err := s.queryRange(ctx, fmt.Sprintf(prometheusCPUQuery, selector), start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range cpu {
+		sampleOf(point.id).CPU = resource.NewMilliQuantity(int64(math.Ceil(point.value*1000)), resource.DecimalSI)
+	}
+	memory, err := s.queryRange(ctx, fmt.Sprintf(prometheusMemoryQuery, selector), start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range memory {
+		sampleOf(point.id).Memory = resource.NewQuantity(int64(point.value), resource.BinarySI)
+	}
+
+	result := make([]Sample, 0, len(samples))
+	for _, sample := range samples {
+		result = append(result, *sample)
+	}
+	return result, nil
+}
+
+// prometheusPoint is a value of a series of a container.
+type prometheusPoint struct {
+	id    sampleID
+	value float64
+}
+
+// prometheusResponse is the response of the range queries of the Prometheus HTTP API.
+type prometheusResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]interface{}  `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryRange runs a range query, returning its values. The values that are not a number are left out.
+func (s *PrometheusSource) queryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]prometheusPoint, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	params.Set("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(s.URL, "/")+"/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := s.Client
+	if httpClient == nil {
+		httpClient = defaultPrometheusClient
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query Prometheus: %w", err)
+	}
+	defer response.Body.Close()
+
+	decoded := &prometheusResponse{}
+	if err := json.NewDecoder(response.Body).Decode(decoded); err != nil {
+		return nil, fmt.Errorf("unable to decode the Prometheus response (HTTP %d): %w", response.StatusCode, err)
+	}
+	if decoded.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s: %s", decoded.ErrorType, decoded.Error)
+	}
+	if decoded.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("unexpected Prometheus result type %q", decoded.Data.ResultType)
+	}
+
+	var points []prometheusPoint
+	for _, series := range decoded.Data.Result {
+		for _, pair := range series.Values {
+			timestamp, ok := pair[0].(float64)
+			if !ok {
+				continue
+			}
+			text, ok := pair[1].(string)
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			points = append(points, prometheusPoint{
+				id: sampleID{
+					namespace: series.Metric["namespace"],
+					pod:       series.Metric["pod"],
+					container: series.Metric["container"],
+					time:      int64(timestamp),
+				},
+				value: value,
+			})
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package recommender recommends the overcommit ratios of a class from the observed usage of its containers.
+//
+// A ratio is the share of the limits requested by the containers. The recommended one keeps the configured
+// percentile of the usage samples, relative to the limits of their containers, under the requests it computes.
+package recommender
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// minRatio is the lowest ratio recommended, the ratios of a class must be positive.
+const minRatio = 0.01
+
+// Sample is the usage of a container at a point in time.
+type Sample struct {
+	Namespace string
+	Pod       string
+	Container string
+	Time      time.Time
+	// CPU and Memory are nil when the source did not report them.
+	CPU    *resource.Quantity
+	Memory *resource.Quantity
+}
+
+// Source reads the usage samples of the containers of a set of pods over a window.
+type Source interface {
+	Samples(ctx context.Context, pods []corev1.Pod, window time.Duration) ([]Sample, error)
+}
+
+// Recommendation holds the ratios recommended for a set of containers, zero for a resource without samples.
+type Recommendation struct {
+	CpuOvercommit    float64
+	MemoryOvercommit float64
+	Samples          int32
+	// Observed is the time between the first and the last samples.
+	Observed time.Duration
+}
+
+// Recommend computes the ratios keeping percentile of the usage samples of the pods under their requests. The
+// samples of other pods, or of containers without limits, are ignored. With byOwner, the ratios of each owner
+// of the pods are returned too, sorted by namespace, kind and name.
+func Recommend(pods []corev1.Pod, samples []Sample, percentile int32, byOwner bool) (Recommendation, []overcommit.OwnerRecommendation) {
+	indexed := indexPods(pods)
+
+	class := &usage{}
+	owners := map[ownerKey]*usage{}
+	for _, sample := range samples {
+		pod := indexed[podKey{namespace: sample.Namespace, name: sample.Pod}]
+		if pod == nil {
+			continue
+		}
+		container := findContainer(pod, sample.Container)
+		if container == nil {
+			continue
+		}
+		observed := &usage{}
+		observed.add(sample.CPU, container.Resources.Limits, corev1.ResourceCPU)
+		observed.add(sample.Memory, container.Resources.Limits, corev1.ResourceMemory)
+		if len(observed.cpu) == 0 && len(observed.memory) == 0 {
+			continue
+		}
+		observed.samples = 1
+		observed.first, observed.last = sample.Time, sample.Time
+		class.merge(observed)
+		if byOwner {
+			key := podOwner(pod)
+			if owners[key] == nil {
+				owners[key] = &usage{}
+			}
+			owners[key].merge(observed)
+		}
+	}
+
+	recommendation := class.recommend(percentile)
+	if !byOwner {
+		return recommendation, nil
+	}
+	recommendations := make([]overcommit.OwnerRecommendation, 0, len(owners))
+	for key, observed := range owners {
+		owner := observed.recommend(percentile)
+		recommendations = append(recommendations, overcommit.OwnerRecommendation{
+			Kind:             key.kind,
+			Namespace:        key.namespace,
+			Name:             key.name,
+			CpuOvercommit:    owner.CpuOvercommit,
+			MemoryOvercommit: owner.MemoryOvercommit,
+			Samples:          owner.Samples,
+		})
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return recommendation, recommendations
+}
+
+// usage holds the samples of a set of containers as fractions of their limits.
+type usage struct {
+	cpu         []float64
+	memory      []float64
+	samples     int32
+	first, last time.Time
+}
+
+// add records the fraction of the limit of a resource used by a sample.
+func (u *usage) add(used *resource.Quantity, limits corev1.ResourceList, name corev1.ResourceName) {
+	limit, ok := limits[name]
+	if used == nil || !ok || limit.IsZero() {
+		return
+	}
+	fraction := used.AsApproximateFloat64() / limit.AsApproximateFloat64()
+	if name == corev1.ResourceCPU {
+		u.cpu = append(u.cpu, fraction)
+	} else {
+		u.memory = append(u.memory, fraction)
+	}
+}
+
+func (u *usage) merge(other *usage) {
+	u.cpu = append(u.cpu, other.cpu...)
+	u.memory = append(u.memory, other.memory...)
+	u.samples += other.samples
+	if u.first.IsZero() || other.first.Before(u.first) {
+		u.first = other.first
+	}
+	if other.last.After(u.last) {
+		u.last = other.last
+	}
+}
+
+func (u *usage) recommend(percentile int32) Recommendation {
+	return Recommendation{
+		CpuOvercommit:    ratio(u.cpu, percentile),
+		MemoryOvercommit: ratio(u.memory, percentile),
+		Samples:          u.samples,
+		Observed:         u.last.Sub(u.first),
+	}
+}
+
+// ratio returns the nearest-rank percentile of the fractions, rounded up to two decimals and bounded to the
+// valid ratios of a class. It returns 0 without fractions.
+func ratio(fractions []float64, percentile int32) float64 {
+	if len(fractions) == 0 {
+		return 0
+	}
+	sort.Float64s(fractions)
+	rank := int(math.Ceil(float64(percentile) / 100 * float64(len(fractions))))
+	value := fractions[max(rank, 1)-1]
+	// The epsilon keeps the exact ratios, like 0.5, from being rounded up by the float representation
+	value = math.Ceil(value*100-1e-9) / 100
+	return math.Min(math.Max(value, minRatio), 1)
+}
+
+// podKey identifies a pod.
+type podKey struct {
+	namespace string
+	name      string
+}
+
+func indexPods(pods []corev1.Pod) map[podKey]*corev1.Pod {
+	indexed := make(map[podKey]*corev1.Pod, len(pods))
+	for i := range pods {
+		indexed[podKey{namespace: pods[i].Namespace, name: pods[i].Name}] = &pods[i]
+	}
+	return indexed
+}
+
+func findContainer(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// ownerKey identifies the owner of a pod.
+type ownerKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// podOwner returns the workload of the pod.
+func podOwner(pod *corev1.Pod) ownerKey {
+	kind, name := utils.PodWorkload(pod)
+	return ownerKey{kind: kind, namespace: pod.Namespace, name: name}
+}
@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package recommender
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testPod(namespace, name string, owner *metav1.OwnerReference, labels map[string]string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "app",
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}},
+		}}},
+	}
+	if owner != nil {
+		pod.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return pod
+}
+
+func testSample(namespace, pod, cpu, memory string, at time.Time) Sample {
+	sample := Sample{Namespace: namespace, Pod: pod, Container: "app", Time: at}
+	if cpu != "" {
+		quantity := resource.MustParse(cpu)
+		sample.CPU = &quantity
+	}
+	if memory != "" {
+		quantity := resource.MustParse(memory)
+		sample.Memory = &quantity
+	}
+	return sample
+}
+
+func TestRecommendKeepsThePercentileUnderTheRequests(t *testing.T) {
+	pods := []corev1.Pod{testPod("apps", "web", nil, nil)}
+	start := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	var samples []Sample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, testSample("apps", "web", fmt.Sprintf("%dm", i*10), "256Mi", start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	recommendation, owners := Recommend(pods, samples, 95, false)
+
+	if recommendation.CpuOvercommit != 0.95 {
+		t.Errorf("Expected the CPU ratio to be the 95th percentile 0.95, got %v", recommendation.CpuOvercommit)
+	}
+	if recommendation.MemoryOvercommit != 0.25 {
+		t.Errorf("Expected the memory ratio to be 0.25, got %v", recommendation.MemoryOvercommit)
+	}
+	if recommendation.Samples != 100 {
+		t.Errorf("Expected 100 samples, got %d", recommendation.Samples)
+	}
+	if recommendation.Observed != 99*time.Minute {
+		t.Errorf("Expected the samples to span 99 minutes, got %s", recommendation.Observed)
+	}
+	if owners != nil {
+		t.Errorf("Expected no owner recommendations, got %v", owners)
+	}
+}
+
+func TestRecommendBoundsAndRoundsTheRatios(t *testing.T) {
+	pods := []corev1.Pod{testPod("apps", "web", nil, nil)}
+	now := time.Now()
+	samples := []Sample{
+		testSample("apps", "web", "1m", "1500Mi", now),
+		// Samples of other pods or containers are ignored
+		testSample("apps", "other", "900m", "1Mi", now),
+		{Namespace: "apps", Pod: "web", Container: "sidecar", Time: now},
+	}
+
+	recommendation, _ := Recommend(pods, samples, 95, false)
+
+	if recommendation.CpuOvercommit != minRatio {
+		t.Errorf("Expected the CPU ratio to be raised to %v, got %v", minRatio, recommendation.CpuOvercommit)
+	}
+	if recommendation.MemoryOvercommit != 1 {
+		t.Errorf("Expected the memory ratio to be bounded to 1, got %v", recommendation.MemoryOvercommit)
+	}
+	if recommendation.Samples != 1 {
+		t.Errorf("Expected only the sample of the pod to be counted, got %d", recommendation.Samples)
+	}
+}
+
+func TestRecommendWithoutSamples(t *testing.T) {
+	recommendation, _ := Recommend([]corev1.Pod{testPod("apps", "web", nil, nil)}, nil, 95, false)
+
+	if recommendation.CpuOvercommit != 0 || recommendation.MemoryOvercommit != 0 || recommendation.Samples != 0 {
+		t.Errorf("Expected no recommendation, got %+v", recommendation)
+	}
+}
+
+func TestRecommendByOwner(t *testing.T) {
+	controller := true
+	replicaSet := &metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-7d9f8", Controller: &controller}
+	statefulSet := &metav1.OwnerReference{Kind: "StatefulSet", Name: "db", Controller: &controller}
+	pods := []corev1.Pod{
+		testPod("apps", "web-7d9f8-a", replicaSet, map[string]string{"pod-template-hash": "7d9f8"}),
+		testPod("apps", "web-7d9f8-b", replicaSet, map[string]string{"pod-template-hash": "7d9f8"}),
+		testPod("apps", "db-0", statefulSet, nil),
+		testPod("apps", "debug", nil, nil),
+	}
+	now := time.Now()
+	samples := []Sample{
+		testSample("apps", "web-7d9f8-a", "200m", "", now),
+		testSample("apps", "web-7d9f8-b", "400m", "", now),
+		testSample("apps", "db-0", "800m", "512Mi", now),
+		testSample("apps", "debug", "", "128Mi", now),
+	}
+
+	recommendation, owners := Recommend(pods, samples, 100, true)
+
+	if recommendation.CpuOvercommit != 0.8 || recommendation.Samples != 4 {
+		t.Errorf("Expected the class to be recommended from every sample, got %+v", recommendation)
+	}
+	if len(owners) != 3 {
+		t.Fatalf("Expected 3 owners, got %v", owners)
+	}
+	expected := []struct {
+		kind, name string
+		cpu        float64
+		samples    int32
+	}{{"Deployment", "web", 0.4, 2}, {"Pod", "debug", 0, 1}, {"StatefulSet", "db", 0.8, 1}}
+	for i, owner := range owners {
+		if owner.Kind != expected[i].kind || owner.Name != expected[i].name || owner.CpuOvercommit != expected[i].cpu || owner.Samples != expected[i].samples {
+			t.Errorf("Expected owner %d to be %+v, got %+v", i, expected[i], owner)
+		}
+	}
+}
+
+func TestSamplesFromPodMetrics(t *testing.T) {
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: map[string]interface{}{
+		"metadata":  map[string]interface{}{"namespace": "apps", "name": "web"},
+		"timestamp": "2025-06-10T12:00:00Z",
+		"window":    "30s",
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "usage": map[string]interface{}{"cpu": "250m", "memory": "128Mi"}},
+			map[string]interface{}{"name": "sidecar", "usage": map[string]interface{}{"memory": "16Mi"}},
+		},
+	}}}}
+
+	samples, err := samplesFromPodMetrics(list)
+	if err != nil {
+		t.Fatalf("Expected the pod metrics to be read, got error '%v'", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("Expected a sample per container, got %v", samples)
+	}
+	if samples[0].CPU.MilliValue() != 250 || samples[0].Memory.Value() != 128*1024*1024 {
+		t.Errorf("Expected the usage of the app container, got %+v", samples[0])
+	}
+	if samples[1].CPU != nil {
+		t.Errorf("Expected no CPU usage for the sidecar, got %v", samples[1].CPU)
+	}
+	if !samples[0].Time.Equal(time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the time of the sample to be read, got %s", samples[0].Time)
+	}
+}
+
+func TestPrometheusSource(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+		value := `"0.25"`
+		if strings.Contains(query, "container_memory_working_set_bytes") {
+			value = `"268435456"`
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[`+
+			`{"metric":{"namespace":"apps","pod":"web","container":"app"},"values":[[1749556800,%s],[1749556860,"NaN"]]}]}}`, value)
+	}))
+	defer server.Close()
+
+	source := &PrometheusSource{URL: server.URL + "/"}
+	samples, err := source.Samples(context.Background(), []corev1.Pod{testPod("apps", "web", nil, nil), testPod("batch", "job", nil, nil)}, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected the usage to be read, got error '%v'", err)
+	}
+
+	if len(queries) != 2 || !strings.Contains(queries[0], `namespace=~"apps|batch"`) {
+		t.Errorf("Expected the CPU and memory of the namespaces of the pods to be queried, got %v", queries)
+	}
+	if len(samples) != 1 {
+		t.Fatalf("Expected the values that are not a number to be left out, got %v", samples)
+	}
+	if samples[0].CPU.MilliValue() != 250 || samples[0].Memory.Value() != 268435456 {
+		t.Errorf("Expected the CPU and memory of a point to be merged, got %+v", samples[0])
+	}
+}
+
+func TestPrometheusSourceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+	}))
+	defer server.Close()
+
+	source := &PrometheusSource{URL: server.URL}
+	if _, err := source.Samples(context.Background(), []corev1.Pod{testPod("apps", "web", nil, nil)}, time.Hour); err == nil || !strings.Contains(err.Error(), "parse error") {
+		t.Errorf("Expected the error of Prometheus to be returned, got '%v'", err)
+	}
+}
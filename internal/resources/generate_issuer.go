@@ -7,17 +7,17 @@
 package resources
 
 import (
-	"os"
-
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func GenerateIssuer() *certmanagerv1.Issuer {
+func GenerateIssuer(cfg config.Config) *certmanagerv1.Issuer {
 	return &certmanagerv1.Issuer{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "k8s-overcommit-issuer",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.PodNamespace,
 		},
 		Spec: certmanagerv1.IssuerSpec{
 			IssuerConfig: certmanagerv1.IssuerConfig{
@@ -26,3 +26,24 @@ func GenerateIssuer() *certmanagerv1.Issuer {
 		},
 	}
 }
+
+// CAInjectionAnnotation is the cert-manager annotation used to inject the CA bundle into webhook configurations.
+const CAInjectionAnnotation = "cert-manager.io/inject-ca-from"
+
+// SetMutatingWebhookCABundle replaces the cert-manager CA injection by a static CA bundle.
+// It is used when the operator manages the webhook certificates itself.
+func SetMutatingWebhookCABundle(webhookConfig *admissionv1.MutatingWebhookConfiguration, caBundle []byte) {
+	delete(webhookConfig.Annotations, CAInjectionAnnotation)
+	for i := range webhookConfig.Webhooks {
+		webhookConfig.Webhooks[i].ClientConfig.CABundle = caBundle
+	}
+}
+
+// SetValidatingWebhookCABundle replaces the cert-manager CA injection by a static CA bundle.
+// It is used when the operator manages the webhook certificates itself.
+func SetValidatingWebhookCABundle(webhookConfig *admissionv1.ValidatingWebhookConfiguration, caBundle []byte) {
+	delete(webhookConfig.Annotations, CAInjectionAnnotation)
+	for i := range webhookConfig.Webhooks {
+		webhookConfig.Webhooks[i].ClientConfig.CABundle = caBundle
+	}
+}
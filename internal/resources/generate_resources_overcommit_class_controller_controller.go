@@ -6,17 +6,20 @@
 package resources
 
 import (
-	"os"
+	"maps"
+	"strconv"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Overcommit) *appsv1.Deployment {
+func GenerateOvercommitClassControllerDeployment(cfg config.Config, overcommitObject overcommit.Overcommit) *appsv1.Deployment {
 	replicas := int32(1)
-	labels := overcommitObject.Spec.Labels
+	// Copy the labels, the same Overcommit spec is used to generate several deployments
+	labels := maps.Clone(overcommitObject.Spec.Labels)
 	if labels == nil {
 		labels = make(map[string]string)
 	}
@@ -31,7 +34,7 @@ func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Ove
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "k8s-overcommit-overcommitclass-controller",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.PodNamespace,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
@@ -46,11 +49,11 @@ func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Ove
 					Annotations: annotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: os.Getenv("SERVICE_ACCOUNT_NAME"),
+					ServiceAccountName: cfg.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:  "overcommit-controller",
-							Image: os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION"),
+							Image: cfg.Image(),
 							Args: []string{
 								"--metrics-bind-address=:8080",
 								"-metrics-secure=false",
@@ -60,25 +63,57 @@ func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Ove
 									Name:  "ENABLE_OVERCOMMIT_CLASS_CONTROLLER",
 									Value: "true",
 								},
+								{
+									Name:  "ENABLE_ROLLOUT_CONTROLLER",
+									Value: "true",
+								},
+								{
+									Name:  "ENABLE_DRIFT_CONTROLLER",
+									Value: "true",
+								},
+								{
+									Name:  "ENABLE_RESIZE_CONTROLLER",
+									Value: "true",
+								},
+								{
+									Name:  "ENABLE_RECOMMENDATION_CONTROLLER",
+									Value: strconv.FormatBool(overcommitObject.RecommendationsEnabled()),
+								},
+								{
+									Name:  "ENABLE_NODE_CONTROLLER",
+									Value: "true",
+								},
+								{
+									Name:  "ENABLE_QUOTA_CONTROLLER",
+									Value: strconv.FormatBool(overcommitObject.ManagesResourceQuotas()),
+								},
+								{
+									Name:  "ENABLE_REPORT_CONTROLLER",
+									Value: "true",
+								},
+								{
+									Name:  "ENABLE_CAPACITY_CONTROLLER",
+									Value: "true",
+								},
 								{
 									Name:  "IMAGE_REGISTRY",
-									Value: os.Getenv("IMAGE_REGISTRY"),
+									Value: cfg.ImageRegistry,
 								},
 								{
 									Name:  "IMAGE_REPOSITORY",
-									Value: os.Getenv("IMAGE_REPOSITORY"),
+									Value: cfg.ImageRepository,
 								},
 								{
 									Name:  "APP_VERSION",
-									Value: os.Getenv("APP_VERSION"),
+									Value: cfg.AppVersion,
 								},
 								{
 									Name:  "POD_NAMESPACE",
-									Value: os.Getenv("POD_NAMESPACE"),
+									Value: cfg.PodNamespace,
 								},
 								{
 									Name:  "SERVICE_ACCOUNT_NAME",
-									Value: os.Getenv("SERVICE_ACCOUNT_NAME"),
+									Value: cfg.ServiceAccountName,
 								},
 								{
 									Name: "POD_NAME",
@@ -91,7 +126,10 @@ func GenerateOvercommitClassControllerDeployment(overcommitObject overcommit.Ove
 							},
 							Ports: []corev1.ContainerPort{
 								{ContainerPort: 8080, Name: "metrics", Protocol: corev1.ProtocolTCP},
+								{ContainerPort: healthProbePort, Name: "health", Protocol: corev1.ProtocolTCP},
 							},
+							LivenessProbe:  healthProbe("/healthz", 15, 20),
+							ReadinessProbe: healthProbe("/readyz", 5, 10),
 						},
 					},
 					NodeSelector: overcommitObject.Spec.NodeSelector,
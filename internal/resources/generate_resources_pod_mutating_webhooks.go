@@ -6,10 +6,12 @@
 package resources
 
 import (
-	"os"
+	"maps"
 	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/exclusions"
 	certmanager "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certmanagermeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
@@ -20,20 +22,19 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func CreateDeployment(class overcommit.OvercommitClass) *appsv1.Deployment {
+func CreateDeployment(cfg config.Config, class overcommit.OvercommitClass) *appsv1.Deployment {
 	replicas := int32(1)
 
-	if class.Spec.Labels == nil {
-		class.Spec.Labels = make(map[string]string)
+	labels := maps.Clone(class.Spec.Labels)
+	if labels == nil {
+		labels = make(map[string]string)
 	}
-
-	labels := class.Spec.Labels
 	labels["app"] = class.Name + "-overcommit-webhook"
 
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      class.Name + "-overcommit-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.PodNamespace,
 			Labels: map[string]string{
 				"app": class.Name + "-overcommit-webhook",
 			},
@@ -51,33 +52,36 @@ func CreateDeployment(class overcommit.OvercommitClass) *appsv1.Deployment {
 					Annotations: class.Spec.Annotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: os.Getenv("SERVICE_ACCOUNT_NAME"),
+					ServiceAccountName: cfg.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:    "k8s-overcommit",
-							Image:   os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION"),
+							Image:   cfg.Image(),
 							Command: []string{"/manager"},
 							Args: []string{
 								"--metrics-bind-address=:8080",
 								"-metrics-secure=false",
 							},
 							Env: []corev1.EnvVar{
-								{Name: "APP_VERSION", Value: os.Getenv("APP_VERSION")},
+								{Name: "APP_VERSION", Value: cfg.AppVersion},
 								{Name: "WEBHOOK_CERT_DIR", Value: "/etc/webhook/config"},
 								{Name: "ENABLE_CONTROLLER", Value: "false"},
 								{Name: "ENABLE_POD_MUTATING_WEBHOOK", Value: "true"},
 								{Name: "OVERCOMMIT_CLASS_NAME", Value: class.Name},
-								{Name: "SERVICE_ACCOUNT_NAME", Value: os.Getenv("SERVICE_ACCOUNT_NAME")},
-								{Name: "POD_NAMESPACE", Value: os.Getenv("POD_NAMESPACE")},
+								{Name: "SERVICE_ACCOUNT_NAME", Value: cfg.ServiceAccountName},
+								{Name: "POD_NAMESPACE", Value: cfg.PodNamespace},
 								{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
 									FieldRef: &corev1.ObjectFieldSelector{
 										FieldPath: "metadata.name"},
 								}},
 							},
 							Ports: []corev1.ContainerPort{
-								{ContainerPort: 9443},
+								{ContainerPort: 9443, Name: "webhook", Protocol: corev1.ProtocolTCP},
 								{ContainerPort: 8080, Name: "metrics", Protocol: corev1.ProtocolTCP},
+								{ContainerPort: healthProbePort, Name: "health", Protocol: corev1.ProtocolTCP},
 							},
+							LivenessProbe:  healthProbe("/healthz", 15, 20),
+							ReadinessProbe: healthProbe("/readyz", 5, 10),
 							Resources: corev1.ResourceRequirements{
 								Requests: corev1.ResourceList{
 									corev1.ResourceMemory: resourceMustParse("64Mi"),
@@ -120,18 +124,35 @@ func resourceMustParse(value string) resource.Quantity {
 	return res
 }
 
-func CreateService(name string) *corev1.Service {
+// healthProbePort is the port of the health probe endpoints of the operator.
+const healthProbePort = 8081
+
+// healthProbe returns an HTTP probe of a health endpoint of the operator. The readiness endpoint fails until
+// the informer caches are synced, so the webhook services only route admission requests to synced pods.
+func healthProbe(path string, initialDelaySeconds, periodSeconds int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromInt32(healthProbePort)},
+		},
+		InitialDelaySeconds: initialDelaySeconds,
+		PeriodSeconds:       periodSeconds,
+	}
+}
+
+func CreateService(cfg config.Config, name string) *corev1.Service {
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name + "-webhook-service",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.PodNamespace,
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: map[string]string{"app": name + "-overcommit-webhook"},
 			Ports: []corev1.ServicePort{
 				{
+					Name:       "https",
 					Port:       443,
 					TargetPort: intstr.FromInt(9443),
+					Protocol:   corev1.ProtocolTCP,
 				},
 			},
 		},
@@ -142,7 +163,7 @@ func CreateCertificate(name string, svc corev1.Service) *certmanager.Certificate
 	return &certmanager.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name + "-webhook-certificate",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: svc.Namespace,
 		},
 		Spec: certmanager.CertificateSpec{
 			SecretName: name + "-webhook-secret",
@@ -175,16 +196,6 @@ func getSelectorClassNotExist(label string) *metav1.LabelSelector {
 	}
 }
 
-func getMatchCondition(excludedNamespaces string) []admissionv1.MatchCondition {
-	matchConditions := []admissionv1.MatchCondition{}
-	matchConditions = append(matchConditions, admissionv1.MatchCondition{
-		Name:       "exclude-namespaces",
-		Expression: "!object.metadata.namespace.matches('" + excludedNamespaces + "')",
-	})
-
-	return matchConditions
-}
-
 func getObjectSelector(isDefault bool, label string, name string) *metav1.LabelSelector {
 	if isDefault {
 		return getSelectorClassNotExist(label)
@@ -203,9 +214,9 @@ func getObjectSelector(isDefault bool, label string, name string) *metav1.LabelS
 func CreateMutatingWebhookConfiguration(class overcommit.OvercommitClass, svc corev1.Service, cert certmanager.Certificate, label string) *admissionv1.MutatingWebhookConfiguration {
 	var path = "/mutate--v1-pod"
 	var scope = admissionv1.NamespacedScope
-	var policy = admissionv1.Fail
 	var sideEffect = admissionv1.SideEffectClassNone
-	var reinvocationPolicy = admissionv1.IfNeededReinvocationPolicy
+	settings := class.Spec.Webhook.WithDefaults()
+	classExclusions := exclusions.ForClass(&class)
 
 	rules := []admissionv1.RuleWithOperations{
 		{
@@ -236,7 +247,7 @@ func CreateMutatingWebhookConfiguration(class overcommit.OvercommitClass, svc co
 		ObjectMeta: metav1.ObjectMeta{
 			Name: class.Name + "-overcommit-webhook",
 			Annotations: map[string]string{
-				"cert-manager.io/inject-ca-from": cert.Namespace + "/" + cert.Name,
+				CAInjectionAnnotation: cert.Namespace + "/" + cert.Name,
 			},
 		},
 		Webhooks: []admissionv1.MutatingWebhook{
@@ -251,10 +262,12 @@ func CreateMutatingWebhookConfiguration(class overcommit.OvercommitClass, svc co
 				},
 				Rules:                   rules,
 				AdmissionReviewVersions: []string{"v1"},
-				FailurePolicy:           &policy,
+				FailurePolicy:           &settings.FailurePolicy,
+				TimeoutSeconds:          &settings.TimeoutSeconds,
 				SideEffects:             &sideEffect,
-				ReinvocationPolicy:      &reinvocationPolicy,
-				MatchConditions:         getMatchCondition(class.Spec.ExcludedNamespaces),
+				ReinvocationPolicy:      &settings.ReinvocationPolicy,
+				MatchConditions:         classExclusions.MatchConditions(),
+				NamespaceSelector:       classExclusions.NamespaceSelector,
 				ObjectSelector:          getObjectSelector(false, label, class.Name),
 			},
 		},
@@ -272,10 +285,12 @@ func CreateMutatingWebhookConfiguration(class overcommit.OvercommitClass, svc co
 			},
 			Rules:                   rules,
 			AdmissionReviewVersions: []string{"v1"},
-			FailurePolicy:           &policy,
+			FailurePolicy:           &settings.FailurePolicy,
+			TimeoutSeconds:          &settings.TimeoutSeconds,
 			SideEffects:             &sideEffect,
-			ReinvocationPolicy:      &reinvocationPolicy,
-			MatchConditions:         getMatchCondition(class.Spec.ExcludedNamespaces),
+			ReinvocationPolicy:      &settings.ReinvocationPolicy,
+			MatchConditions:         classExclusions.MatchConditions(),
+			NamespaceSelector:       classExclusions.NamespaceSelector,
 			ObjectSelector:          getObjectSelector(class.Spec.IsDefault, label, class.Name),
 		})
 	}
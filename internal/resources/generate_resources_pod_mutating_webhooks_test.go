@@ -6,21 +6,27 @@
 package resources
 
 import (
-	"os"
+	"slices"
 	"testing"
 	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func TestCreateDeployment(t *testing.T) {
-	os.Setenv("POD_NAMESPACE", "test-namespace")
-	os.Setenv("IMAGE_REGISTRY", "test-registry")
-	os.Setenv("IMAGE_REPOSITORY", "test-repo")
-	os.Setenv("APP_VERSION", "v1.0.0")
+var testConfig = config.Config{
+	PodNamespace:       "test-namespace",
+	ImageRegistry:      "test-registry",
+	ImageRepository:    "test-repo",
+	AppVersion:         "v1.0.0",
+	ServiceAccountName: "test-service-account",
+}
 
+func TestCreateDeployment(t *testing.T) {
 	class := overcommit.OvercommitClass{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "test-class",
@@ -31,7 +37,7 @@ func TestCreateDeployment(t *testing.T) {
 		},
 	}
 
-	deployment := CreateDeployment(class)
+	deployment := CreateDeployment(testConfig, class)
 
 	if deployment.ObjectMeta.Name != "test-class-overcommit-webhook" {
 		t.Errorf("Expected deployment name 'test-class-overcommit-webhook', got '%s'", deployment.ObjectMeta.Name)
@@ -40,14 +46,17 @@ func TestCreateDeployment(t *testing.T) {
 	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 1 {
 		t.Errorf("Expected replicas to be 1, got '%v'", deployment.Spec.Replicas)
 	}
+
+	if deployment.Namespace != "test-namespace" {
+		t.Errorf("Expected deployment namespace 'test-namespace', got '%s'", deployment.Namespace)
+	}
+
+	if image := deployment.Spec.Template.Spec.Containers[0].Image; image != "test-registry/test-repo:v1.0.0" {
+		t.Errorf("Expected image 'test-registry/test-repo:v1.0.0', got '%s'", image)
+	}
 }
 
 func TestCreateDeploymentWithTolerationsAndNodeSelector(t *testing.T) {
-	os.Setenv("POD_NAMESPACE", "test-namespace")
-	os.Setenv("IMAGE_REGISTRY", "test-registry")
-	os.Setenv("IMAGE_REPOSITORY", "test-repo")
-	os.Setenv("APP_VERSION", "v1.0.0")
-
 	class := overcommit.OvercommitClass{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "test-class",
@@ -67,7 +76,7 @@ func TestCreateDeploymentWithTolerationsAndNodeSelector(t *testing.T) {
 		},
 	}
 
-	deployment := CreateDeployment(class)
+	deployment := CreateDeployment(testConfig, class)
 
 	if deployment.ObjectMeta.Name != "test-class-overcommit-webhook" {
 		t.Errorf("Expected deployment name 'test-class-overcommit-webhook', got '%s'", deployment.ObjectMeta.Name)
@@ -90,9 +99,7 @@ func TestCreateDeploymentWithTolerationsAndNodeSelector(t *testing.T) {
 }
 
 func TestCreateService(t *testing.T) {
-	os.Setenv("POD_NAMESPACE", "test-namespace")
-
-	service := CreateService("test-class")
+	service := CreateService(testConfig, "test-class")
 
 	if service.ObjectMeta.Name != "test-class-webhook-service" {
 		t.Errorf("Expected service name 'test-class-webhook-service', got '%s'", service.ObjectMeta.Name)
@@ -104,8 +111,6 @@ func TestCreateService(t *testing.T) {
 }
 
 func TestCreateCertificate(t *testing.T) {
-	os.Setenv("POD_NAMESPACE", "test-namespace")
-
 	service := corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-service",
@@ -129,6 +134,81 @@ func TestCreateCertificate(t *testing.T) {
 	}
 }
 
+func TestCreateMutatingWebhookConfigurationSettings(t *testing.T) {
+	class := overcommit.OvercommitClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-class",
+		},
+		Spec: overcommit.OvercommitClassSpec{
+			IsDefault: true,
+			Webhook: &overcommit.WebhookSettings{
+				FailurePolicy:  admissionv1.Ignore,
+				TimeoutSeconds: 3,
+			},
+		},
+	}
+	service := corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"}}
+	certificate := CreateCertificate("test-class", service)
+
+	webhookConfig := CreateMutatingWebhookConfiguration(class, service, *certificate, "inditex.com/overcommit-class")
+
+	if len(webhookConfig.Webhooks) != 2 {
+		t.Fatalf("Expected 2 webhooks for a default class, got '%d'", len(webhookConfig.Webhooks))
+	}
+	for _, webhook := range webhookConfig.Webhooks {
+		if *webhook.FailurePolicy != admissionv1.Ignore {
+			t.Errorf("Expected failure policy 'Ignore', got '%s'", *webhook.FailurePolicy)
+		}
+		if *webhook.TimeoutSeconds != 3 {
+			t.Errorf("Expected timeout '3', got '%d'", *webhook.TimeoutSeconds)
+		}
+		if *webhook.ReinvocationPolicy != admissionv1.IfNeededReinvocationPolicy {
+			t.Errorf("Expected default reinvocation policy 'IfNeeded', got '%s'", *webhook.ReinvocationPolicy)
+		}
+	}
+}
+
+func TestGeneratePodValidatingWebhookConfigurationScope(t *testing.T) {
+	deployment := GeneratePodValidatingDeployment(testConfig, overcommit.Overcommit{})
+	service := GeneratePodValidatingService(*deployment)
+	certificate := GenerateCertificateValidatingPods(*GenerateIssuer(testConfig), *service)
+
+	webhook := GeneratePodValidatingWebhookConfiguration(*deployment, *service, *certificate, nil).Webhooks[0]
+	if *webhook.FailurePolicy != admissionv1.Ignore {
+		t.Errorf("Expected default failure policy 'Ignore', got '%s'", *webhook.FailurePolicy)
+	}
+	if webhook.NamespaceSelector == nil || len(webhook.NamespaceSelector.MatchExpressions) != 1 {
+		t.Fatalf("Expected a namespace selector leaving out the system namespaces, got '%v'", webhook.NamespaceSelector)
+	}
+	requirement := webhook.NamespaceSelector.MatchExpressions[0]
+	if requirement.Key != corev1.LabelMetadataName || requirement.Operator != metav1.LabelSelectorOpNotIn || !slices.Contains(requirement.Values, "kube-system") {
+		t.Errorf("Expected kube-system to be left out, got '%v'", requirement)
+	}
+	if len(webhook.MatchConditions) != 1 || webhook.MatchConditions[0].Expression != "object.metadata.namespace != '"+deployment.Namespace+"'" {
+		t.Errorf("Expected the operator namespace to be left out by its exact name, got '%v'", webhook.MatchConditions)
+	}
+
+	webhook = GeneratePodValidatingWebhookConfiguration(*deployment, *service, *certificate, &overcommit.ValidatingWebhookSettings{FailurePolicy: admissionv1.Fail}).Webhooks[0]
+	if *webhook.FailurePolicy != admissionv1.Fail {
+		t.Errorf("Expected failure policy 'Fail', got '%s'", *webhook.FailurePolicy)
+	}
+}
+
+func TestGenerateOvercommitClassValidatingWebhookConfigurationSettings(t *testing.T) {
+	deployment := GenerateOvercommitClassValidatingDeployment(testConfig, overcommit.Overcommit{})
+	service := GenerateOvercommitClassValidatingService(*deployment)
+	certificate := GenerateCertificateValidatingOvercommitClass(*GenerateIssuer(testConfig), *service)
+
+	webhook := GenerateOvercommitClassValidatingWebhookConfiguration(*deployment, *service, *certificate, nil).Webhooks[0]
+	if *webhook.FailurePolicy != admissionv1.Fail || *webhook.TimeoutSeconds != 10 {
+		t.Errorf("Expected default failure policy 'Fail' and timeout '10', got '%s' and '%d'", *webhook.FailurePolicy, *webhook.TimeoutSeconds)
+	}
+	webhook = GenerateOvercommitClassValidatingWebhookConfiguration(*deployment, *service, *certificate, &overcommit.ValidatingWebhookSettings{TimeoutSeconds: 3}).Webhooks[0]
+	if *webhook.FailurePolicy != admissionv1.Fail || *webhook.TimeoutSeconds != 3 {
+		t.Errorf("Expected failure policy 'Fail' and timeout '3', got '%s' and '%d'", *webhook.FailurePolicy, *webhook.TimeoutSeconds)
+	}
+}
+
 func TestResourceMustParse(t *testing.T) {
 	memory := resourceMustParse("64Mi")
 	if memory.String() != "64Mi" {
@@ -140,3 +220,55 @@ func TestResourceMustParse(t *testing.T) {
 		t.Errorf("Expected '250m', got '%s'", cpu.String())
 	}
 }
+
+func TestGeneratedDeploymentsReadinessProbe(t *testing.T) {
+	deployments := []*appsv1.Deployment{
+		CreateDeployment(testConfig, overcommit.OvercommitClass{ObjectMeta: metav1.ObjectMeta{Name: "test-class"}}),
+		GeneratePodValidatingDeployment(testConfig, overcommit.Overcommit{}),
+		GenerateOvercommitClassValidatingDeployment(testConfig, overcommit.Overcommit{}),
+		GenerateOvercommitClassControllerDeployment(testConfig, overcommit.Overcommit{}),
+	}
+	for _, deployment := range deployments {
+		probe := deployment.Spec.Template.Spec.Containers[0].ReadinessProbe
+		if probe == nil || probe.HTTPGet == nil {
+			t.Errorf("Expected deployment %s to have an HTTP readiness probe, got '%v'", deployment.Name, probe)
+			continue
+		}
+		if probe.HTTPGet.Path != "/readyz" || probe.HTTPGet.Port.IntValue() != healthProbePort {
+			t.Errorf("Expected deployment %s readiness probe on /readyz:%d, got '%s:%s'", deployment.Name, healthProbePort, probe.HTTPGet.Path, probe.HTTPGet.Port.String())
+		}
+	}
+}
+
+func TestGenerateOvercommitClassControllerDeploymentRecommendations(t *testing.T) {
+	env := func(overcommitObject overcommit.Overcommit) map[string]string {
+		values := map[string]string{}
+		for _, variable := range GenerateOvercommitClassControllerDeployment(testConfig, overcommitObject).Spec.Template.Spec.Containers[0].Env {
+			values[variable.Name] = variable.Value
+		}
+		return values
+	}
+
+	values := env(overcommit.Overcommit{})
+	if values["ENABLE_RECOMMENDATION_CONTROLLER"] != "false" {
+		t.Errorf("Expected the recommendation controller to be disabled by default, got '%s'", values["ENABLE_RECOMMENDATION_CONTROLLER"])
+	}
+	if values["ENABLE_DRIFT_CONTROLLER"] != "true" {
+		t.Errorf("Expected the drift controller to be enabled, got '%s'", values["ENABLE_DRIFT_CONTROLLER"])
+	}
+
+	values = env(overcommit.Overcommit{Spec: overcommit.OvercommitSpec{Recommendations: &overcommit.RecommendationPolicy{Enabled: true}}})
+	if values["ENABLE_RECOMMENDATION_CONTROLLER"] != "true" {
+		t.Errorf("Expected the recommendation controller to be enabled, got '%s'", values["ENABLE_RECOMMENDATION_CONTROLLER"])
+	}
+
+	if values = env(overcommit.Overcommit{}); values["ENABLE_QUOTA_CONTROLLER"] != "false" {
+		t.Errorf("Expected the quota controller to be disabled without a resourceQuotas policy, got '%s'", values["ENABLE_QUOTA_CONTROLLER"])
+	}
+
+	// The quota controller keeps running with a disabled policy, to restore the quotas
+	values = env(overcommit.Overcommit{Spec: overcommit.OvercommitSpec{ResourceQuotas: &overcommit.ResourceQuotaPolicy{}}})
+	if values["ENABLE_QUOTA_CONTROLLER"] != "true" {
+		t.Errorf("Expected the quota controller to be enabled by the resourceQuotas policy, got '%s'", values["ENABLE_QUOTA_CONTROLLER"])
+	}
+}
@@ -6,10 +6,12 @@
 package resources
 
 import (
-	"os"
+	"maps"
 	"time"
 
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/exclusions"
 	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	certmanagermeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
@@ -23,7 +25,7 @@ func GenerateCertificateValidatingPods(issuer certmanagerv1.Issuer, svc corev1.S
 	return &certmanagerv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "pod-validating-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: issuer.Namespace,
 		},
 		Spec: certmanagerv1.CertificateSpec{
 			SecretName: "pod-validating-webhook",
@@ -44,9 +46,10 @@ func GenerateCertificateValidatingPods(issuer certmanagerv1.Issuer, svc corev1.S
 	}
 }
 
-func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *appsv1.Deployment {
+func GeneratePodValidatingDeployment(cfg config.Config, overcommitObject overcommit.Overcommit) *appsv1.Deployment {
 	replicas := int32(1)
-	labels := overcommitObject.Spec.Labels
+	// Copy the labels, the same Overcommit spec is used to generate several deployments
+	labels := maps.Clone(overcommitObject.Spec.Labels)
 	if labels == nil {
 		labels = make(map[string]string)
 	}
@@ -54,7 +57,7 @@ func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *ap
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "k8s-overcommit-pod-validating-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.PodNamespace,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
@@ -69,11 +72,11 @@ func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *ap
 					Annotations: overcommitObject.Spec.Annotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: os.Getenv("SERVICE_ACCOUNT_NAME"),
+					ServiceAccountName: cfg.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:  "k8s-overcommit-pod-validating-webhook",
-							Image: os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION"),
+							Image: cfg.Image(),
 							Args: []string{
 								"--metrics-bind-address=:8080",
 								"-metrics-secure=false",
@@ -85,15 +88,15 @@ func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *ap
 								},
 								{
 									Name:  "IMAGE_REGISTRY",
-									Value: os.Getenv("IMAGE_REGISTRY"),
+									Value: cfg.ImageRegistry,
 								},
 								{
 									Name:  "IMAGE_REPOSITORY",
-									Value: os.Getenv("IMAGE_REPOSITORY"),
+									Value: cfg.ImageRepository,
 								},
 								{
 									Name:  "APP_VERSION",
-									Value: os.Getenv("APP_VERSION"),
+									Value: cfg.AppVersion,
 								},
 								{
 									Name:  "WEBHOOK_CERT_DIR",
@@ -101,11 +104,11 @@ func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *ap
 								},
 								{
 									Name:  "SERVICE_ACCOUNT_NAME",
-									Value: os.Getenv("SERVICE_ACCOUNT_NAME"),
+									Value: cfg.ServiceAccountName,
 								},
 								{
 									Name:  "POD_NAMESPACE",
-									Value: os.Getenv("POD_NAMESPACE"),
+									Value: cfg.PodNamespace,
 								},
 								{
 									Name: "POD_NAME",
@@ -116,9 +119,12 @@ func GeneratePodValidatingDeployment(overcommitObject overcommit.Overcommit) *ap
 								},
 							},
 							Ports: []corev1.ContainerPort{
-								{ContainerPort: 9443},
+								{ContainerPort: 9443, Name: "webhook", Protocol: corev1.ProtocolTCP},
 								{ContainerPort: 8080, Name: "metrics", Protocol: corev1.ProtocolTCP},
+								{ContainerPort: healthProbePort, Name: "health", Protocol: corev1.ProtocolTCP},
 							},
+							LivenessProbe:  healthProbe("/healthz", 15, 20),
+							ReadinessProbe: healthProbe("/readyz", 5, 10),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "webhook-cert",
@@ -168,15 +174,22 @@ func GeneratePodValidatingService(deployment appsv1.Deployment) *corev1.Service
 	}
 }
 
-func GeneratePodValidatingWebhookConfiguration(deployment appsv1.Deployment, service corev1.Service, certificate certmanagerv1.Certificate, label string) *admissionv1.ValidatingWebhookConfiguration {
-	var policy = admissionv1.Fail
+// systemNamespaces are the namespaces of the control plane, whose pods are never validated so that an outage of the
+// webhook cannot block them.
+var systemNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// GeneratePodValidatingWebhookConfiguration validates the pods of every namespace but the system ones, as pods may
+// inherit their class from the namespace or the default class without carrying the class label. As it sees most
+// pods of the cluster, the webhook ignores the requests it cannot answer unless a failure policy is set.
+func GeneratePodValidatingWebhookConfiguration(deployment appsv1.Deployment, service corev1.Service, certificate certmanagerv1.Certificate, webhookSettings *overcommit.ValidatingWebhookSettings) *admissionv1.ValidatingWebhookConfiguration {
+	settings := webhookSettings.WithDefaults(admissionv1.Ignore)
 	var sideEffects = admissionv1.SideEffectClassNone
 	var path = "/validate--v1-pod"
 	return &admissionv1.ValidatingWebhookConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: deployment.Name,
 			Annotations: map[string]string{
-				"cert-manager.io/inject-ca-from": certificate.Namespace + "/" + certificate.Name,
+				CAInjectionAnnotation: certificate.Namespace + "/" + certificate.Name,
 			},
 		},
 		Webhooks: []admissionv1.ValidatingWebhook{
@@ -199,21 +212,23 @@ func GeneratePodValidatingWebhookConfiguration(deployment appsv1.Deployment, ser
 						},
 					},
 				},
-				FailurePolicy:           &policy,
+				FailurePolicy:           &settings.FailurePolicy,
+				TimeoutSeconds:          &settings.TimeoutSeconds,
 				SideEffects:             &sideEffects,
 				AdmissionReviewVersions: []string{"v1"},
-				ObjectSelector: &metav1.LabelSelector{
+				NamespaceSelector: &metav1.LabelSelector{
 					MatchExpressions: []metav1.LabelSelectorRequirement{
 						{
-							Key:      label,
-							Operator: metav1.LabelSelectorOpExists,
+							Key:      corev1.LabelMetadataName,
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   systemNamespaces,
 						},
 					},
 				},
 				MatchConditions: []admissionv1.MatchCondition{
 					{
 						Name:       "exclude-operator-namespace",
-						Expression: "!object.metadata.namespace.matches('" + os.Getenv("POD_NAMESPACE") + "')",
+						Expression: "object.metadata.namespace != " + exclusions.Quote(deployment.Namespace),
 					},
 				},
 			},
@@ -221,9 +236,10 @@ func GeneratePodValidatingWebhookConfiguration(deployment appsv1.Deployment, ser
 	}
 }
 
-func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Overcommit) *appsv1.Deployment {
+func GenerateOvercommitClassValidatingDeployment(cfg config.Config, overcommitObject overcommit.Overcommit) *appsv1.Deployment {
 	replicas := int32(1)
-	labels := overcommitObject.Spec.Labels
+	// Copy the labels, the same Overcommit spec is used to generate several deployments
+	labels := maps.Clone(overcommitObject.Spec.Labels)
 	if labels == nil {
 		labels = make(map[string]string)
 	}
@@ -231,7 +247,7 @@ func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Ove
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "k8s-overcommit-class-validating-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: cfg.PodNamespace,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
@@ -246,11 +262,11 @@ func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Ove
 					Annotations: overcommitObject.Spec.Annotations,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: os.Getenv("SERVICE_ACCOUNT_NAME"),
+					ServiceAccountName: cfg.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
 							Name:  "k8s-overcommit-class-validating-webhook",
-							Image: os.Getenv("IMAGE_REGISTRY") + "/" + os.Getenv("IMAGE_REPOSITORY") + ":" + os.Getenv("APP_VERSION"),
+							Image: cfg.Image(),
 							Args: []string{
 								"--metrics-bind-address=:8080",
 								"-metrics-secure=false",
@@ -262,15 +278,15 @@ func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Ove
 								},
 								{
 									Name:  "IMAGE_REGISTRY",
-									Value: os.Getenv("IMAGE_REGISTRY"),
+									Value: cfg.ImageRegistry,
 								},
 								{
 									Name:  "IMAGE_REPOSITORY",
-									Value: os.Getenv("IMAGE_REPOSITORY"),
+									Value: cfg.ImageRepository,
 								},
 								{
 									Name:  "APP_VERSION",
-									Value: os.Getenv("APP_VERSION"),
+									Value: cfg.AppVersion,
 								},
 								{
 									Name:  "WEBHOOK_CERT_DIR",
@@ -285,13 +301,16 @@ func GenerateOvercommitClassValidatingDeployment(overcommitObject overcommit.Ove
 								},
 								{
 									Name:  "POD_NAMESPACE",
-									Value: os.Getenv("POD_NAMESPACE"),
+									Value: cfg.PodNamespace,
 								},
 							},
 							Ports: []corev1.ContainerPort{
-								{ContainerPort: 9443},
+								{ContainerPort: 9443, Name: "webhook", Protocol: corev1.ProtocolTCP},
 								{ContainerPort: 8080, Name: "metrics", Protocol: corev1.ProtocolTCP},
+								{ContainerPort: healthProbePort, Name: "health", Protocol: corev1.ProtocolTCP},
 							},
+							LivenessProbe:  healthProbe("/healthz", 15, 20),
+							ReadinessProbe: healthProbe("/readyz", 5, 10),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "webhook-cert",
@@ -345,7 +364,7 @@ func GenerateCertificateValidatingOvercommitClass(issuer certmanagerv1.Issuer, s
 	return &certmanagerv1.Certificate{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "oc-validating-webhook",
-			Namespace: os.Getenv("POD_NAMESPACE"),
+			Namespace: issuer.Namespace,
 		},
 		Spec: certmanagerv1.CertificateSpec{
 			SecretName: "oc-validating-webhook",
@@ -366,8 +385,8 @@ func GenerateCertificateValidatingOvercommitClass(issuer certmanagerv1.Issuer, s
 	}
 }
 
-func GenerateOvercommitClassValidatingWebhookConfiguration(deployment appsv1.Deployment, service corev1.Service, certificate certmanagerv1.Certificate) *admissionv1.ValidatingWebhookConfiguration {
-	var policy = admissionv1.Fail
+func GenerateOvercommitClassValidatingWebhookConfiguration(deployment appsv1.Deployment, service corev1.Service, certificate certmanagerv1.Certificate, webhookSettings *overcommit.ValidatingWebhookSettings) *admissionv1.ValidatingWebhookConfiguration {
+	settings := webhookSettings.WithDefaults(admissionv1.Fail)
 	var sideEffects = admissionv1.SideEffectClassNone
 	var path = "/validate-overcommit-inditex-dev-v1alphav1-overcommitclass"
 
@@ -375,7 +394,7 @@ func GenerateOvercommitClassValidatingWebhookConfiguration(deployment appsv1.Dep
 		ObjectMeta: metav1.ObjectMeta{
 			Name: deployment.Name,
 			Annotations: map[string]string{
-				"cert-manager.io/inject-ca-from": certificate.Namespace + "/" + certificate.Name,
+				CAInjectionAnnotation: certificate.Namespace + "/" + certificate.Name,
 			},
 		},
 		Webhooks: []admissionv1.ValidatingWebhook{
@@ -390,7 +409,7 @@ func GenerateOvercommitClassValidatingWebhookConfiguration(deployment appsv1.Dep
 				},
 				Rules: []admissionv1.RuleWithOperations{
 					{
-						Operations: []admissionv1.OperationType{"CREATE", "UPDATE"},
+						Operations: []admissionv1.OperationType{"CREATE", "UPDATE", "DELETE"},
 						Rule: admissionv1.Rule{
 							APIGroups:   []string{"overcommit.inditex.dev"},
 							APIVersions: []string{"v1alphav1"},
@@ -398,7 +417,8 @@ func GenerateOvercommitClassValidatingWebhookConfiguration(deployment appsv1.Dep
 						},
 					},
 				},
-				FailurePolicy:           &policy,
+				FailurePolicy:           &settings.FailurePolicy,
+				TimeoutSeconds:          &settings.TimeoutSeconds,
 				SideEffects:             &sideEffects,
 				AdmissionReviewVersions: []string{"v1"},
 			},
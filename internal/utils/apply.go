@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// FieldManager is the field manager used by the operator to apply the generated resources.
+	FieldManager = "k8s-overcommit-operator"
+	// ManagedByLabel marks the generated resources with FieldManager, so they can be found on cleanup.
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+)
+
+// Apply converges obj to its generated state with server-side apply, setting owner as its controller when not nil.
+// Only the fields present in obj are owned by the operator, so fields managed by others (like the caBundle
+// injected by cert-manager) are left untouched. On success obj holds the object returned by the API server.
+func Apply(ctx context.Context, c client.Client, owner client.Object, obj client.Object) error {
+	if owner != nil {
+		if err := controllerutil.SetControllerReference(owner, obj, c.Scheme()); err != nil {
+			return err
+		}
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ManagedByLabel] = FieldManager
+	obj.SetLabels(labels)
+
+	applyConfig, err := ToApplyConfiguration(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+
+	if err := c.Apply(ctx, client.ApplyConfigurationFromUnstructured(applyConfig), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(applyConfig.Object, obj)
+}
+
+// ToApplyConfiguration converts a generated object into the unstructured apply configuration sent to the API server.
+// Server populated fields and null values are dropped so the operator does not claim ownership of them.
+func ToApplyConfiguration(obj client.Object, scheme *runtime.Scheme) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the kind of %T: %w", obj, err)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	applyConfig := &unstructured.Unstructured{Object: pruneNulls(content)}
+	applyConfig.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(applyConfig.Object, "status")
+	unstructured.RemoveNestedField(applyConfig.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(applyConfig.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(applyConfig.Object, "metadata", "uid")
+
+	return applyConfig, nil
+}
+
+// pruneNulls removes the null values left by the conversion of unset fields, such as creationTimestamp.
+func pruneNulls(content map[string]interface{}) map[string]interface{} {
+	for key, value := range content {
+		switch typed := value.(type) {
+		case nil:
+			delete(content, key)
+		case map[string]interface{}:
+			content[key] = pruneNulls(typed)
+		case []interface{}:
+			for i, item := range typed {
+				if itemMap, ok := item.(map[string]interface{}); ok {
+					typed[i] = pruneNulls(itemMap)
+				}
+			}
+		}
+	}
+	return content
+}
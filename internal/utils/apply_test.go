@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestToApplyConfiguration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("Expected scheme to be built, got error '%v'", err)
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-service",
+			Namespace:       "test-namespace",
+			ResourceVersion: "42",
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "test"},
+		},
+	}
+
+	applyConfig, err := ToApplyConfiguration(service, scheme)
+	if err != nil {
+		t.Fatalf("Expected apply configuration, got error '%v'", err)
+	}
+
+	if applyConfig.GetKind() != "Service" || applyConfig.GetAPIVersion() != "v1" {
+		t.Errorf("Expected kind 'v1/Service', got '%v/%v'", applyConfig.GetAPIVersion(), applyConfig.GetKind())
+	}
+	if applyConfig.GetResourceVersion() != "" {
+		t.Errorf("Expected resourceVersion to be dropped, got '%v'", applyConfig.GetResourceVersion())
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(applyConfig.Object, "metadata", "creationTimestamp"); found {
+		t.Errorf("Expected null creationTimestamp to be dropped")
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(applyConfig.Object, "status"); found {
+		t.Errorf("Expected status to be dropped")
+	}
+	if selector, _, _ := unstructured.NestedStringMap(applyConfig.Object, "spec", "selector"); selector["app"] != "test" {
+		t.Errorf("Expected selector to be kept, got '%v'", selector)
+	}
+}
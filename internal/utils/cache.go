@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// cacheSyncTimeout bounds how long a readiness probe waits for the informers to sync.
+const cacheSyncTimeout = time.Second
+
+// cachedOwnerKinds are the owners of pods resolved from metadata-only informers. The owners of other kinds
+// are read from the API server, so that no informer is started for kinds the operator may not be allowed to watch.
+var cachedOwnerKinds = map[schema.GroupVersionKind]bool{
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"}:  true,
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"}: true,
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"}:   true,
+	{Group: "batch", Version: "v1", Kind: "Job"}:        true,
+	{Group: "batch", Version: "v1", Kind: "CronJob"}:    true,
+}
+
+// RegisterAdmissionInformers registers the informers serving the lookups of the pod webhooks, so they are
+// started and synced with the manager instead of lazily on the first admission request.
+func RegisterAdmissionInformers(ctx context.Context, informers cache.Informers) error {
+	objects := []client.Object{
+		&overcommit.Overcommit{},
+		&overcommit.OvercommitClass{},
+		&corev1.Namespace{},
+	}
+	for gvk := range cachedOwnerKinds {
+		owner := &metav1.PartialObjectMetadata{}
+		owner.SetGroupVersionKind(gvk)
+		objects = append(objects, owner)
+	}
+	for _, obj := range objects {
+		if _, err := informers.GetInformer(ctx, obj); err != nil {
+			return fmt.Errorf("error registering the informer for %T: %w", obj, err)
+		}
+	}
+	return nil
+}
+
+// CacheSyncedChecker is a readiness check failing until every informer of the cache has synced, so no
+// admission request nor reconciliation is served from a partially filled cache.
+func CacheSyncedChecker(informers cache.Informers) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+		if !informers.WaitForCacheSync(ctx) {
+			return errors.New("informer caches are not synced")
+		}
+		return nil
+	}
+}
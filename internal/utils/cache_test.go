@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+func TestCacheSyncedChecker(t *testing.T) {
+	synced := false
+	informers := &informertest.FakeInformers{Synced: &synced}
+	checker := CacheSyncedChecker(informers)
+
+	if err := checker(httptest.NewRequest("GET", "/readyz", nil)); err == nil {
+		t.Error("Expected the check to fail while the caches are not synced")
+	}
+
+	synced = true
+	if err := checker(httptest.NewRequest("GET", "/readyz", nil)); err != nil {
+		t.Errorf("Expected the check to pass once the caches are synced, got '%v'", err)
+	}
+}
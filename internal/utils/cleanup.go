@@ -8,38 +8,166 @@ package utils
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 )
 
-func DeleteResources(ctx context.Context, k8sClient client.Client) error {
-	var nameValidatingPodWebhook = "k8s-overcommit-pod-validating-webhook-webhook"
-	var nameValidatingOCWebhook = "k8s-overcommit-class-validating-webhook-webhook"
+// CleanupFinalizer holds the deletion of the Overcommit until every generated object has been removed.
+const CleanupFinalizer = "overcommit.inditex.dev/cleanup"
 
-	// Delete Pod Validating Webhook Configuration
-	podWebhook := &admissionregistrationv1.ValidatingWebhookConfiguration{}
-	if err := k8sClient.Get(ctx, client.ObjectKey{Name: nameValidatingPodWebhook}, podWebhook); err != nil {
-		if client.IgnoreNotFound(err) != nil {
-			return err
+const (
+	// stopPollInterval is how often the pods of the stopped deployments are checked.
+	stopPollInterval = 2 * time.Second
+	// stopTimeout is how long the pods of the stopped deployments are waited for.
+	stopTimeout = 5 * time.Minute
+)
+
+// StopDeployments scales the operator deployment and the deployments generated by it down to zero replicas and
+// waits until their pods are gone, so that no controller recreates the generated objects or the cleanup
+// finalizer while they are removed. The operator deployment may not exist.
+func StopDeployments(ctx context.Context, k8sClient client.Client, namespace, operatorDeployment string) error {
+	deployments := &appsv1.DeploymentList{}
+	if err := k8sClient.List(ctx, deployments, client.InNamespace(namespace), client.MatchingLabels{ManagedByLabel: FieldManager}); err != nil {
+		return fmt.Errorf("error listing deployments: %w", err)
+	}
+	operator := appsv1.Deployment{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: operatorDeployment}, &operator); err == nil {
+		// The operator goes first, it would otherwise scale the generated deployments back up
+		deployments.Items = append([]appsv1.Deployment{operator}, deployments.Items...)
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error getting the operator deployment %s: %w", operatorDeployment, err)
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		podlog.Info("Scaling down deployment", "name", deployment.Name, "namespace", deployment.Namespace)
+		patch := client.MergeFrom(deployment.DeepCopy())
+		deployment.Spec.Replicas = ptr.To[int32](0)
+		if err := k8sClient.Patch(ctx, deployment, patch); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("error scaling down deployment %s: %w", deployment.Name, err)
 		}
-	} else {
-		if err := k8sClient.Delete(ctx, podWebhook); err != nil {
+	}
+	for i := range deployments.Items {
+		if err := waitForPods(ctx, k8sClient, &deployments.Items[i]); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	// Delete Overcommit Class Validating Webhook Configuration
-	ocWebhook := &admissionregistrationv1.ValidatingWebhookConfiguration{}
-	if err := k8sClient.Get(ctx, client.ObjectKey{Name: nameValidatingOCWebhook}, ocWebhook); err != nil {
-		if client.IgnoreNotFound(err) != nil {
-			return err
+// waitForPods waits until the deployment has no pods left, terminating ones included.
+func waitForPods(ctx context.Context, k8sClient client.Client, deployment *appsv1.Deployment) error {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("error reading the selector of deployment %s: %w", deployment.Name, err)
+	}
+	err = wait.PollUntilContextTimeout(ctx, stopPollInterval, stopTimeout, true, func(ctx context.Context) (bool, error) {
+		pods := &corev1.PodList{}
+		if err := k8sClient.List(ctx, pods, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return false, err
 		}
-	} else {
-		if err := k8sClient.Delete(ctx, ocWebhook); err != nil {
-			return err
+		return len(pods.Items) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error waiting for the pods of deployment %s to stop: %w", deployment.Name, err)
+	}
+	return nil
+}
+
+// DeleteResources tears down every object generated by the operator, found by its managed-by label.
+// The webhook configurations go first, so the API server never calls a webhook whose service is already
+// gone, then the workloads serving them and finally the certificates and the issuer.
+func DeleteResources(ctx context.Context, k8sClient client.Client, namespace string) error {
+	inNamespace := client.InNamespace(namespace)
+
+	if err := deleteManaged(ctx, k8sClient, &admissionregistrationv1.MutatingWebhookConfigurationList{}); err != nil {
+		return fmt.Errorf("error deleting mutating webhook configurations: %w", err)
+	}
+	if err := deleteManaged(ctx, k8sClient, &admissionregistrationv1.ValidatingWebhookConfigurationList{}); err != nil {
+		return fmt.Errorf("error deleting validating webhook configurations: %w", err)
+	}
+
+	if err := deleteManaged(ctx, k8sClient, &appsv1.DeploymentList{}, inNamespace); err != nil {
+		return fmt.Errorf("error deleting deployments: %w", err)
+	}
+	if err := deleteManaged(ctx, k8sClient, &corev1.ServiceList{}, inNamespace); err != nil {
+		return fmt.Errorf("error deleting services: %w", err)
+	}
+
+	// cert-manager does not delete the Secrets of the Certificates, and may not even be installed
+	certificates := &certmanagerv1.CertificateList{}
+	if err := k8sClient.List(ctx, certificates, inNamespace, client.MatchingLabels{ManagedByLabel: FieldManager}); err != nil && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("error listing certificates: %w", err)
+	}
+	for i := range certificates.Items {
+		secret := &corev1.Secret{}
+		secret.Name = certificates.Items[i].Spec.SecretName
+		secret.Namespace = certificates.Items[i].Namespace
+		for _, obj := range []client.Object{&certificates.Items[i], secret} {
+			if err := k8sClient.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("error deleting certificate %s: %w", certificates.Items[i].Name, err)
+			}
+		}
+	}
+	if err := deleteManaged(ctx, k8sClient, &corev1.SecretList{}, inNamespace); err != nil {
+		return fmt.Errorf("error deleting secrets: %w", err)
+	}
+	if err := deleteManaged(ctx, k8sClient, &certmanagerv1.IssuerList{}, inNamespace); err != nil {
+		return fmt.Errorf("error deleting issuers: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveCleanupFinalizer releases the Overcommit once its resources have been removed without the operator running.
+// The operator must be stopped first, it would otherwise add the finalizer back.
+func RemoveCleanupFinalizer(ctx context.Context, k8sClient client.Client) error {
+	overcommitObject := &overcommit.Overcommit{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: "cluster"}, overcommitObject); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
 		}
+		return err
 	}
 
+	if controllerutil.RemoveFinalizer(overcommitObject, CleanupFinalizer) {
+		return k8sClient.Update(ctx, overcommitObject)
+	}
 	return nil
 }
+
+// deleteManaged deletes every object of the list kind carrying the managed-by label of the operator.
+func deleteManaged(ctx context.Context, k8sClient client.Client, list client.ObjectList, opts ...client.ListOption) error {
+	opts = append(opts, client.MatchingLabels{ManagedByLabel: FieldManager})
+	if err := k8sClient.List(ctx, list, opts...); err != nil {
+		// The kind is not served, e.g. cert-manager is not installed
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	return meta.EachListItem(list, func(item runtime.Object) error {
+		obj, ok := item.(client.Object)
+		if !ok {
+			return fmt.Errorf("unexpected list item %T", item)
+		}
+		podlog.Info("Deleting generated resource", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return client.IgnoreNotFound(k8sClient.Delete(ctx, obj))
+	})
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("DeleteResources", func() {
+	It("should delete only the resources generated by the operator", func() {
+		managed := map[string]string{ManagedByLabel: FieldManager}
+
+		webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-overcommit-webhook", Labels: managed},
+		}
+		generatedService := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-webhook-service", Namespace: "default", Labels: managed},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 443}}},
+		}
+		userService := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-user-service", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 443}}},
+		}
+		for _, obj := range []client.Object{webhookConfig, generatedService, userService} {
+			Expect(k8sClient.Create(context.Background(), obj)).To(Succeed())
+		}
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), userService))).To(Succeed())
+		})
+
+		// cert-manager is not installed in the test environment, its kinds must be skipped
+		Expect(DeleteResources(context.Background(), k8sClient, "default")).To(Succeed())
+
+		err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(webhookConfig), webhookConfig)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "Mutating webhook configuration should be deleted")
+		err = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(generatedService), generatedService)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "Generated service should be deleted")
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(userService), userService)).To(Succeed())
+	})
+})
+
+var _ = Describe("StopDeployments", func() {
+	It("should scale the operator and the generated deployments down to zero", func() {
+		deployment := func(name string, labels map[string]string) *appsv1.Deployment {
+			selector := map[string]string{"app": name}
+			return &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To[int32](1),
+					Selector: &metav1.LabelSelector{MatchLabels: selector},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: selector},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "manager", Image: "manager"}}},
+					},
+				},
+			}
+		}
+		operator := deployment("test-operator", nil)
+		generated := deployment("test-class-webhook", map[string]string{ManagedByLabel: FieldManager})
+		user := deployment("test-user-app", nil)
+		for _, obj := range []*appsv1.Deployment{operator, generated, user} {
+			Expect(k8sClient.Create(context.Background(), obj)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), obj))).To(Succeed())
+			})
+		}
+
+		Expect(StopDeployments(context.Background(), k8sClient, "default", "test-operator")).To(Succeed())
+
+		for _, obj := range []*appsv1.Deployment{operator, generated} {
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(*obj.Spec.Replicas).To(BeZero(), "Deployment %s should be scaled down", obj.Name)
+		}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(user), user)).To(Succeed())
+		Expect(*user.Spec.Replicas).To(Equal(int32(1)))
+	})
+})
@@ -8,16 +8,18 @@ package utils
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// GetPodOwner retrieves the owner of a Pod. If the owner is a ReplicaSet, it fetches the Deployment.
+// GetPodOwner retrieves the owner of a Pod. If the owner is a ReplicaSet, it returns its Deployment.
+// Only the metadata of the owners is read, from the cache for the kinds in cachedOwnerKinds.
 func GetPodOwner(ctx context.Context, k8sClient client.Client, pod *corev1.Pod) (string, string, error) {
 	// Check if the Pod has an owner reference
 	if len(pod.OwnerReferences) == 0 {
@@ -26,33 +28,28 @@ func GetPodOwner(ctx context.Context, k8sClient client.Client, pod *corev1.Pod)
 
 	ownerRef := pod.OwnerReferences[0] // Assume the first owner reference is the relevant one
 
-	// If the owner is a ReplicaSet, fetch its Deployment
+	// If the owner is a ReplicaSet, return its Deployment
 	if ownerRef.Kind == "ReplicaSet" {
-		replicaSet := &appsv1.ReplicaSet{}
-		err := k8sClient.Get(ctx, types.NamespacedName{Name: ownerRef.Name, Namespace: pod.Namespace}, replicaSet)
+		replicaSet, err := getOwnerMetadata(ctx, k8sClient, ownerRef, pod.Namespace)
 		if err != nil {
 			return "", "", fmt.Errorf("failed to get ReplicaSet %s: %v", ownerRef.Name, err)
 		}
 
 		// Check if the ReplicaSet has an owner reference
-		if len(replicaSet.OwnerReferences) == 0 {
-			return "", "", fmt.Errorf("replicaSet %s has no owner", replicaSet.Name)
+		if len(replicaSet.GetOwnerReferences()) == 0 {
+			return "", "", fmt.Errorf("replicaSet %s has no owner", replicaSet.GetName())
 		}
 
-		rsOwnerRef := replicaSet.OwnerReferences[0]
+		rsOwnerRef := replicaSet.GetOwnerReferences()[0]
 		if rsOwnerRef.Kind == "Deployment" {
 			return rsOwnerRef.Name, rsOwnerRef.Kind, nil
 		}
 
-		return "", "", fmt.Errorf("replicaSet %s owner is not a Deployment", replicaSet.Name)
+		return "", "", fmt.Errorf("replicaSet %s owner is not a Deployment", replicaSet.GetName())
 	}
 
 	// If the owner is not a ReplicaSet, find the root owner
-
-	ownerObj := &unstructured.Unstructured{}
-	ownerObj.SetKind(ownerRef.Kind)
-	ownerObj.SetAPIVersion(ownerRef.APIVersion)
-	err := k8sClient.Get(ctx, types.NamespacedName{Name: ownerRef.Name, Namespace: pod.Namespace}, ownerObj)
+	ownerObj, err := getOwnerMetadata(ctx, k8sClient, ownerRef, pod.Namespace)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get owner object %s: %v", ownerRef.Name, err)
 	}
@@ -62,32 +59,57 @@ func GetPodOwner(ctx context.Context, k8sClient client.Client, pod *corev1.Pod)
 		return "", "", fmt.Errorf("failed to find root owner: %v", err)
 	}
 
-	u, ok := rootOwner.(*unstructured.Unstructured)
-	if !ok {
-		return rootOwner.GetName(), "", fmt.Errorf("rootOwner is not *unstructured.Unstructured")
+	apiVersion, kind := rootOwner.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+	return rootOwner.GetName(), kind + "/" + apiVersion, nil
+}
+
+// PodWorkload returns the kind and name of the controller of the pod, without reading it: a ReplicaSet created
+// by a Deployment is reported as the Deployment, its name being the one of the Deployment suffixed by the pod
+// template hash. Pods without a controller are their own workload.
+func PodWorkload(pod *corev1.Pod) (string, string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod", pod.Name
 	}
-	return u.GetName(), u.GetKind() + "/" + u.GetAPIVersion(), nil
+	if hash, ok := pod.Labels["pod-template-hash"]; ok && owner.Kind == "ReplicaSet" && strings.HasSuffix(owner.Name, "-"+hash) {
+		return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return owner.Kind, owner.Name
 }
 
-func findRootOwner(ctx context.Context, c client.Client, obj metav1.Object) (metav1.Object, error) {
+func findRootOwner(ctx context.Context, c client.Client, obj client.Object) (client.Object, error) {
 	owners := obj.GetOwnerReferences()
 	if len(owners) == 0 {
 		return obj, nil
 	}
 
-	ownerRef := owners[0]
-	ownerObj := &unstructured.Unstructured{}
-	ownerObj.SetKind(ownerRef.Kind)
-	ownerObj.SetAPIVersion(ownerRef.APIVersion)
-
-	// Search for the owner by name and namespace
-	err := c.Get(ctx, types.NamespacedName{
-		Name:      ownerRef.Name,
-		Namespace: obj.GetNamespace(),
-	}, ownerObj)
+	ownerObj, err := getOwnerMetadata(ctx, c, owners[0], obj.GetNamespace())
 	if err != nil {
 		return nil, err
 	}
 
 	return findRootOwner(ctx, c, ownerObj)
 }
+
+// getOwnerMetadata gets the owner of an object in the namespace. The owners of a cached kind are read as
+// metadata from the informers, the others with a live request to the API server.
+func getOwnerMetadata(ctx context.Context, c client.Client, ownerRef metav1.OwnerReference, namespace string) (client.Object, error) {
+	gvk := schema.FromAPIVersionAndKind(ownerRef.APIVersion, ownerRef.Kind)
+	key := types.NamespacedName{Name: ownerRef.Name, Namespace: namespace}
+	if cachedOwnerKinds[gvk] {
+		owner := &metav1.PartialObjectMetadata{}
+		owner.SetGroupVersionKind(gvk)
+		if err := c.Get(ctx, key, owner); err != nil {
+			return nil, err
+		}
+		return owner, nil
+	}
+
+	// Unstructured objects are not cached by the client
+	owner := &unstructured.Unstructured{}
+	owner.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, key, owner); err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
@@ -8,7 +8,6 @@ package utils
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -18,12 +17,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func GetPodImageDetails(ctx context.Context, client client.Reader) (string, string, string, error) {
-	podName := os.Getenv("POD_NAME")
-	podNamespace := os.Getenv("POD_NAMESPACE")
-
+// GetPodImageDetails returns the registry, repository and tag of the first container image of the given pod.
+func GetPodImageDetails(ctx context.Context, client client.Reader, podNamespace, podName string) (string, string, string, error) {
 	if podName == "" || podNamespace == "" {
-		return "", "", "", fmt.Errorf("POD_NAME or POD_NAMESPACE environment variables are not set")
+		return "", "", "", fmt.Errorf("pod name or pod namespace are not set")
 	}
 
 	pod := &corev1.Pod{}
@@ -58,12 +55,10 @@ func GetPodImageDetails(ctx context.Context, client client.Reader) (string, stri
 	return "", "", "", fmt.Errorf("no containers found in pod")
 }
 
-func GetPodServiceAccount(ctx context.Context, client client.Reader) (string, error) {
-	podName := os.Getenv("POD_NAME")
-	podNamespace := os.Getenv("POD_NAMESPACE")
-
+// GetPodServiceAccount returns the service account of the given pod.
+func GetPodServiceAccount(ctx context.Context, client client.Reader, podNamespace, podName string) (string, error) {
 	if podName == "" || podNamespace == "" {
-		return "", fmt.Errorf("POD_NAME or POD_NAMESPACE environment variables are not set")
+		return "", fmt.Errorf("pod name or pod namespace are not set")
 	}
 
 	pod := &corev1.Pod{}
@@ -78,13 +73,10 @@ func GetPodServiceAccount(ctx context.Context, client client.Reader) (string, er
 	return pod.Spec.ServiceAccountName, nil
 }
 
-// GetPodDeploymentName retrieves the name of the deployment associated with the current pod.
-func GetPodDeploymentName() (string, error) {
-	podName := os.Getenv("POD_NAME")
-	podNamespace := os.Getenv("POD_NAMESPACE")
-
+// GetPodDeploymentName retrieves the name of the deployment associated with the given pod.
+func GetPodDeploymentName(podNamespace, podName string) (string, error) {
 	if podName == "" || podNamespace == "" {
-		return "", fmt.Errorf("POD_NAME or POD_NAMESPACE environment variables are not set")
+		return "", fmt.Errorf("pod name or pod namespace are not set")
 	}
 
 	// Create a new Kubernetes client
@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"fmt"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+// OvercommitOutdated reports whether the annotations of a pod mutated by a class record other ratios than
+// the current ones of the class. The ratios are recorded with the precision used by the mutating webhook.
+func OvercommitOutdated(annotations map[string]string, spec *overcommit.OvercommitClassSpec) bool {
+	return annotations["overcommit.inditex.dev/cpu"] != fmt.Sprintf("%.4f", spec.CpuOvercommit) ||
+		annotations["overcommit.inditex.dev/memory"] != fmt.Sprintf("%.4f", spec.MemoryOvercommit)
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+// MutatingWebhookSettings returns the settings actually configured in the first webhook of the configuration.
+func MutatingWebhookSettings(webhookConfig *admissionregistrationv1.MutatingWebhookConfiguration) *overcommit.WebhookSettings {
+	if len(webhookConfig.Webhooks) == 0 {
+		return nil
+	}
+	webhook := webhookConfig.Webhooks[0]
+	settings := &overcommit.WebhookSettings{}
+	if webhook.FailurePolicy != nil {
+		settings.FailurePolicy = *webhook.FailurePolicy
+	}
+	if webhook.TimeoutSeconds != nil {
+		settings.TimeoutSeconds = *webhook.TimeoutSeconds
+	}
+	if webhook.ReinvocationPolicy != nil {
+		settings.ReinvocationPolicy = *webhook.ReinvocationPolicy
+	}
+	return settings
+}
+
+// ValidatingWebhookSettings returns the settings actually configured in the first webhook of the configuration.
+func ValidatingWebhookSettings(webhookConfig *admissionregistrationv1.ValidatingWebhookConfiguration) *overcommit.ValidatingWebhookSettings {
+	if len(webhookConfig.Webhooks) == 0 {
+		return nil
+	}
+	webhook := webhookConfig.Webhooks[0]
+	settings := &overcommit.ValidatingWebhookSettings{}
+	if webhook.FailurePolicy != nil {
+		settings.FailurePolicy = *webhook.FailurePolicy
+	}
+	if webhook.TimeoutSeconds != nil {
+		settings.TimeoutSeconds = *webhook.TimeoutSeconds
+	}
+	return settings
+}
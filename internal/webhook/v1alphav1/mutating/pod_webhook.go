@@ -9,8 +9,11 @@ package v1alphav1
 import (
 	"context"
 
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 	overcommit "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -25,6 +28,7 @@ import (
 type PodCustomDefaulter struct {
 	Recorder record.EventRecorder
 	Client   client.Client
+	Options  overcommit.Options
 }
 
 func (d *PodCustomDefaulter) InjectRecorder(r record.EventRecorder) {
@@ -42,25 +46,38 @@ func (d *PodCustomDefaulter) Default(ctx context.Context, pod *corev1.Pod) error
 	isResize := false
 	if req, err := admission.RequestFromContext(ctx); err == nil {
 		isResize = req.SubResource == "resize"
+		// The requests of a pod can only change through the resize subresource, recomputing them on other
+		// updates would make the API server reject the update
+		if req.Operation == admissionv1.Update && !isResize {
+			return nil
+		}
 	}
 
 	if isResize {
-		overcommit.OvercommitOnResize(ctx, pod, d.Recorder, d.Client)
+		overcommit.OvercommitOnResize(ctx, pod, d.Recorder, d.Client, d.Options)
 		return nil
 	}
 
-	overcommit.Overcommit(ctx, pod, d.Recorder, d.Client)
+	overcommit.Overcommit(ctx, pod, d.Recorder, d.Client, d.Options)
 	return nil
 }
 
 // +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,reinvocationPolicy=IfNeeded,sideEffects=None,groups="",resources=pods;pods/resize,verbs=create;update,versions=v1,name=mutating-pod-v1.overcommit.inditex.dev,admissionReviewVersions=v1
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=replicasets;statefulsets;daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch
 
 // SetupPodWebhookWithManager registers the webhook for Pod in the manager.
-func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
-	defaulter := &PodCustomDefaulter{}
+func SetupPodWebhookWithManager(mgr ctrl.Manager, cfg config.Config) error {
+	defaulter := &PodCustomDefaulter{
+		Options: overcommit.Options{ClassName: cfg.OvercommitClassName, Instance: cfg.PodName},
+	}
 	defaulter.InjectRecorder(mgr.GetEventRecorderFor("pod-defaulter"))
 	defaulter.InjectClient(mgr.GetClient())
+	// The lookups of every admission request are served from the cache, synced before the pod is ready
+	if err := utils.RegisterAdmissionInformers(context.Background(), mgr.GetCache()); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr, &corev1.Pod{}).
 		WithDefaulter(defaulter).
 		Complete()
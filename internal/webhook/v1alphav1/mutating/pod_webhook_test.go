@@ -11,6 +11,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,7 +22,9 @@ var _ = Describe("PodCustomDefaulter Webhook", func() {
 	var defaulter *PodCustomDefaulter
 
 	BeforeEach(func() {
-		defaulter = &PodCustomDefaulter{}
+		defaulter = &PodCustomDefaulter{
+			Options: overcommit.Options{ClassName: "default-overcommitclass"},
+		}
 		defaulter.InjectClient(k8sClient)
 		defaulter.InjectRecorder(recorder)
 	})
@@ -50,9 +50,6 @@ func TestWebhookSuite(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
-	os.Setenv("POD_NAME", "webhook-server-mock")
-	os.Setenv("POD_NAMESPACE", "webhook-server-mock-namespace")
-	os.Setenv("OVERCOMMIT_CLASS_NAME", "default-overcommitclass")
 	os.Setenv("LABEL_OVERCOMMIT_CLASS", "inditex.com/overcommit-class")
 
 	log.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
@@ -110,9 +107,6 @@ var _ = BeforeSuite(func() {
 })
 
 var _ = AfterSuite(func() {
-	os.Unsetenv("POD_NAME")
-	os.Unsetenv("POD_NAMESPACE")
-	os.Unsetenv("OVERCOMMIT_CLASS_NAME")
 	os.Unsetenv("LABEL_OVERCOMMIT_CLASS")
 
 	By("tearing down the test environment")
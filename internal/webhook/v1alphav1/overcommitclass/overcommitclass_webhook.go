@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package v1alphav1 implements the validating webhook for OvercommitClasses.
+package v1alphav1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/exclusions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var overcommitclasslog = logf.Log.WithName("overcommitclass-resource")
+
+// +kubebuilder:object:generate=false
+type OvercommitClassValidator struct {
+	// +kubebuilder:skip
+	Client client.Client
+	// OperatorNamespace is the namespace of the operator, which the classes are expected to exclude.
+	// +kubebuilder:skip
+	OperatorNamespace string
+	// Reader lists the namespaces and pods referencing a class without caching them, Client is used when nil.
+	// +kubebuilder:skip
+	Reader client.Reader
+}
+
+func (v *OvercommitClassValidator) InjectClient(c client.Client) {
+	v.Client = c
+}
+
+// SetupOvercommitClassWebhookWithManager registers the webhook for OvercommitClass in the manager.
+func SetupOvercommitClassWebhookWithManager(mgr ctrl.Manager, operatorNamespace string) error {
+	validator := &OvercommitClassValidator{OperatorNamespace: operatorNamespace, Reader: mgr.GetAPIReader()}
+	validator.InjectClient(mgr.GetClient())
+	return ctrl.NewWebhookManagedBy(mgr, &overcommit.OvercommitClass{}).
+		WithValidator(validator).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-overcommit-inditex-dev-v1alphav1-overcommitclass,mutating=false,failurePolicy=fail,sideEffects=None,groups=overcommit.inditex.dev,resources=overcommitclass,verbs=create;update;delete,versions=v1alphav1,name=overcommitclass.inditex.dev,admissionReviewVersions=v1
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (v *OvercommitClassValidator) ValidateCreate(ctx context.Context, overcommitClass *overcommit.OvercommitClass) (admission.Warnings, error) {
+
+	overcommitclasslog.Info("validate create", "name", overcommitClass.Name)
+
+	err := isClassDefault(*overcommitClass, v.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateSpecOvercommit(*overcommitClass)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkDecimals(*overcommitClass)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkIsRegexValid(overcommitClass.Spec.ExcludedNamespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	err = exclusions.ForClass(overcommitClass).Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return riskWarnings(ctx, *overcommitClass, v.Client, v.OperatorNamespace)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (v *OvercommitClassValidator) ValidateUpdate(ctx context.Context, oldOvercommitClass *overcommit.OvercommitClass, newOvercommitClass *overcommit.OvercommitClass) (admission.Warnings, error) {
+
+	overcommitclasslog.Info("validate update", "name", oldOvercommitClass.Name)
+
+	if !oldOvercommitClass.Spec.IsDefault {
+		err := isClassDefault(*newOvercommitClass, v.Client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := validateSpecOvercommit(*newOvercommitClass)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkDecimals(*newOvercommitClass)
+	if err != nil {
+		return nil, err
+	}
+	err = checkIsRegexValid(newOvercommitClass.Spec.ExcludedNamespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	err = exclusions.ForClass(newOvercommitClass).Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := riskWarnings(ctx, *newOvercommitClass, v.Client, v.OperatorNamespace)
+	if err != nil {
+		return nil, err
+	}
+	reader := v.Reader
+	if reader == nil {
+		reader = v.Client
+	}
+	unreachable, err := quotaWarnings(ctx, *oldOvercommitClass, *newOvercommitClass, reader)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, unreachable...)
+	if oldOvercommitClass.Spec.IsDefault && !newOvercommitClass.Spec.IsDefault {
+		warning, err := defaultClassWarning(ctx, *newOvercommitClass, v.Client)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings, nil
+}
+
+// +kubebuilder:rbac:groups="",resources=namespaces;pods;resourcequotas,verbs=get;list;watch
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+// Deleting a class still in use would silently drop its pods to the fallback ratios, so it is refused
+// unless the class carries the ForceDeleteAnnotation.
+func (v *OvercommitClassValidator) ValidateDelete(ctx context.Context, overcommitClass *overcommit.OvercommitClass) (admission.Warnings, error) {
+	overcommitclasslog.Info("validate delete", "name", overcommitClass.Name)
+
+	reader := v.Reader
+	if reader == nil {
+		reader = v.Client
+	}
+	references, err := classReferences(ctx, *overcommitClass, reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(references) == 0 {
+		return nil, nil
+	}
+
+	if overcommitClass.Annotations[overcommit.ForceDeleteAnnotation] == "true" {
+		overcommitclasslog.Info("Forcing the deletion of a class in use", "name", overcommitClass.Name, "references", references)
+		return admission.Warnings{fmt.Sprintf("OvercommitClass %s is deleted while still in use: %s", overcommitClass.Name, strings.Join(references, ", "))}, nil
+	}
+	return nil, fmt.Errorf("OvercommitClass %s is still in use: %s; set the annotation %s=true to delete it anyway",
+		overcommitClass.Name, strings.Join(references, ", "), overcommit.ForceDeleteAnnotation)
+}
@@ -0,0 +1,427 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+)
+
+var _ = Describe("OvercommitClass Webhook", func() {
+	var validator *OvercommitClassValidator
+
+	BeforeEach(func() {
+		validator = &OvercommitClassValidator{}
+		validator.InjectClient(k8sClient)
+	})
+
+	AfterEach(func() {
+		// Clean up all resources created during the test
+		By("Cleaning up OvercommitClass resources")
+		err := k8sClient.DeleteAllOf(context.TODO(), &overcommit.OvercommitClass{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("ValidateCreate", func() {
+		It("Should pass validation for a valid OvercommitClass", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should fail validation for invalid CPU overcommit", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      -0.5, // Invalid value
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cpuOvercommit must be greater than 0 and equal or lower than 1, failed creating  class"))
+		})
+
+		It("Should fail validation for invalid memory overcommit", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   -0.5, // Invalid value
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("memoryOvercommit must be greater than 0 and equal or lower than 1, failed creating  class"))
+		})
+
+		It("Should fail validation for invalid excluded namespaces", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: ".**./*/-*./../kube-system,invalid-namespace", // Invalid value
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("regex"))
+		})
+
+		It("Should warn about ratios below the thresholds", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.05,
+					MemoryOvercommit:   0.3,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(2))
+			Expect(warnings[0]).To(ContainSubstring("cpuOvercommit 0.05 is below 0.1"))
+			Expect(warnings[1]).To(ContainSubstring("memoryOvercommit 0.3 is below 0.5"))
+		})
+
+		It("Should use the thresholds of the Overcommit", func() {
+			overcommitObject := &overcommit.Overcommit{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: overcommit.OvercommitSpec{
+					OvercommitLabel:        "inditex.com/overcommit-class",
+					ClassWarningThresholds: &overcommit.ClassWarningThresholds{CpuOvercommit: 0.6},
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), overcommitObject)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(context.TODO(), overcommitObject)).To(Succeed())
+			})
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("cpuOvercommit 0.5 is below 0.6")))
+		})
+
+		It("Should warn when the system namespaces are not excluded", func() {
+			validator.OperatorNamespace = "k8s-overcommit"
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "^openshift-.*",
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(context.TODO(), overcommitClass)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				ContainSubstring("does not cover namespace kube-system"),
+				ContainSubstring("does not cover namespace k8s-overcommit"),
+			))
+		})
+	})
+
+	Context("ValidateUpdate", func() {
+		It("Should pass validation for a valid update", func() {
+			oldOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			newOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.7,
+					MemoryOvercommit:   0.7,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          false,
+				},
+			}
+
+			warnings, err := validator.ValidateUpdate(context.TODO(), oldOvercommitClass, newOvercommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should fail validation for invalid memory overcommit in update", func() {
+			oldOvercommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			newOvercommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.7,
+					MemoryOvercommit:   -0.7, // Invalid value
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateUpdate(context.TODO(), oldOvercommitClass, newOvercommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("memoryOvercommit must be greater than 0 and equal or lower than 1, failed creating  class"))
+		})
+
+		It("Should fail validation for invalid memory overcommit in update", func() {
+			oldOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			newOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.7,
+					MemoryOvercommit:   -0.7, // Invalid value
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateUpdate(context.TODO(), oldOvercommitClass, newOvercommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("memoryOvercommit must be greater than 0 and equal or lower than 1, failed creating test class"))
+		})
+
+		It("Should warn when the cluster is left without a default class", func() {
+			oldOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+					IsDefault:          true,
+				},
+			}
+			newOvercommitClass := oldOvercommitClass.DeepCopy()
+			newOvercommitClass.Spec.IsDefault = false
+
+			warnings, err := validator.ValidateUpdate(context.TODO(), oldOvercommitClass, newOvercommitClass)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("no longer the default")))
+		})
+
+		It("Should warn when the new ratios make the limits quotas unreachable", func() {
+			overcommitObject := &overcommit.Overcommit{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: overcommit.OvercommitSpec{
+					OvercommitLabel: "inditex.com/overcommit-class",
+					ResourceQuotas:  &overcommit.ResourceQuotaPolicy{Enabled: true},
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), overcommitObject)).To(Succeed())
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "quota-test"}}
+			Expect(k8sClient.Create(context.TODO(), namespace)).To(Succeed())
+			resourceQuota := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "compute",
+					Namespace:   namespace.Name,
+					Annotations: map[string]string{"overcommit.inditex.dev/class": "test-overcommitclass"},
+				},
+				Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+					corev1.ResourceRequestsCPU: resource.MustParse("10"),
+					corev1.ResourceLimitsCPU:   resource.MustParse("20"),
+				}},
+			}
+			Expect(k8sClient.Create(context.TODO(), resourceQuota)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(context.TODO(), resourceQuota)).To(Succeed())
+				Expect(k8sClient.Delete(context.TODO(), namespace)).To(Succeed())
+				Expect(k8sClient.Delete(context.TODO(), overcommitObject)).To(Succeed())
+			})
+			oldOvercommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+			newOvercommitClass := oldOvercommitClass.DeepCopy()
+			newOvercommitClass.Spec.CpuOvercommit = 0.8
+
+			warnings, err := validator.ValidateUpdate(context.TODO(), oldOvercommitClass, newOvercommitClass)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("the cpu limits of ResourceQuota quota-test/compute cannot be reached")))
+		})
+	})
+
+	Context("ValidateDelete", func() {
+		It("Should pass validation for delete", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:      0.5,
+					MemoryOvercommit:   0.5,
+					ExcludedNamespaces: "kube-system",
+				},
+			}
+
+			warnings, err := validator.ValidateDelete(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should fail validation for deleting the default class", func() {
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					IsDefault:        true,
+				},
+			}
+
+			warnings, err := validator.ValidateDelete(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("it is the default class"))
+		})
+
+		It("Should pass validation for deleting a default class when another class is default", func() {
+			otherDefault := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-default-overcommitclass"},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					IsDefault:        true,
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), otherDefault)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(context.TODO(), otherDefault)).To(Succeed())
+			})
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-overcommitclass"},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					IsDefault:        true,
+				},
+			}
+
+			warnings, err := validator.ValidateDelete(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should fail validation for deleting a class referenced by namespaces and pods", func() {
+			overcommitObject := &overcommit.Overcommit{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       overcommit.OvercommitSpec{OvercommitLabel: "inditex.com/overcommit-class"},
+			}
+			namespace := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-labelled-namespace",
+					Labels: map[string]string{"inditex.com/overcommit-class": "test-overcommitclass"},
+				},
+			}
+			for _, obj := range []client.Object{overcommitObject, namespace} {
+				Expect(k8sClient.Create(context.TODO(), obj)).To(Succeed())
+			}
+			DeferCleanup(func() {
+				for _, obj := range []client.Object{overcommitObject, namespace} {
+					Expect(k8sClient.Delete(context.TODO(), obj)).To(Succeed())
+				}
+			})
+			overcommitClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-overcommitclass",
+				},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+				},
+				Status: overcommit.OvercommitClassStatus{
+					Drift: &overcommit.DriftStatus{CurrentPods: 2, OutdatedPods: 1},
+				},
+			}
+
+			warnings, err := validator.ValidateDelete(context.TODO(), overcommitClass)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("labelled namespaces [test-labelled-namespace]"))
+			Expect(err.Error()).To(ContainSubstring("3 running pods mutated by the class"))
+
+			By("forcing the deletion")
+			overcommitClass.Annotations = map[string]string{overcommit.ForceDeleteAnnotation: "true"}
+			warnings, err = validator.ValidateDelete(context.TODO(), overcommitClass)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("deleted while still in use")))
+		})
+	})
+})
@@ -18,15 +18,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 )
 
 var cfg *rest.Config
 var testEnv *envtest.Environment
 var k8sClient client.Client // Global Kubernetes client
 
-func TestAPIs(t *testing.T) {
+func TestWebhookSuite(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "Webhook Suite")
+	RunSpecs(t, "OvercommitClass Webhook Suite")
 }
 
 var _ = BeforeSuite(func() {
@@ -35,7 +37,7 @@ var _ = BeforeSuite(func() {
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths: []string{
-			filepath.Join("..", "..", "config", "crd", "bases"),
+			filepath.Join("..", "..", "..", "..", "config", "crd", "bases"),
 		},
 	}
 
@@ -44,7 +46,7 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(cfg).NotTo(BeNil())
 
-	err = AddToScheme(scheme.Scheme)
+	err = overcommit.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
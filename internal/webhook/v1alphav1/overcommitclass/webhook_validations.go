@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alphav1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/exclusions"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/quota"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func validateSpecOvercommit(class overcommit.OvercommitClass) error {
+	if class.Spec.CpuOvercommit <= 0 || class.Spec.CpuOvercommit > 1 {
+		return errors.New("Error: cpuOvercommit must be greater than 0 and equal or lower than 1, failed creating " + class.Name + " class ")
+	}
+	if class.Spec.MemoryOvercommit <= 0 || class.Spec.MemoryOvercommit > 1 {
+		return errors.New("Error: memoryOvercommit must be greater than 0 and equal or lower than 1, failed creating " + class.Name + " class ")
+	}
+	return nil
+}
+
+func checkDecimals(class overcommit.OvercommitClass) error {
+	cpu := class.Spec.CpuOvercommit
+	memory := class.Spec.MemoryOvercommit
+	const precision = 10000 // 10^4
+	roundedCpu := math.Round(cpu*precision) / precision
+
+	if math.Abs(cpu-roundedCpu) > 1e-9 {
+		return errors.New("the CPU value must have 4 decimals max")
+	}
+
+	roundedMemory := math.Round(memory*precision) / precision
+	if math.Abs(memory-roundedMemory) > 1e-9 {
+		return errors.New("the memory value must have 4 decimals max")
+	}
+	return nil
+}
+
+func isClassDefault(class overcommit.OvercommitClass, client client.Client) error {
+	// Create a context for the client
+	ctx := context.TODO()
+
+	// List all OvercommitClasses
+	var overcommitClassList overcommit.OvercommitClassList
+	err := client.List(ctx, &overcommitClassList)
+	if err != nil {
+		return fmt.Errorf("error listing OvercommitClasses: %w", err)
+	}
+
+	var existsDefault = false
+	if class.Spec.IsDefault {
+		for _, item := range overcommitClassList.Items {
+			if item.Spec.IsDefault {
+				existsDefault = true
+			}
+		}
+	}
+
+	if existsDefault {
+		return fmt.Errorf("error: only one OvercommitClass can be default, failed creating %s class", class.Name)
+	}
+
+	return nil
+}
+
+func checkIsRegexValid(regex string) error {
+	// Limit regex length to prevent ReDoS (catastrophic backtracking)
+	const maxRegexLen = 512
+	if len(regex) > maxRegexLen {
+		return fmt.Errorf("regex is too long (%d chars), maximum allowed is %d", len(regex), maxRegexLen)
+	}
+	_, err := regexp.Compile(regex)
+	if err != nil {
+		return fmt.Errorf("invalid regex for excludedNamespaces: %w", err)
+	}
+	return nil
+}
+
+// riskWarnings returns the warnings about a valid class that may still hurt the cluster: ratios below the
+// thresholds of the Overcommit and system namespaces left out of its excludedNamespaces.
+func riskWarnings(ctx context.Context, class overcommit.OvercommitClass, c client.Client, operatorNamespace string) (admission.Warnings, error) {
+	overcommitObject := &overcommit.Overcommit{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "cluster"}, overcommitObject); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting the Overcommit: %w", err)
+		}
+		overcommitObject = nil
+	}
+	cpuThreshold, memoryThreshold := overcommitObject.WarningThresholds()
+
+	var warnings admission.Warnings
+	if class.Spec.CpuOvercommit < cpuThreshold {
+		warnings = append(warnings, fmt.Sprintf("cpuOvercommit %v is below %v, pods of class %s may be heavily throttled", class.Spec.CpuOvercommit, cpuThreshold, class.Name))
+	}
+	if class.Spec.MemoryOvercommit < memoryThreshold {
+		warnings = append(warnings, fmt.Sprintf("memoryOvercommit %v is below %v, pods of class %s may be OOM killed or evicted", class.Spec.MemoryOvercommit, memoryThreshold, class.Name))
+	}
+
+	for _, namespace := range []string{"kube-system", operatorNamespace} {
+		if namespace == "" {
+			continue
+		}
+		excluded, err := exclusions.ForClass(&class).Excludes(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+		if err != nil {
+			return nil, err
+		}
+		if !excluded {
+			warnings = append(warnings, fmt.Sprintf("excludedNamespaces does not cover namespace %s, its pods will be overcommitted by class %s", namespace, class.Name))
+		}
+	}
+	return warnings, nil
+}
+
+// defaultClassWarning returns a warning when no class other than the given one is default.
+func defaultClassWarning(ctx context.Context, class overcommit.OvercommitClass, reader client.Reader) (string, error) {
+	otherDefault, err := hasOtherDefault(ctx, class, reader)
+	if err != nil || otherDefault {
+		return "", err
+	}
+	return fmt.Sprintf("OvercommitClass %s is no longer the default, pods without a class will not be overcommitted", class.Name), nil
+}
+
+// hasOtherDefault reports whether a class other than the given one is default.
+func hasOtherDefault(ctx context.Context, class overcommit.OvercommitClass, reader client.Reader) (bool, error) {
+	var overcommitClassList overcommit.OvercommitClassList
+	if err := reader.List(ctx, &overcommitClassList); err != nil {
+		return false, fmt.Errorf("error listing OvercommitClasses: %w", err)
+	}
+	for _, item := range overcommitClassList.Items {
+		if item.Name != class.Name && item.Spec.IsDefault {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// quotaWarnings returns a warning for every ResourceQuota of the class whose limits quota becomes unreachable
+// with its new ratios, when the Overcommit follows the quotas. The quotas of the class are the ones annotated
+// with it by the quota controller.
+func quotaWarnings(ctx context.Context, oldClass, newClass overcommit.OvercommitClass, reader client.Reader) (admission.Warnings, error) {
+	if oldClass.Spec.CpuOvercommit == newClass.Spec.CpuOvercommit && oldClass.Spec.MemoryOvercommit == newClass.Spec.MemoryOvercommit {
+		return nil, nil
+	}
+	overcommitObject := &overcommit.Overcommit{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: "cluster"}, overcommitObject); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting the Overcommit: %w", err)
+	}
+	if !overcommitObject.ResourceQuotasEnabled() {
+		return nil, nil
+	}
+
+	var resourceQuotas corev1.ResourceQuotaList
+	if err := reader.List(ctx, &resourceQuotas); err != nil {
+		return nil, fmt.Errorf("error listing ResourceQuotas: %w", err)
+	}
+	previous := quota.Ratios{CPU: oldClass.Spec.CpuOvercommit, Memory: oldClass.Spec.MemoryOvercommit}
+	next := quota.Ratios{CPU: newClass.Spec.CpuOvercommit, Memory: newClass.Spec.MemoryOvercommit}
+	var warnings admission.Warnings
+	for i := range resourceQuotas.Items {
+		resourceQuota := &resourceQuotas.Items[i]
+		if resourceQuota.Annotations[quota.ClassAnnotation] != newClass.Name {
+			continue
+		}
+		for _, name := range quota.NewlyUnreachable(resourceQuota, previous, next, overcommitObject.ScalesRequestsQuotas()) {
+			warnings = append(warnings, fmt.Sprintf("the %s limits of ResourceQuota %s/%s cannot be reached with the new ratios of class %s, the requests quota is exhausted first",
+				name, resourceQuota.Namespace, resourceQuota.Name, newClass.Name))
+		}
+	}
+	return warnings, nil
+}
+
+// classReferences describes what still uses the class: being the only default, the namespaces labelled with it
+// and the running pods it was applied to, as counted by the last drift scan of the class.
+func classReferences(ctx context.Context, class overcommit.OvercommitClass, reader client.Reader) ([]string, error) {
+	var references []string
+	if class.Spec.IsDefault {
+		otherDefault, err := hasOtherDefault(ctx, class, reader)
+		if err != nil {
+			return nil, err
+		}
+		if !otherDefault {
+			references = append(references, "it is the default class")
+		}
+	}
+
+	overcommitObject := &overcommit.Overcommit{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: "cluster"}, overcommitObject); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting the Overcommit: %w", err)
+		}
+	} else {
+		var namespaces corev1.NamespaceList
+		if err := reader.List(ctx, &namespaces, client.MatchingLabels{overcommitObject.Spec.OvercommitLabel: class.Name}); err != nil {
+			return nil, fmt.Errorf("error listing namespaces: %w", err)
+		}
+		if len(namespaces.Items) > 0 {
+			names := make([]string, 0, len(namespaces.Items))
+			for _, namespace := range namespaces.Items {
+				names = append(names, namespace.Name)
+			}
+			references = append(references, fmt.Sprintf("labelled namespaces [%s]", strings.Join(names, " ")))
+		}
+	}
+
+	if drift := class.Status.Drift; drift != nil && drift.CurrentPods+drift.OutdatedPods > 0 {
+		references = append(references, fmt.Sprintf("%d running pods mutated by the class", drift.CurrentPods+drift.OutdatedPods))
+	}
+	return references, nil
+}
@@ -9,11 +9,17 @@ package v1alphav1
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/InditexTech/k8s-overcommit-operator/internal/config"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/exclusions"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/pkg/overcommit"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -25,9 +31,16 @@ import (
 var podlog = logf.Log.WithName("pod-resource")
 
 // SetupPodWebhookWithManager registers the webhook for Pod in the manager.
-func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+func SetupPodWebhookWithManager(mgr ctrl.Manager, cfg config.Config) error {
 	validator := &PodCustomValidator{}
 	validator.Client = mgr.GetClient()
+	if cfg.ServiceAccountName != "" {
+		validator.OperatorUsername = fmt.Sprintf("system:serviceaccount:%s:%s", cfg.PodNamespace, cfg.ServiceAccountName)
+	}
+	// The lookups of every admission request are served from the cache, synced before the pod is ready
+	if err := utils.RegisterAdmissionInformers(context.Background(), mgr.GetCache()); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr, &corev1.Pod{}).
 		WithValidator(validator).
 		Complete()
@@ -42,55 +55,159 @@ func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
 // as this struct is used only for temporary operations and does not need to be deeply copied.
 type PodCustomValidator struct {
 	Client client.Client
+	// OperatorUsername is the user of the operator, allowed to rewrite the overcommit decisions of running pods.
+	OperatorUsername string
 }
 
+// AdminGroup is the group of the cluster admins, allowed to bypass the protection of the overcommit decisions.
+const AdminGroup = "system:masters"
+
+// ProtectedAnnotationPrefix is the prefix of the annotations written by the overcommit webhook on pods.
+const ProtectedAnnotationPrefix = "overcommit.inditex.dev/"
+
 var _ admission.Validator[*corev1.Pod] = &PodCustomValidator{}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Pod.
+// The pod is checked against the policy of the class it resolves to, like the mutating webhook does.
+// Violations are rejected in Enforce mode and returned as warnings in Warn mode. A pod whose policy cannot be
+// looked up is admitted with a warning, the webhook never rejects a pod because of a lookup error.
 func (v *PodCustomValidator) ValidateCreate(ctx context.Context, pod *corev1.Pod) (admission.Warnings, error) {
+	podlog.Info("Validation for Pod upon creation", "name", pod.GetName())
 
-	label, err := utils.GetOvercommitLabel(ctx, v.Client)
+	overcommitResource, err := utils.GetOvercommit(ctx, v.Client)
 	if err != nil {
-		return nil, err
+		return unchecked(pod, err), nil
 	}
 
-	podlog.Info("Validation for Pod upon creation", "name", pod.GetName())
+	// The namespace may not be set yet in the object of a creation request
+	if pod.Namespace == "" {
+		if req, reqErr := admission.RequestFromContext(ctx); reqErr == nil {
+			pod = pod.DeepCopy()
+			pod.Namespace = req.Namespace
+		}
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Name: pod.Namespace}, namespace); err != nil {
+		return unchecked(pod, err), nil
+	}
 
-	value, exists := pod.Labels[label]
+	label := overcommitResource.Spec.OvercommitLabel
+	var violations []string
+	resolution, err := overcommit.ResolveClass(ctx, pod, v.Client, label)
+	switch {
+	case apierrors.IsNotFound(err):
+		// The namespace being found, the missing object is the class named by its label
+		violations = []string{fmt.Sprintf("OvercommitClass %s requested by namespace %s not found", namespace.Labels[label], pod.Namespace)}
+	case err != nil:
+		return unchecked(pod, err), nil
+	default:
+		violations = policyViolations(pod, namespace, label, resolution)
+	}
+	if len(violations) == 0 {
+		return nil, nil
+	}
 
-	if !exists {
-		return nil, errors.New("Pod without overcommit class label: " + label)
+	// Pods falling back to the default class did not ask for overcommit, they are never rejected
+	_, requested := pod.Labels[label]
+	if overcommitResource.WarnsOnly() || (!requested && resolution != nil && resolution.Source == overcommit.ClassSourceDefault) {
+		return admission.Warnings(violations), nil
 	}
+	return nil, errors.New(strings.Join(violations, "; "))
+}
 
-	unstructuredObj := unstructured.Unstructured{}
-	unstructuredObj.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "overcommit.inditex.dev",
-		Version: "v1alphav1",
-		Kind:    "OvercommitClass",
-	})
+// unchecked admits a pod whose policy could not be looked up, returning the error as a warning.
+func unchecked(pod *corev1.Pod, err error) admission.Warnings {
+	podlog.Error(err, "Error looking up the overcommit policy of the pod, admitting it", "name", pod.GetName(), "namespace", pod.Namespace)
+	return admission.Warnings{fmt.Sprintf("the overcommit policy of the pod could not be checked: %v", err)}
+}
 
-	err = v.Client.Get(ctx, client.ObjectKey{
-		Name: value,
-	}, &unstructuredObj)
+// policyViolations returns the reasons why the pod does not comply with the policy of its class.
+func policyViolations(pod *corev1.Pod, namespace *corev1.Namespace, label string, resolution *overcommit.ClassResolution) []string {
+	if value, exists := pod.Labels[label]; exists && (resolution == nil || resolution.Source != overcommit.ClassSourcePod) {
+		return []string{fmt.Sprintf("OvercommitClass %s requested by the pod not found", value)}
+	}
+	if resolution == nil {
+		// No class applies to the pod, so there is no policy to check
+		return nil
+	}
 
+	class := resolution.Class
+	excluded, err := exclusions.ForClass(class).Excludes(namespace)
 	if err != nil {
-		return nil, err
+		return []string{fmt.Sprintf("OvercommitClass %s has invalid namespace exclusions: %v", class.Name, err)}
 	}
-
-	if unstructuredObj.Object == nil {
-		return nil, errors.New("OvercommitClass not found")
+	if excluded {
+		if resolution.Source == overcommit.ClassSourceDefault {
+			// The default class does not apply to its excluded namespaces
+			return nil
+		}
+		return []string{fmt.Sprintf("OvercommitClass %s is not allowed in namespace %s", class.Name, pod.Namespace)}
 	}
-	return nil, nil
 
+	var violations []string
+	if class.Spec.Deprecated {
+		violations = append(violations, fmt.Sprintf("OvercommitClass %s is deprecated", class.Name))
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := container.Resources.Limits[resourceName]; !ok {
+				violations = append(violations, fmt.Sprintf("container %s has no %s limit, its request cannot be overcommitted", container.Name, resourceName))
+			}
+		}
+	}
+	return violations
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Pod.
+// The overcommit class label and the annotations recording the overcommit applied to the pod cannot be
+// changed once the pod exists, except by cluster admins and the operator itself.
 func (v *PodCustomValidator) ValidateUpdate(ctx context.Context, oldPod *corev1.Pod, pod *corev1.Pod) (admission.Warnings, error) {
 	podlog.Info("Validation for Pod upon update", "name", pod.GetName())
 
-	// TODO(user): fill in your validation logic upon object update.
+	if req, err := admission.RequestFromContext(ctx); err == nil && v.isPrivileged(req.UserInfo) {
+		podlog.Info("Privileged user, skipping the protection of the overcommit decisions", "name", pod.GetName(), "user", req.UserInfo.Username)
+		return nil, nil
+	}
 
-	return nil, nil
+	// The annotations are still protected when the label cannot be looked up
+	var warnings admission.Warnings
+	label, err := utils.GetOvercommitLabel(ctx, v.Client)
+	if err != nil {
+		warnings = unchecked(pod, err)
+	} else if changed(oldPod.Labels, pod.Labels, label) {
+		return nil, fmt.Errorf("the overcommit class label %s cannot be changed on an existing pod", label)
+	}
+
+	keys := make(map[string]struct{})
+	for key := range oldPod.Annotations {
+		keys[key] = struct{}{}
+	}
+	for key := range pod.Annotations {
+		keys[key] = struct{}{}
+	}
+	for key := range keys {
+		if strings.HasPrefix(key, ProtectedAnnotationPrefix) && changed(oldPod.Annotations, pod.Annotations, key) {
+			return nil, fmt.Errorf("the annotation %s is managed by the overcommit webhook and cannot be changed", key)
+		}
+	}
+
+	return warnings, nil
+}
+
+// isPrivileged reports whether the user may change the overcommit decisions of running pods.
+func (v *PodCustomValidator) isPrivileged(userInfo authenticationv1.UserInfo) bool {
+	if v.OperatorUsername != "" && userInfo.Username == v.OperatorUsername {
+		return true
+	}
+	return slices.Contains(userInfo.Groups, AdminGroup)
+}
+
+// changed reports whether key was added, removed or modified between the old and the new map.
+func changed(oldValues, newValues map[string]string, key string) bool {
+	oldValue, oldExists := oldValues[key]
+	newValue, newExists := newValues[key]
+	return oldExists != newExists || oldValue != newValue
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Pod.
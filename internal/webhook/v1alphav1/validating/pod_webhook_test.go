@@ -9,8 +9,14 @@ import (
 	"context"
 	"os"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -31,8 +37,9 @@ var _ = Describe("PodCustomValidator Webhook", func() {
 		// Create a sample Pod object
 		pod = &corev1.Pod{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:   "test-pod",
-				Labels: map[string]string{"inditex.com/overcommit-class": "default-overcommitclass"},
+				Name:      "test-pod",
+				Namespace: "default",
+				Labels:    map[string]string{"inditex.com/overcommit-class": "default-overcommitclass"},
 			},
 		}
 	})
@@ -49,14 +56,13 @@ var _ = Describe("PodCustomValidator Webhook", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("should fail when Pod lacks overcommit class label", func() {
+		It("should pass validation when Pod lacks overcommit class label and falls back to the default class", func() {
 			delete(pod.Labels, "inditex.com/overcommit-class")
 
 			// Validate Pod creation
 			warnings, err := validator.ValidateCreate(ctx, pod)
 			Expect(warnings).To(BeNil())
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("Pod without overcommit class"))
+			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("should fail validation when OvercommitClass doesnt exists", func() {
@@ -65,6 +71,99 @@ var _ = Describe("PodCustomValidator Webhook", func() {
 			warnings, err := validator.ValidateCreate(ctx, pod)
 			Expect(warnings).To(BeNil())
 			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("nonexistent-overcommitclass"))
+		})
+
+		It("should fail when a container has no limits", func() {
+			pod.Spec.Containers = []corev1.Container{{Name: "app", Image: "nginx"}}
+
+			warnings, err := validator.ValidateCreate(ctx, pod)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("container app has no cpu limit"))
+			Expect(err.Error()).To(ContainSubstring("container app has no memory limit"))
+		})
+
+		It("should only warn about the default class falling back on pods without limits", func() {
+			delete(pod.Labels, "inditex.com/overcommit-class")
+			pod.Spec.Containers = []corev1.Container{{Name: "app", Image: "nginx"}}
+
+			warnings, err := validator.ValidateCreate(ctx, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(2))
+		})
+
+		It("should fail when the class is not allowed in the namespace", func() {
+			pod.Namespace = "kube-system"
+
+			warnings, err := validator.ValidateCreate(ctx, pod)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not allowed in namespace kube-system"))
+		})
+
+		It("should fail when the class is deprecated", func() {
+			deprecatedClass := &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "deprecated-overcommitclass"},
+				Spec: overcommit.OvercommitClassSpec{
+					CpuOvercommit:    0.5,
+					MemoryOvercommit: 0.5,
+					Deprecated:       true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deprecatedClass)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, deprecatedClass)).To(Succeed())
+			})
+			pod.Labels["inditex.com/overcommit-class"] = "deprecated-overcommitclass"
+
+			warnings, err := validator.ValidateCreate(ctx, pod)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is deprecated"))
+		})
+
+		It("should fail when the class of the namespace doesnt exist", func() {
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "missing-class",
+				Labels: map[string]string{"inditex.com/overcommit-class": "nonexistent-overcommitclass"},
+			}}
+			Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, namespace)).To(Succeed())
+			})
+			delete(pod.Labels, "inditex.com/overcommit-class")
+			pod.Namespace = "missing-class"
+
+			warnings, err := validator.ValidateCreate(ctx, pod)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("nonexistent-overcommitclass requested by namespace missing-class"))
+		})
+
+		It("should admit the pod with a warning when its policy cannot be looked up", func() {
+			pod.Namespace = "nonexistent-namespace"
+
+			warnings, err := validator.ValidateCreate(ctx, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("could not be checked")))
+		})
+
+		It("should return warnings instead of failing in Warn mode", func() {
+			current := &overcommit.Overcommit{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "cluster"}, current)).To(Succeed())
+			current.Spec.ValidationMode = overcommit.ValidationModeWarn
+			Expect(k8sClient.Update(ctx, current)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "cluster"}, current)).To(Succeed())
+				current.Spec.ValidationMode = overcommit.ValidationModeEnforce
+				Expect(k8sClient.Update(ctx, current)).To(Succeed())
+			})
+			pod.Labels["inditex.com/overcommit-class"] = "nonexistent-overcommitclass"
+
+			warnings, err := validator.ValidateCreate(ctx, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("nonexistent-overcommitclass")))
 		})
 	})
 
@@ -75,6 +174,51 @@ var _ = Describe("PodCustomValidator Webhook", func() {
 			Expect(warnings).To(BeNil())
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should fail when the overcommit class label changes", func() {
+			newPod := pod.DeepCopy()
+			newPod.Labels["inditex.com/overcommit-class"] = "other-overcommitclass"
+
+			warnings, err := validator.ValidateUpdate(ctx, pod, newPod)
+			Expect(warnings).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("overcommit class label"))
+		})
+
+		It("should fail when the overcommit annotations are stripped or forged", func() {
+			pod.Annotations = map[string]string{"overcommit.inditex.dev/cpu": "0.5000"}
+
+			stripped := pod.DeepCopy()
+			delete(stripped.Annotations, "overcommit.inditex.dev/cpu")
+			_, err := validator.ValidateUpdate(ctx, pod, stripped)
+			Expect(err).To(HaveOccurred())
+
+			forged := pod.DeepCopy()
+			forged.Annotations["overcommit.inditex.dev/memory"] = "1.0000"
+			_, err = validator.ValidateUpdate(ctx, pod, forged)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should pass when unrelated annotations change", func() {
+			newPod := pod.DeepCopy()
+			newPod.Annotations = map[string]string{"example.com/annotation": "true"}
+
+			_, err := validator.ValidateUpdate(ctx, pod, newPod)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should let cluster admins change the overcommit class label", func() {
+			newPod := pod.DeepCopy()
+			newPod.Labels["inditex.com/overcommit-class"] = "other-overcommitclass"
+			adminCtx := admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UserInfo: authenticationv1.UserInfo{Username: "admin", Groups: []string{AdminGroup}},
+				},
+			})
+
+			_, err := validator.ValidateUpdate(adminCtx, pod, newPod)
+			Expect(err).NotTo(HaveOccurred())
+		})
 	})
 
 	Context("ValidateDelete", func() {
@@ -35,6 +35,7 @@ var (
 		},
 		Spec: overcommit.OvercommitSpec{
 			OvercommitLabel: "inditex.com/overcommit-class",
+			ValidationMode:  overcommit.ValidationModeEnforce,
 		},
 	}
 )
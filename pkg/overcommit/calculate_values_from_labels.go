@@ -8,7 +8,12 @@ package overcommit
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/exclusions"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/utils"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,92 +26,235 @@ type overcommitResolution struct {
 	ownerName   string
 	ownerKind   string
 	resolved    bool
+	// skipReason is set when the mutation is disabled by the Overcommit or the resolved class
+	skipReason string
+	// source and classGeneration identify the resolved class in the decision recorded on the pod
+	source          ClassSource
+	classGeneration int64
 }
 
-// getNamespaceOvercommit gets the overcommit values from the namespace label or falls back to the default class.
-// Returns safe no-op values when any error occurs to avoid mutating pods incorrectly.
-func getNamespaceOvercommit(ctx context.Context, pod *corev1.Pod, k8sClient client.Client, label, ownerName, ownerKind string) overcommitResolution {
-	// Get the namespace of the pod
-	namespaceName := pod.Namespace
+const (
+	skipReasonPaused    = "paused"
+	skipReasonSuspended = "suspended"
+	skipReasonReverted  = "reverted"
+)
+
+// classResolution builds the resolution of a class, disabling the mutation when the class is suspended.
+func classResolution(className string, spec *overcommit.OvercommitClassSpec, ownerName, ownerKind string) overcommitResolution {
+	if spec.Suspended {
+		return overcommitResolution{className: className, cpuValue: 1.0, memoryValue: 1.0, ownerName: ownerName, ownerKind: ownerKind, skipReason: skipReasonSuspended}
+	}
+	return overcommitResolution{
+		className:   className,
+		cpuValue:    spec.CpuOvercommit,
+		memoryValue: spec.MemoryOvercommit,
+		ownerName:   ownerName,
+		ownerKind:   ownerKind,
+		resolved:    true,
+	}
+}
+
+// ClassSource tells where the OvercommitClass of a pod was resolved from.
+type ClassSource string
+
+const (
+	// ClassSourcePod is a class requested with the class label of the pod.
+	ClassSourcePod ClassSource = "Pod"
+	// ClassSourceNamespace is a class requested with the class label of the namespace of the pod.
+	ClassSourceNamespace ClassSource = "Namespace"
+	// ClassSourceDefault is the default class, used when neither the pod nor its namespace request one.
+	ClassSourceDefault ClassSource = "Default"
+)
+
+// ClassResolution is the OvercommitClass a pod resolves to.
+type ClassResolution struct {
+	Class  *overcommit.OvercommitClass
+	Source ClassSource
+}
+
+// ResolveClass resolves the OvercommitClass of a pod the same way the mutating webhook does: the class label
+// of the pod first, then the class label of its namespace and finally the default class. A pod label naming a
+// missing class falls back to the namespace. It returns nil when the pod resolves to no class at all.
+func ResolveClass(ctx context.Context, pod *corev1.Pod, k8sClient client.Client, label string) (*ClassResolution, error) {
+	if value, exists := pod.Labels[label]; exists {
+		overcommitClass := &overcommit.OvercommitClass{}
+		start := time.Now()
+		err := k8sClient.Get(ctx, client.ObjectKey{Name: value}, overcommitClass)
+		observeLookup(lookupClass, value, start, err)
+		if err == nil {
+			return &ClassResolution{Class: overcommitClass, Source: ClassSourcePod}, nil
+		}
+		resolutionError(errorPodClass)
+		podlog.Error(err, "Error getting the overcommit class", "overcommitClassLabel", value)
+	}
+	return resolveNamespaceClass(ctx, pod, k8sClient, label)
+}
+
+// resolveNamespaceClass resolves the OvercommitClass from the namespace label or falls back to the default class.
+func resolveNamespaceClass(ctx context.Context, pod *corev1.Pod, k8sClient client.Client, label string) (*ClassResolution, error) {
 	var ns corev1.Namespace
-	err := k8sClient.Get(ctx, client.ObjectKey{Name: namespaceName}, &ns)
+	start := time.Now()
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: pod.Namespace}, &ns)
+	observeLookup(lookupNamespace, "", start, err)
 	if err != nil {
-		podlog.Error(err, "Error getting the namespace", "namespace", namespaceName)
-		return overcommitResolution{cpuValue: 1.0, memoryValue: 1.0, ownerName: ownerName, ownerKind: ownerKind}
+		resolutionError(errorNamespaceLookup)
+		return nil, fmt.Errorf("error getting the namespace %s: %w", pod.Namespace, err)
 	}
 
 	// Check if the overcommit class label is in the namespace
 	if val, ok := ns.Labels[label]; ok {
 		podlog.Info("Namespace class found", "class", val)
-		overcommitClass, err := utils.GetOvercommitClassSpec(ctx, val, k8sClient)
+		overcommitClass := &overcommit.OvercommitClass{}
+		start := time.Now()
+		err := k8sClient.Get(ctx, client.ObjectKey{Name: val}, overcommitClass)
+		observeLookup(lookupClass, val, start, err)
 		if err != nil {
-			podlog.Error(err, "Error getting the overcommit class", "overcommitClassLabel", val)
-			return overcommitResolution{cpuValue: 1.0, memoryValue: 1.0, ownerName: ownerName, ownerKind: ownerKind}
-		}
-		return overcommitResolution{
-			className:   val,
-			cpuValue:    overcommitClass.CpuOvercommit,
-			memoryValue: overcommitClass.MemoryOvercommit,
-			ownerName:   ownerName,
-			ownerKind:   ownerKind,
-			resolved:    true,
+			resolutionError(errorNamespaceClass)
+			return nil, fmt.Errorf("error getting OvercommitClass with name '%s': %w", val, err)
 		}
+		return &ClassResolution{Class: overcommitClass, Source: ClassSourceNamespace}, nil
 	}
 
 	podlog.Info("Overcommit class not found in the namespace, using the default", "namespace", ns.Name)
-	defaultClass, err := utils.GetDefaultClass(ctx, k8sClient)
+	var overcommitClasses overcommit.OvercommitClassList
+	start = time.Now()
+	err = k8sClient.List(ctx, &overcommitClasses)
+	observeLookup(lookupClass, "", start, err)
 	if err != nil {
-		podlog.Error(err, "Error getting the default overcommit class")
-		return overcommitResolution{cpuValue: 1.0, memoryValue: 1.0, ownerName: ownerName, ownerKind: ownerKind}
+		resolutionError(errorClassList)
+		return nil, fmt.Errorf("error listing OvercommitClass: %w", err)
 	}
-	return overcommitResolution{
-		className:   defaultClass.Name,
-		cpuValue:    defaultClass.Spec.CpuOvercommit,
-		memoryValue: defaultClass.Spec.MemoryOvercommit,
-		ownerName:   ownerName,
-		ownerKind:   ownerKind,
-		resolved:    true,
+	for i := range overcommitClasses.Items {
+		if overcommitClasses.Items[i].Spec.IsDefault {
+			return &ClassResolution{Class: &overcommitClasses.Items[i], Source: ClassSourceDefault}, nil
+		}
 	}
+	resolutionError(errorNoDefaultClass)
+	return nil, nil
 }
 
-func checkOvercommitType(ctx context.Context, pod corev1.Pod, client client.Client) overcommitResolution {
-	ownerName, ownerKind, err := utils.GetPodOwner(ctx, client, &pod)
+// Route is how the mutating webhooks handle a pod being created.
+type Route struct {
+	// Webhook is the class whose webhook the webhook configurations call for the pod, nil when none is called.
+	Webhook *overcommit.OvercommitClass
+	// Class is the class applied to the pod and Source where it was resolved from, nil when the pod is left
+	// untouched.
+	Class  *overcommit.OvercommitClass
+	Source ClassSource
+	// Reason explains why the pod is left untouched.
+	Reason string
+}
+
+// RouteClass routes a pod with the labels created in the namespace the way the webhook configurations and the
+// webhooks do, from the Overcommit and the existing classes. Like the object selectors of the webhook
+// configurations, the class label of the pod selects the webhook of its class and pods without it go to the
+// webhook of the default class, which resolves the class label of the namespace. Suspended classes, and every
+// class while the Overcommit is paused, have no webhook configuration, and the namespace exclusions are the ones
+// of the selected webhook. The error reports invalid exclusions, the pod then being left untouched.
+func RouteClass(overcommitResource *overcommit.Overcommit, overcommitClasses []overcommit.OvercommitClass, namespace *corev1.Namespace, labels map[string]string) (Route, error) {
+	if overcommitResource.Spec.Paused {
+		return Route{Reason: "the Overcommit is paused, the webhooks are removed"}, nil
+	}
+	label := overcommitResource.Spec.OvercommitLabel
+	findClass := func(name string) *overcommit.OvercommitClass {
+		for i := range overcommitClasses {
+			if overcommitClasses[i].Name == name {
+				return &overcommitClasses[i]
+			}
+		}
+		return nil
+	}
+
+	route := Route{Source: ClassSourcePod}
+	if value, ok := labels[label]; ok && label != "" {
+		if route.Webhook = findClass(value); route.Webhook == nil {
+			return Route{Reason: fmt.Sprintf("the class label of the pod names OvercommitClass %s, which does not exist", value)}, nil
+		}
+	} else {
+		route.Source = ClassSourceDefault
+		for i := range overcommitClasses {
+			if overcommitClasses[i].Spec.IsDefault {
+				route.Webhook = &overcommitClasses[i]
+			}
+		}
+		if route.Webhook == nil {
+			return Route{Reason: "no OvercommitClass is the default one"}, nil
+		}
+	}
+	if route.Webhook.Spec.Suspended {
+		return Route{Reason: fmt.Sprintf("OvercommitClass %s is suspended, its webhook is removed", route.Webhook.Name)}, nil
+	}
+	excluded, err := exclusions.ForClass(route.Webhook).Excludes(namespace)
 	if err != nil {
-		podlog.Error(err, "Error getting the pod owner")
-		// Non-fatal: continue with empty owner info
+		return Route{Reason: fmt.Sprintf("the namespace exclusions of OvercommitClass %s are invalid: %v", route.Webhook.Name, err)},
+			fmt.Errorf("invalid exclusions of OvercommitClass %s: %w", route.Webhook.Name, err)
 	}
+	if excluded {
+		return Route{Reason: fmt.Sprintf("namespace %s is excluded by OvercommitClass %s", namespace.Name, route.Webhook.Name)}, nil
+	}
+
+	route.Class = route.Webhook
+	if value, ok := namespace.Labels[label]; ok && label != "" && route.Source == ClassSourceDefault {
+		route.Class, route.Source = findClass(value), ClassSourceNamespace
+		if route.Class == nil {
+			route.Reason = fmt.Sprintf("the class label of the namespace names OvercommitClass %s, which does not exist", value)
+		} else if route.Class.Spec.Suspended {
+			route.Class, route.Reason = nil, fmt.Sprintf("OvercommitClass %s is suspended", value)
+		}
+	}
+	return route, nil
+}
 
-	label, err := utils.GetOvercommitLabel(ctx, client)
+// resolutionValues turns a class resolution into the values used for the mutation.
+func resolutionValues(resolution *ClassResolution, err error, ownerName, ownerKind string) overcommitResolution {
 	if err != nil {
-		podlog.Error(err, "Error getting the overcommit label")
+		podlog.Error(err, "Error resolving the overcommit class")
 		return overcommitResolution{cpuValue: 1.0, memoryValue: 1.0, ownerName: ownerName, ownerKind: ownerKind}
 	}
-	//  Check if the pod has the overcommit class label
-	value, exists := pod.Labels[label]
-	podlog.Info(
-		"Checking if pod has overcommit class label",
-		"overcommitClassLabel", value,
-		"exists", exists,
-	)
-	if exists {
-		// Overcommit class found in pod
-		overcommitClass, err := utils.GetOvercommitClassSpec(ctx, value, client)
-		if err != nil {
-			podlog.Error(err, "Error getting the overcommit class", "overcommitClassLabel", value)
-			// Overcommit class not found or some error, fall back to namespace/default
-			return getNamespaceOvercommit(ctx, &pod, client, label, ownerName, ownerKind)
-		}
-		return overcommitResolution{
-			className:   value,
-			cpuValue:    overcommitClass.CpuOvercommit,
-			memoryValue: overcommitClass.MemoryOvercommit,
-			ownerName:   ownerName,
-			ownerKind:   ownerKind,
-			resolved:    true,
-		}
+	if resolution == nil {
+		podlog.Info("No overcommit class found for the pod")
+		return overcommitResolution{cpuValue: 1.0, memoryValue: 1.0, ownerName: ownerName, ownerKind: ownerKind}
+	}
+	values := classResolution(resolution.Class.Name, &resolution.Class.Spec, ownerName, ownerKind)
+	values.source = resolution.Source
+	values.classGeneration = resolution.Class.Generation
+	return values
+}
+
+func checkOvercommitType(ctx context.Context, pod corev1.Pod, client client.Client) overcommitResolution {
+	start := time.Now()
+	overcommitResource, err := utils.GetOvercommit(ctx, client)
+	observeLookup(lookupOvercommit, "", start, err)
+	if err != nil {
+		resolutionError(errorOvercommitLookup)
+		podlog.Error(err, "Error getting the overcommit label")
+		return overcommitResolution{cpuValue: 1.0, memoryValue: 1.0}
+	}
+	// Emergency kill switch, no pod is mutated while the Overcommit is paused
+	if overcommitResource.Spec.Paused {
+		return overcommitResolution{cpuValue: 1.0, memoryValue: 1.0, skipReason: skipReasonPaused}
+	}
+	label := overcommitResource.Spec.OvercommitLabel
+
+	resolution, err := ResolveClass(ctx, &pod, client, label)
+	values := resolutionValues(resolution, err, "", "")
+	if err != nil || resolution == nil {
+		metrics.K8sOvercommitOperatorResolutionsTotal.WithLabelValues("", pathFallback).Inc()
+	} else {
+		metrics.K8sOvercommitOperatorResolutionsTotal.WithLabelValues(resolution.Class.Name, sourcePaths[resolution.Source]).Inc()
+	}
+	if !values.resolved {
+		return values
 	}
 
-	// Overcommit class not found, checking the namespace
-	podlog.Info("Overcommit class label not found in pod, checking the namespace")
-	return getNamespaceOvercommit(ctx, &pod, client, label, ownerName, ownerKind)
+	// The owner is only reported in the metrics of the mutated pods
+	start = time.Now()
+	values.ownerName, values.ownerKind, err = utils.GetPodOwner(ctx, client, &pod)
+	observeLookup(lookupOwner, values.className, start, err)
+	if err != nil {
+		resolutionError(errorOwnerLookup)
+		podlog.Error(err, "Error getting the pod owner")
+		// Non-fatal: continue with empty owner info
+	}
+	return values
 }
@@ -10,16 +10,94 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
 )
 
 var _ = Describe("Overcommit Functions", func() {
 
-	Describe("getNamespaceOvercommit", func() {
-		It("should return the correct overcommit values from the namespace", func() {
-			resolution := getNamespaceOvercommit(context.TODO(), testPod, k8sClient, "inditex.com/overcommit-class", "ownerName", "ownerKind")
-			Expect(resolution.className).To(Equal("test-class"))
-			Expect(resolution.cpuValue).To(Equal(0.5))
-			Expect(resolution.memoryValue).To(Equal(0.5))
+	Describe("ResolveClass", func() {
+		It("should resolve the class from the namespace when the pod has no class label", func() {
+			pod := testPod.DeepCopy()
+			delete(pod.Labels, "inditex.com/overcommit-class")
+
+			resolution, err := ResolveClass(context.TODO(), pod, k8sClient, "inditex.com/overcommit-class")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolution).NotTo(BeNil())
+			Expect(resolution.Source).To(Equal(ClassSourceNamespace))
+			Expect(resolution.Class.Name).To(Equal("test-class"))
+			Expect(resolution.Class.Spec.CpuOvercommit).To(Equal(0.5))
+			Expect(resolution.Class.Spec.MemoryOvercommit).To(Equal(0.5))
+		})
+
+		It("should not record the class requested by a pod in the lookup metric when it does not exist", func() {
+			pod := testPod.DeepCopy()
+			pod.Labels["inditex.com/overcommit-class"] = "nonexistent-class"
+
+			_, err := ResolveClass(context.TODO(), pod, k8sClient, "inditex.com/overcommit-class")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metrics.K8sOvercommitOperatorLookupDurationSeconds.DeleteLabelValues(lookupClass, "nonexistent-class", lookupNotFound)).To(BeFalse())
+			Expect(metrics.K8sOvercommitOperatorLookupDurationSeconds.DeleteLabelValues(lookupClass, "", lookupNotFound)).To(BeTrue())
+		})
+	})
+
+	Describe("RouteClass", func() {
+		var (
+			overcommitResource *overcommit.Overcommit
+			overcommitClasses  []overcommit.OvercommitClass
+			namespace          *corev1.Namespace
+		)
+
+		BeforeEach(func() {
+			overcommitResource = &overcommit.Overcommit{Spec: overcommit.OvercommitSpec{OvercommitLabel: "inditex.com/overcommit-class"}}
+			overcommitClasses = []overcommit.OvercommitClass{
+				{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Spec: overcommit.OvercommitClassSpec{IsDefault: true}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "high"}},
+			}
+			namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps", Labels: map[string]string{}}}
+		})
+
+		It("should route the pods with a class label to the webhook of their class", func() {
+			route, err := RouteClass(overcommitResource, overcommitClasses, namespace, map[string]string{"inditex.com/overcommit-class": "high"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Webhook.Name).To(Equal("high"))
+			Expect(route.Class.Name).To(Equal("high"))
+			Expect(route.Source).To(Equal(ClassSourcePod))
+		})
+
+		It("should route the other pods to the default class, resolving the class label of the namespace", func() {
+			namespace.Labels["inditex.com/overcommit-class"] = "high"
+
+			route, err := RouteClass(overcommitResource, overcommitClasses, namespace, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Webhook.Name).To(Equal("default"))
+			Expect(route.Class.Name).To(Equal("high"))
+			Expect(route.Source).To(Equal(ClassSourceNamespace))
+		})
+
+		It("should leave the pods untouched while the Overcommit is paused", func() {
+			overcommitResource.Spec.Paused = true
+
+			route, err := RouteClass(overcommitResource, overcommitClasses, namespace, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Webhook).To(BeNil())
+			Expect(route.Class).To(BeNil())
+			Expect(route.Reason).To(Equal("the Overcommit is paused, the webhooks are removed"))
+		})
+
+		It("should leave the pods of a namespace naming a suspended class untouched", func() {
+			overcommitClasses[1].Spec.Suspended = true
+			namespace.Labels["inditex.com/overcommit-class"] = "high"
+
+			route, err := RouteClass(overcommitResource, overcommitClasses, namespace, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(route.Webhook.Name).To(Equal("default"))
+			Expect(route.Class).To(BeNil())
+			Expect(route.Reason).To(Equal("OvercommitClass high is suspended"))
 		})
 	})
 
@@ -40,5 +118,35 @@ var _ = Describe("Overcommit Functions", func() {
 			Expect(resolution.cpuValue).To(Equal(0.5))
 			Expect(resolution.memoryValue).To(Equal(0.5))
 		})
+
+		It("should skip the mutation while the overcommit is paused", func() {
+			paused := &overcommit.Overcommit{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(testOvercommit), paused)).To(Succeed())
+			paused.Spec.Paused = true
+			Expect(k8sClient.Update(context.TODO(), paused)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(testOvercommit), paused)).To(Succeed())
+				paused.Spec.Paused = false
+				Expect(k8sClient.Update(context.TODO(), paused)).To(Succeed())
+			})
+
+			resolution := checkOvercommitType(context.TODO(), *testPod, k8sClient)
+			Expect(resolution.skipReason).To(Equal(skipReasonPaused))
+			Expect(resolution.cpuValue).To(Equal(1.0))
+			Expect(resolution.memoryValue).To(Equal(1.0))
+		})
+	})
+
+	Describe("classResolution", func() {
+		It("should skip the mutation of suspended classes", func() {
+			resolution := classResolution("test-class", &overcommit.OvercommitClassSpec{
+				CpuOvercommit:    0.5,
+				MemoryOvercommit: 0.5,
+				Suspended:        true,
+			}, "ownerName", "ownerKind")
+			Expect(resolution.skipReason).To(Equal(skipReasonSuspended))
+			Expect(resolution.resolved).To(BeFalse())
+			Expect(resolution.cpuValue).To(Equal(1.0))
+		})
 	})
 })
@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AnnotationOvercommitDecision records on the mutated pods the Decision of the webhook as compact JSON.
+	AnnotationOvercommitDecision = "overcommit.inditex.dev/decision"
+	// AnnotationOvercommitRevert set to "true" on a pod restores its original requests and disables its mutation.
+	AnnotationOvercommitRevert = "overcommit.inditex.dev/revert"
+)
+
+// Decision records how the overcommit of a pod was decided, so a mutation can be explained and the original
+// requests recovered without reading the logs of the webhook.
+type Decision struct {
+	// Class is the OvercommitClass applied to the pod.
+	Class string `json:"class"`
+	// Source is where the class was resolved from, empty when the pod did not resolve to any class.
+	Source ClassSource `json:"source,omitempty"`
+	// ClassGeneration is the generation of the class when the pod was mutated.
+	ClassGeneration int64 `json:"classGeneration,omitempty"`
+	// CpuOvercommit and MemoryOvercommit are the ratios applied to the limits.
+	CpuOvercommit    float64 `json:"cpu"`
+	MemoryOvercommit float64 `json:"memory"`
+	// Containers are the resources of each container before and after the mutation.
+	Containers []ContainerDecision `json:"containers,omitempty"`
+	// Webhook is the webhook pod that mutated the pod.
+	Webhook string `json:"webhook,omitempty"`
+	// Pass is the mutation pass that wrote the decision, increased on every reinvocation of the webhook.
+	Pass int `json:"pass"`
+}
+
+// ContainerDecision records the resources of a container before and after the mutation.
+type ContainerDecision struct {
+	Name string `json:"name"`
+	Init bool   `json:"init,omitempty"`
+	// Original are the requests and limits of the container before the mutation.
+	Original corev1.ResourceRequirements `json:"original"`
+	// Requests are the requests computed by the webhook.
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	// LimitsHash identifies the limits the requests were computed from.
+	LimitsHash string `json:"limitsHash,omitempty"`
+	// Pass is the mutation pass that computed the requests, 0 when the container was never mutated.
+	Pass int `json:"pass,omitempty"`
+}
+
+// newDecision starts the decision of a pod, recording the resources of its containers before the mutation.
+// The containers already processed by a previous pass of the same class, with the same limits and requests,
+// are carried over and left untouched, the others are processed in the pass of the decision. The original
+// requests recorded by a previous decision are kept for the containers whose requests were not changed since,
+// by the user or another webhook. The init containers are only processed when withInitContainers is set.
+func newDecision(pod *corev1.Pod, className string, resolution overcommitResolution, instance string, previous *Decision, withInitContainers bool) *Decision {
+	decision := &Decision{
+		Class:            className,
+		Source:           resolution.source,
+		ClassGeneration:  resolution.classGeneration,
+		CpuOvercommit:    resolution.cpuValue,
+		MemoryOvercommit: resolution.memoryValue,
+		Webhook:          instance,
+		Pass:             1,
+	}
+	sameClass := false
+	if previous != nil {
+		decision.Pass = previous.Pass + 1
+		sameClass = previous.Class == className && previous.CpuOvercommit == resolution.cpuValue && previous.MemoryOvercommit == resolution.memoryValue
+	}
+	for _, container := range pod.Spec.InitContainers {
+		decision.record(container, true, previous, sameClass, withInitContainers)
+	}
+	for _, container := range pod.Spec.Containers {
+		decision.record(container, false, previous, sameClass, true)
+	}
+	return decision
+}
+
+// record adds a container to the decision, carrying over its record when it was already processed.
+func (d *Decision) record(container corev1.Container, init bool, previous *Decision, sameClass, mutable bool) {
+	hash := limitsHash(container.Resources.Limits)
+	recorded := previous.container(container.Name, init)
+	if recorded != nil && (sameClass || !mutable) && recorded.LimitsHash == hash && resourceListsEqual(recorded.Requests, container.Resources.Requests) {
+		d.Containers = append(d.Containers, *recorded)
+		return
+	}
+	entry := ContainerDecision{Name: container.Name, Init: init, Original: previous.original(container, init), LimitsHash: hash}
+	if mutable {
+		entry.Pass = d.Pass
+	} else {
+		entry.Requests = container.Resources.Requests.DeepCopy()
+	}
+	d.Containers = append(d.Containers, entry)
+}
+
+// apply mutates the containers processed in the pass of the decision from their original requests, recording
+// the computed requests.
+func (d *Decision) apply(containers []corev1.Container, init bool) {
+	for i := range containers {
+		recorded := d.container(containers[i].Name, init)
+		if recorded == nil || recorded.Pass != d.Pass {
+			continue
+		}
+		containers[i].Resources.Requests = recorded.Original.Requests.DeepCopy()
+		mutateContainers(containers[i:i+1], d.CpuOvercommit, d.MemoryOvercommit)
+		recorded.Requests = containers[i].Resources.Requests.DeepCopy()
+	}
+}
+
+// processed returns the names of the containers processed in the pass of the decision.
+func (d *Decision) processed() []string {
+	var names []string
+	for _, c := range d.Containers {
+		if c.Pass == d.Pass {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// container returns the record of a container, nil when the decision has none.
+func (d *Decision) container(name string, init bool) *ContainerDecision {
+	if d == nil {
+		return nil
+	}
+	for i := range d.Containers {
+		if d.Containers[i].Name == name && d.Containers[i].Init == init {
+			return &d.Containers[i]
+		}
+	}
+	return nil
+}
+
+// original returns the resources of the container before any mutation: its current limits, and the original
+// requests recorded by the decision unless its requests were changed after the mutation.
+func (d *Decision) original(container corev1.Container, init bool) corev1.ResourceRequirements {
+	original := *container.Resources.DeepCopy()
+	if recorded := d.container(container.Name, init); recorded != nil && resourceListsEqual(recorded.Requests, container.Resources.Requests) {
+		original.Requests = recorded.Original.Requests.DeepCopy()
+	}
+	return original
+}
+
+// resourceListsEqual compares two resource lists by value, a nil list being equal to an empty one.
+func resourceListsEqual(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, ok := b[name]
+		if !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// limitsHash returns a short hash identifying the limits of a container.
+func limitsHash(limits corev1.ResourceList) string {
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	hash := fnv.New32a()
+	for _, name := range names {
+		quantity := limits[corev1.ResourceName(name)]
+		fmt.Fprintf(hash, "%s=%s;", name, quantity.String())
+	}
+	return strconv.FormatUint(uint64(hash.Sum32()), 16)
+}
+
+// previousDecision returns the decision recorded on the pod by a previous mutation, if any. An unreadable
+// decision is ignored, the current requests of the pod being taken as the original ones.
+func previousDecision(pod *corev1.Pod) *Decision {
+	decision, err := GetDecision(pod)
+	if err != nil {
+		podlog.Error(err, "Error reading the overcommit decision, ignoring it", "pod", pod.Name)
+		return nil
+	}
+	return decision
+}
+
+// setDecisionAnnotation writes the decision on the pod.
+func setDecisionAnnotation(pod *corev1.Pod, decision *Decision) error {
+	record, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[AnnotationOvercommitDecision] = string(record)
+	return nil
+}
+
+// GetDecision returns the decision recorded on a pod, nil when the pod has none.
+func GetDecision(pod *corev1.Pod) (*Decision, error) {
+	record, ok := pod.Annotations[AnnotationOvercommitDecision]
+	if !ok {
+		return nil, nil
+	}
+	decision := &Decision{}
+	if err := json.Unmarshal([]byte(record), decision); err != nil {
+		return nil, err
+	}
+	return decision, nil
+}
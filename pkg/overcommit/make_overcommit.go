@@ -8,8 +8,10 @@ package overcommit
 import (
 	"context"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -20,11 +22,28 @@ import (
 
 const (
 	// AnnotationOvercommitApplied is set on pods after overcommit mutation to ensure idempotency.
-	AnnotationOvercommitApplied = "overcommit.inditex.dev/applied"
+	AnnotationOvercommitApplied = overcommit.AppliedAnnotation
 )
 
 var podlog = logf.Log.WithName("overcommit")
 
+// Options configures the overcommit mutation.
+type Options struct {
+	// ClassName is the OvercommitClass served by the webhook, used when the pod does not resolve to any class.
+	ClassName string
+	// Instance is the name of the webhook pod, recorded in the decision of the mutated pods.
+	Instance string
+}
+
+// Result tells what the mutation did to a pod.
+type Result struct {
+	// Outcome is mutated, fallback, skipped, unchanged or reverted, like in the mutation metrics.
+	Outcome string
+	// SkipReason is why a skipped pod was left untouched, paused or suspended.
+	SkipReason string
+}
+
+// mutateContainers sets the requests of the containers with limits to their limits times the overcommit.
 func mutateContainers(containers []corev1.Container, cpuValue float64, memoryValue float64) {
 	for i, container := range containers {
 		limits := container.Resources.Limits
@@ -51,33 +70,51 @@ func mutateContainers(containers []corev1.Container, cpuValue float64, memoryVal
 	}
 }
 
-func Overcommit(ctx context.Context, pod *corev1.Pod, recorder record.EventRecorder, client client.Client) {
-	webhookClassName := os.Getenv("OVERCOMMIT_CLASS_NAME")
+// Overcommit sets the requests of the containers and init containers of a pod from their limits. The requests
+// are always computed from the original requests recorded at the first mutation, so reinvocations and class
+// switches give the same result as a single mutation, and a reinvocation only mutates the containers added or
+// changed since the previous pass. It returns what the mutation did to the pod.
+func Overcommit(ctx context.Context, pod *corev1.Pod, recorder record.EventRecorder, client client.Client, opts Options) Result {
+	start := time.Now()
 	resolution := checkOvercommitType(ctx, *pod, client)
 	className := resolution.className
 	if className == "" {
-		className = webhookClassName
+		className = opts.ClassName
 	}
+	outcome := outcomeMutated
+	if !resolution.resolved {
+		outcome = outcomeFallback
+	}
+	defer func() { observeMutation(className, outcome, start) }()
 
 	metrics.K8sOvercommitOperatorPodsRequestedTotal.WithLabelValues(className).Inc()
 
-	// Idempotency: skip if this pod was already mutated by this class
-	if pod.Annotations != nil {
-		if applied, ok := pod.Annotations[AnnotationOvercommitApplied]; ok && applied == className {
-			podlog.Info("Pod already mutated by this overcommit class, skipping", "pod", pod.Name, "class", className)
-			return
-		}
+	previous := previousDecision(pod)
+	if revertRequested(pod, className, previous, true) {
+		outcome = outcomeReverted
+		return Result{Outcome: outcome}
 	}
 
-	mutateContainers(pod.Spec.Containers, resolution.cpuValue, resolution.memoryValue)
+	if mutationDisabled(pod, className, resolution) {
+		outcome = outcomeSkipped
+		return Result{Outcome: outcome, SkipReason: resolution.skipReason}
+	}
 
-	// Also mutate init containers on regular CREATE/UPDATE
-	if len(pod.Spec.InitContainers) > 0 {
-		mutateContainers(pod.Spec.InitContainers, resolution.cpuValue, resolution.memoryValue)
+	decision := newDecision(pod, className, resolution, opts.Instance, previous, true)
+	// Only the containers not processed yet, like sidecars injected by later webhooks, are mutated on reinvocation
+	processed := decision.processed()
+	if previous != nil && len(processed) == 0 {
+		podlog.Info("Pod already mutated by this overcommit class, skipping", "pod", pod.Name, "class", className)
+		outcome = outcomeUnchanged
+		return Result{Outcome: outcome}
 	}
+	decision.apply(pod.Spec.Containers, false)
+	decision.apply(pod.Spec.InitContainers, true)
 
-	// Mark the pod as mutated to prevent double-application on reinvocation
 	setOvercommitAnnotation(pod, className, resolution.cpuValue, resolution.memoryValue)
+	if err := setDecisionAnnotation(pod, decision); err != nil {
+		podlog.Error(err, "Error recording the overcommit decision", "pod", pod.Name)
+	}
 
 	metrics.K8sOvercommitOperatorMutatedPodsTotal.WithLabelValues(className).Inc()
 	if resolution.resolved {
@@ -88,29 +125,49 @@ func Overcommit(ctx context.Context, pod *corev1.Pod, recorder record.EventRecor
 		pod,
 		corev1.EventTypeNormal,
 		"OvercommitApplied",
-		"Applied overcommit to Pod '%s': OvercommitClass = %s, CPU Overcommit = %.2f, Memory Overcommit = %.2f",
+		"Applied overcommit to Pod '%s': OvercommitClass = %s, CPU Overcommit = %.2f, Memory Overcommit = %.2f, Containers = %s",
 		pod.Name,
 		className,
 		resolution.cpuValue,
 		resolution.memoryValue,
+		strings.Join(processed, ","),
 	)
+	return Result{Outcome: outcome}
 }
 
-func OvercommitOnResize(ctx context.Context, pod *corev1.Pod, recorder record.EventRecorder, client client.Client) {
-	webhookClassName := os.Getenv("OVERCOMMIT_CLASS_NAME")
+// OvercommitOnResize recomputes the requests of the containers of a pod being resized from their new limits.
+// The requests left as computed by the previous mutation are recomputed from the recorded original requests,
+// while the requests set by the resize become the new original requests. The pods/resize subresource drops
+// the changes to the annotations, the decision is recorded again afterwards by the resize controller.
+func OvercommitOnResize(ctx context.Context, pod *corev1.Pod, recorder record.EventRecorder, client client.Client, opts Options) {
+	start := time.Now()
 	resolution := checkOvercommitType(ctx, *pod, client)
 	className := resolution.className
 	if className == "" {
-		className = webhookClassName
+		className = opts.ClassName
 	}
+	outcome := outcomeMutated
+	if !resolution.resolved {
+		outcome = outcomeFallback
+	}
+	defer func() { observeMutation(className, outcome, start) }()
 
 	metrics.K8sOvercommitOperatorPodsRequestedTotal.WithLabelValues(className).Inc()
 
-	// On resize: only mutate regular containers, skip init containers.
-	mutateContainers(pod.Spec.Containers, resolution.cpuValue, resolution.memoryValue)
+	previous := previousDecision(pod)
+	if revertRequested(pod, className, previous, false) {
+		outcome = outcomeReverted
+		return
+	}
 
-	// Update annotation with new values after resize
-	setOvercommitAnnotation(pod, className, resolution.cpuValue, resolution.memoryValue)
+	if mutationDisabled(pod, className, resolution) {
+		outcome = outcomeSkipped
+		return
+	}
+
+	// On resize: only mutate regular containers, skip init containers.
+	decision := newDecision(pod, className, resolution, opts.Instance, previous, false)
+	decision.apply(pod.Spec.Containers, false)
 
 	metrics.K8sOvercommitOperatorMutatedPodsTotal.WithLabelValues(className).Inc()
 	if resolution.resolved {
@@ -129,6 +186,63 @@ func OvercommitOnResize(ctx context.Context, pod *corev1.Pod, recorder record.Ev
 	)
 }
 
+// revertRequested reports, and records in the metrics, whether the pod asks to be left with its original requests
+// with the revert annotation. The requests computed by a previous mutation are then restored, the init containers
+// only when withInitContainers is set, and the annotations of the mutation removed.
+func revertRequested(pod *corev1.Pod, className string, previous *Decision, withInitContainers bool) bool {
+	if pod.Annotations[AnnotationOvercommitRevert] != "true" {
+		return false
+	}
+	podlog.Info("Overcommit revert requested, restoring the original requests", "pod", pod.Name, "class", className)
+	restore(pod, previous, withInitContainers)
+	metrics.K8sOvercommitOperatorPodsNotMutatedTotal.WithLabelValues(className, pod.GenerateName, pod.Namespace, skipReasonReverted).Inc()
+	return true
+}
+
+// Restore takes a mutated pod back to its state before the overcommit: the requests still computed by its
+// recorded decision are restored to the original ones and the annotations of the mutation removed.
+func Restore(pod *corev1.Pod) error {
+	decision, err := GetDecision(pod)
+	if err != nil {
+		return err
+	}
+	restore(pod, decision, true)
+	return nil
+}
+
+// restore restores the original requests of the containers recorded by the decision, the init containers only
+// when withInitContainers is set, and removes the annotations of the mutation.
+func restore(pod *corev1.Pod, decision *Decision, withInitContainers bool) {
+	if decision != nil {
+		restoreUnchanged(pod.Spec.Containers, false, decision)
+		if withInitContainers {
+			restoreUnchanged(pod.Spec.InitContainers, true, decision)
+		}
+	}
+	for _, annotation := range []string{AnnotationOvercommitApplied, AnnotationOvercommitDecision, "overcommit.inditex.dev/cpu", "overcommit.inditex.dev/memory"} {
+		delete(pod.Annotations, annotation)
+	}
+}
+
+// restoreUnchanged restores the original requests of the containers whose requests are still the ones computed
+// by the decision.
+func restoreUnchanged(containers []corev1.Container, init bool, decision *Decision) {
+	for i := range containers {
+		containers[i].Resources.Requests = decision.original(containers[i], init).Requests
+	}
+}
+
+// mutationDisabled reports, and records in the metrics, whether the pod must be left untouched because
+// the Overcommit is paused or its class is suspended.
+func mutationDisabled(pod *corev1.Pod, className string, resolution overcommitResolution) bool {
+	if resolution.skipReason == "" {
+		return false
+	}
+	podlog.Info("Overcommit mutation is disabled, skipping", "pod", pod.Name, "class", className, "reason", resolution.skipReason)
+	metrics.K8sOvercommitOperatorPodsNotMutatedTotal.WithLabelValues(className, pod.GenerateName, pod.Namespace, resolution.skipReason).Inc()
+	return true
+}
+
 // setOvercommitAnnotation marks the pod as having been mutated by the overcommit webhook.
 func setOvercommitAnnotation(pod *corev1.Pod, className string, cpuValue, memoryValue float64) {
 	if pod.Annotations == nil {
@@ -7,8 +7,8 @@ package overcommit
 
 import (
 	"context"
-	"os"
 
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -92,7 +92,7 @@ var _ = Describe("Overcommit", func() {
 
 	Describe("makeOvercommit", func() {
 		It("should apply overcommit to containers", func() {
-			Overcommit(context.Background(), pod, recorder, k8sClient)
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
 
 			Expect(pod.Spec.Containers[0].Resources.Requests).To(Equal(expectedRequests))
 			Expect(pod.Annotations[AnnotationOvercommitApplied]).To(Equal("test-class"))
@@ -101,19 +101,102 @@ var _ = Describe("Overcommit", func() {
 
 	Describe("Overcommit", func() {
 
-		BeforeEach(func() {
-			os.Setenv("OVERCOMMIT_CLASS_NAME", "test-class")
+		It("should mutate pod containers and record an event", func() {
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
+
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(Equal(expectedRequests))
+			Expect(pod.Annotations[AnnotationOvercommitApplied]).To(Equal("test-class"))
 		})
 
-		AfterEach(func() {
-			os.Unsetenv("OVERCOMMIT_CLASS_NAME")
+	})
+
+	Describe("Decision", func() {
+
+		It("should record the original and computed requests of the containers", func() {
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class", Instance: "webhook-0"})
+
+			decision, err := GetDecision(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decision).NotTo(BeNil())
+			Expect(decision.Class).To(Equal("test-class"))
+			Expect(decision.Webhook).To(Equal("webhook-0"))
+			Expect(decision.Containers).To(HaveLen(1))
+			Expect(decision.Containers[0].Original.Requests).To(BeEmpty())
+			Expect(decision.Containers[0].Original.Limits.Cpu().MilliValue()).To(Equal(int64(1000)))
+			Expect(decision.Containers[0].Requests.Cpu().MilliValue()).To(Equal(expectedRequests.Cpu().MilliValue()))
 		})
 
-		It("should mutate pod containers and record an event", func() {
-			Overcommit(context.Background(), pod, recorder, k8sClient)
+		It("should recompute the requests from the original ones when mutated again", func() {
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
 
 			Expect(pod.Spec.Containers[0].Resources.Requests).To(Equal(expectedRequests))
-			Expect(pod.Annotations[AnnotationOvercommitApplied]).To(Equal("test-class"))
+			decision, err := GetDecision(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decision.Containers[0].Original.Requests).To(BeEmpty())
+		})
+
+		It("should keep the requests changed after the mutation as the original ones", func() {
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
+			previous, err := GetDecision(pod)
+			Expect(err).NotTo(HaveOccurred())
+
+			pod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("300m")
+			decision := newDecision(pod, "test-class", overcommitResolution{cpuValue: 0.5, memoryValue: 0.5}, "", previous, true)
+
+			Expect(decision.Containers[0].Original.Requests.Cpu().MilliValue()).To(Equal(int64(300)))
+		})
+
+		It("should only mutate the containers added since the previous pass", func() {
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+				Name: "sidecar",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+				},
+			})
+
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
+
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(Equal(expectedRequests))
+			Expect(pod.Spec.Containers[1].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(100)))
+			decision, err := GetDecision(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decision.Pass).To(Equal(2))
+			Expect(decision.Containers[0].Pass).To(Equal(1))
+			Expect(decision.Containers[1].Pass).To(Equal(2))
+		})
+
+		It("should restore the original requests when a revert is requested", func() {
+			pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(500)))
+
+			pod.Annotations[AnnotationOvercommitRevert] = "true"
+			Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"})
+
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(HaveLen(1))
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(100)))
+			Expect(pod.Annotations).NotTo(HaveKey(AnnotationOvercommitApplied))
+			Expect(pod.Annotations).NotTo(HaveKey(AnnotationOvercommitDecision))
+		})
+
+		It("should report the outcome and restore the pod to its state before the mutation", func() {
+			pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+			Expect(Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"}).Outcome).To(Equal(outcomeMutated))
+			Expect(Overcommit(context.Background(), pod, recorder, k8sClient, Options{ClassName: "test-class"}).Outcome).To(Equal(outcomeUnchanged))
+
+			Expect(Restore(pod)).To(Succeed())
+
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(100)))
+			Expect(pod.Annotations).NotTo(HaveKey(AnnotationOvercommitApplied))
+			Expect(pod.Annotations).NotTo(HaveKey(AnnotationOvercommitDecision))
+		})
+
+		It("should return no decision for pods without the annotation", func() {
+			decision, err := GetDecision(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decision).To(BeNil())
 		})
 
 	})
@@ -137,4 +220,69 @@ var _ = Describe("Overcommit", func() {
 
 	})
 
+	Describe("Rebalance", func() {
+		var overcommitClass *overcommit.OvercommitClass
+
+		BeforeEach(func() {
+			overcommitClass = &overcommit.OvercommitClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-class", Generation: 2},
+				Spec:       overcommit.OvercommitClassSpec{CpuOvercommit: 0.25, MemoryOvercommit: 0.5},
+			}
+			decision := newDecision(pod, "test-class", overcommitResolution{cpuValue: 0.5, memoryValue: 0.5}, "webhook-0", nil, true)
+			decision.apply(pod.Spec.Containers, false)
+			setOvercommitAnnotation(pod, "test-class", 0.5, 0.5)
+			Expect(setDecisionAnnotation(pod, decision)).To(Succeed())
+		})
+
+		It("should recompute the requests from the original ones with the new ratios", func() {
+			changed, err := Rebalance(pod, overcommitClass, "controller-0")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(250)))
+			Expect(pod.Spec.Containers[0].Resources.Requests.Memory().Value()).To(Equal(int64(536870912)))
+			Expect(pod.Annotations).To(HaveKeyWithValue("overcommit.inditex.dev/cpu", "0.2500"))
+			decision, err := GetDecision(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decision.ClassGeneration).To(Equal(int64(2)))
+			Expect(decision.Webhook).To(Equal("controller-0"))
+			Expect(decision.Containers[0].Original.Requests).To(BeEmpty())
+		})
+
+		It("should leave the pods already reflecting the class", func() {
+			overcommitClass.Spec.CpuOvercommit = 0.5
+
+			changed, err := Rebalance(pod, overcommitClass, "controller-0")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+
+		It("should ignore the pods mutated by another class", func() {
+			overcommitClass.Name = "other-class"
+
+			changed, err := Rebalance(pod, overcommitClass, "controller-0")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+
+		It("should keep the original requests of a pod resized without its decision", func() {
+			resized := pod.DeepCopy()
+			_, err := Rebalance(resized, overcommitClass, "controller-0")
+			Expect(err).NotTo(HaveOccurred())
+			pod.Spec.Containers = resized.Spec.Containers
+
+			changed, err := Rebalance(pod, overcommitClass, "controller-0")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().MilliValue()).To(Equal(int64(250)))
+			decision, err := GetDecision(pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decision.Containers[0].Original.Requests).To(BeEmpty())
+		})
+
+	})
+
 })
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	"time"
+
+	"github.com/InditexTech/k8s-overcommit-operator/internal/metrics"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Lookups done to resolve the overcommit of a pod.
+const (
+	lookupOvercommit = "overcommit"
+	lookupClass      = "class"
+	lookupNamespace  = "namespace"
+	lookupOwner      = "owner"
+)
+
+// Outcomes of a lookup.
+const (
+	lookupFound    = "found"
+	lookupNotFound = "not_found"
+	lookupError    = "error"
+)
+
+// Resolution paths of the class of a pod. fallback means no class was resolved and the pod keeps its requests.
+const (
+	pathPodLabel       = "pod_label"
+	pathNamespaceLabel = "namespace_label"
+	pathDefault        = "default"
+	pathFallback       = "fallback"
+)
+
+// Error branches of the resolution of the overcommit of a pod.
+const (
+	errorOvercommitLookup = "overcommit_lookup_failed"
+	errorPodClass         = "pod_class_not_found"
+	errorNamespaceLookup  = "namespace_lookup_failed"
+	errorNamespaceClass   = "namespace_class_not_found"
+	errorClassList        = "class_list_failed"
+	errorNoDefaultClass   = "no_default_class"
+	errorOwnerLookup      = "owner_lookup_failed"
+)
+
+// Outcomes of the mutation of a pod.
+const (
+	outcomeMutated   = "mutated"
+	outcomeFallback  = "fallback"
+	outcomeSkipped   = "skipped"
+	outcomeUnchanged = "unchanged"
+	outcomeReverted  = "reverted"
+)
+
+// sourcePaths are the resolution paths of each class source.
+var sourcePaths = map[ClassSource]string{
+	ClassSourcePod:       pathPodLabel,
+	ClassSourceNamespace: pathNamespaceLabel,
+	ClassSourceDefault:   pathDefault,
+}
+
+// observeLookup records the latency and the outcome of a lookup, className being the class it was done for, if known.
+// The class of a failed lookup comes from a label and may be any value, so it is left empty to bound the series.
+func observeLookup(lookup, className string, start time.Time, err error) {
+	outcome := lookupFound
+	if apierrors.IsNotFound(err) {
+		outcome = lookupNotFound
+	} else if err != nil {
+		outcome = lookupError
+	}
+	if err != nil {
+		className = ""
+	}
+	metrics.K8sOvercommitOperatorLookupDurationSeconds.WithLabelValues(lookup, className, outcome).Observe(time.Since(start).Seconds())
+}
+
+// observeMutation records the end-to-end latency of the mutation of a pod.
+func observeMutation(className, outcome string, start time.Time) {
+	metrics.K8sOvercommitOperatorMutationDurationSeconds.WithLabelValues(className, outcome).Observe(time.Since(start).Seconds())
+}
+
+// resolutionError counts an error branch of the resolution.
+func resolutionError(reason string) {
+	metrics.K8sOvercommitOperatorResolutionErrorsTotal.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2025 2025 INDUSTRIA DE DISEÑO TEXTIL S.A. (INDITEX S.A.)
+// SPDX-FileContributor: enriqueavi@inditex.com
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package overcommit
+
+import (
+	overcommit "github.com/InditexTech/k8s-overcommit-operator/api/v1alphav1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Rebalance recomputes the requests of the containers of a running pod mutated by a class whose ratios changed,
+// from the original requests recorded in its decision, and records the new decision on the pod. Like a resize
+// admitted by the webhook, the init containers are left untouched. It returns false when the pod has no
+// decision of the class, or its decision already records the current ratios of the class.
+func Rebalance(pod *corev1.Pod, overcommitClass *overcommit.OvercommitClass, instance string) (bool, error) {
+	previous, err := GetDecision(pod)
+	if err != nil || previous == nil || previous.Class != overcommitClass.Name {
+		return false, err
+	}
+	spec := overcommitClass.Spec
+	if previous.CpuOvercommit == spec.CpuOvercommit && previous.MemoryOvercommit == spec.MemoryOvercommit {
+		return false, nil
+	}
+
+	resolution := overcommitResolution{
+		className:       overcommitClass.Name,
+		cpuValue:        spec.CpuOvercommit,
+		memoryValue:     spec.MemoryOvercommit,
+		resolved:        true,
+		source:          previous.Source,
+		classGeneration: overcommitClass.Generation,
+	}
+	// The pods/resize subresource drops the annotations, a pod resized by a previous rebalance whose decision
+	// could not be recorded already has the requests computed with the current ratios. They are taken back to
+	// the recorded ones, so they are not mistaken for requests changed by the user.
+	for i := range pod.Spec.Containers {
+		recorded := previous.container(pod.Spec.Containers[i].Name, false)
+		if recorded == nil {
+			continue
+		}
+		target := []corev1.Container{*pod.Spec.Containers[i].DeepCopy()}
+		target[0].Resources.Requests = recorded.Original.Requests.DeepCopy()
+		mutateContainers(target, resolution.cpuValue, resolution.memoryValue)
+		if resourceListsEqual(target[0].Resources.Requests, pod.Spec.Containers[i].Resources.Requests) {
+			pod.Spec.Containers[i].Resources.Requests = recorded.Requests.DeepCopy()
+		}
+	}
+
+	decision := newDecision(pod, overcommitClass.Name, resolution, instance, previous, false)
+	decision.apply(pod.Spec.Containers, false)
+	setOvercommitAnnotation(pod, overcommitClass.Name, resolution.cpuValue, resolution.memoryValue)
+	return true, setDecisionAnnotation(pod, decision)
+}
+
+// Resync records again the decision of a pod whose limits were changed through the pods/resize subresource,
+// which drops the annotations written by the webhook on the resize. The containers whose current requests are
+// the ones computed from their recorded original requests and current limits keep their original requests,
+// the webhook having recomputed them. The others are recorded as not mutated, their current requests becoming
+// the original ones. It returns false when the pod has no decision of the class, or the limits of its
+// containers still match the recorded ones.
+func Resync(pod *corev1.Pod, overcommitClass *overcommit.OvercommitClass, instance string) (bool, error) {
+	previous, err := GetDecision(pod)
+	if err != nil || previous == nil || previous.Class != overcommitClass.Name {
+		return false, err
+	}
+	resized := map[string]corev1.Container{}
+	for _, container := range pod.Spec.Containers {
+		if recorded := previous.container(container.Name, false); recorded != nil && recorded.LimitsHash != "" && recorded.LimitsHash != limitsHash(container.Resources.Limits) {
+			resized[container.Name] = container
+		}
+	}
+	if len(resized) == 0 {
+		return false, nil
+	}
+
+	// The webhook recomputes the requests with the current ratios of the class, the recorded ones are kept
+	// when none of the resized containers was recomputed with them
+	decision := &Decision{
+		Class:            previous.Class,
+		Source:           previous.Source,
+		ClassGeneration:  previous.ClassGeneration,
+		CpuOvercommit:    previous.CpuOvercommit,
+		MemoryOvercommit: previous.MemoryOvercommit,
+		Webhook:          instance,
+		Pass:             previous.Pass + 1,
+	}
+	spec := overcommitClass.Spec
+	for _, container := range resized {
+		if recomputed(container, previous, spec.CpuOvercommit, spec.MemoryOvercommit) {
+			decision.CpuOvercommit, decision.MemoryOvercommit = spec.CpuOvercommit, spec.MemoryOvercommit
+			decision.ClassGeneration = overcommitClass.Generation
+			break
+		}
+	}
+
+	for _, recorded := range previous.Containers {
+		container, ok := resized[recorded.Name]
+		if !ok || recorded.Init {
+			decision.Containers = append(decision.Containers, recorded)
+			continue
+		}
+		entry := ContainerDecision{
+			Name:       container.Name,
+			Original:   *container.Resources.DeepCopy(),
+			Requests:   container.Resources.Requests.DeepCopy(),
+			LimitsHash: limitsHash(container.Resources.Limits),
+		}
+		if recomputed(container, previous, decision.CpuOvercommit, decision.MemoryOvercommit) {
+			entry.Original.Requests = recorded.Original.Requests.DeepCopy()
+			entry.Pass = decision.Pass
+			target := []corev1.Container{*container.DeepCopy()}
+			target[0].Resources.Requests = recorded.Original.Requests.DeepCopy()
+			mutateContainers(target, decision.CpuOvercommit, decision.MemoryOvercommit)
+		}
+		decision.Containers = append(decision.Containers, entry)
+	}
+
+	setOvercommitAnnotation(pod, overcommitClass.Name, decision.CpuOvercommit, decision.MemoryOvercommit)
+	return true, setDecisionAnnotation(pod, decision)
+}
+
+// recomputed reports whether the requests of a container are the ones computed with the ratios from the
+// original requests recorded by the decision and its current limits.
+func recomputed(container corev1.Container, decision *Decision, cpuValue, memoryValue float64) bool {
+	recorded := decision.container(container.Name, false)
+	if recorded == nil {
+		return false
+	}
+	target := []corev1.Container{*container.DeepCopy()}
+	target[0].Resources.Requests = recorded.Original.Requests.DeepCopy()
+	mutateContainers(target, cpuValue, memoryValue)
+	return resourceListsEqual(target[0].Resources.Requests, container.Resources.Requests)
+}
@@ -78,7 +78,6 @@ func TestAPIs(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
-	os.Setenv("OVERCOMMIT_CLASS_NAME", "test-class")
 	os.Setenv("LABEL_OVERCOMMIT_CLASS", "inditex.com/overcommit-class")
 
 	By("bootstrapping test environment")
@@ -140,7 +139,6 @@ var _ = BeforeSuite(func() {
 })
 
 var _ = AfterSuite(func() {
-	os.Unsetenv("OVERCOMMIT_CLASS_NAME")
 	os.Unsetenv("LABEL_OVERCOMMIT_CLASS")
 	By("tearing down the test environment")
 	err := testEnv.Stop()